@@ -56,6 +56,9 @@ type Program struct {
 	pos        int
 	File       *gotoken.File
 	Statements []Statement
+	// Data holds the input following the `__END__` line, or nil if the
+	// program has no such line
+	Data *string
 }
 
 // Pos returns the position of first character belonging to the node
@@ -165,24 +168,47 @@ func (bs *BlockStatement) String() string {
 
 // ExceptionHandlingBlock represents a begin/end block where exceptions are rescued
 type ExceptionHandlingBlock struct {
-	BeginToken token.Token
-	EndToken   token.Token
+	BeginToken token.Token // begin, or rescue for the modifier form
+	EndToken   token.Token // end, or token.ILLEGAL for the modifier form
 	TryBody    *BlockStatement
 	Rescues    []*RescueBlock
+	Else       *BlockStatement // nil if there is no else clause
+	Ensure     *BlockStatement // nil if there is no ensure clause
+}
+
+// IsModifier indicates if the block is in the modifier form, i.e. `x rescue y`
+func (eh *ExceptionHandlingBlock) IsModifier() bool {
+	return eh.EndToken.Type == token.ILLEGAL
 }
 
 func (eh *ExceptionHandlingBlock) expressionNode() {}
 
 // Pos returns the position of first character belonging to the node
-func (eh *ExceptionHandlingBlock) Pos() int { return eh.BeginToken.Pos }
+func (eh *ExceptionHandlingBlock) Pos() int {
+	if eh.IsModifier() {
+		return eh.TryBody.Pos()
+	}
+	return eh.BeginToken.Pos
+}
 
 // End returns the position of first character immediately after the node
-func (eh *ExceptionHandlingBlock) End() int { return eh.EndToken.Pos }
+func (eh *ExceptionHandlingBlock) End() int {
+	if eh.IsModifier() {
+		return eh.Rescues[0].End()
+	}
+	return eh.EndToken.Pos
+}
 
 // TokenLiteral returns the token literal from 'begin'
 func (eh *ExceptionHandlingBlock) TokenLiteral() string { return eh.BeginToken.Literal }
 func (eh *ExceptionHandlingBlock) String() string {
 	var out bytes.Buffer
+	if eh.IsModifier() {
+		out.WriteString(eh.TryBody.String())
+		out.WriteString(" rescue ")
+		out.WriteString(eh.Rescues[0].Body.String())
+		return out.String()
+	}
 	out.WriteString(eh.BeginToken.Literal)
 	out.WriteString("\n")
 	out.WriteString(eh.TryBody.String())
@@ -190,6 +216,16 @@ func (eh *ExceptionHandlingBlock) String() string {
 	for _, r := range eh.Rescues {
 		out.WriteString(r.String())
 	}
+	if eh.Else != nil {
+		out.WriteString("else\n")
+		out.WriteString(eh.Else.String())
+		out.WriteString("\n")
+	}
+	if eh.Ensure != nil {
+		out.WriteString("ensure\n")
+		out.WriteString(eh.Ensure.String())
+		out.WriteString("\n")
+	}
 	out.WriteString("end")
 	return out.String()
 }
@@ -220,6 +256,7 @@ func (rb *RescueBlock) String() string {
 		for i, c := range rb.ExceptionClasses {
 			classes[i] = c.String()
 		}
+		out.WriteString(" ")
 		out.WriteString(strings.Join(classes, ", "))
 	}
 	if rb.Exception != nil {
@@ -242,10 +279,21 @@ type Assignment struct {
 func (a *Assignment) String() string {
 	var out bytes.Buffer
 	out.WriteString(encloseInParensIfNeeded(a.Left))
-	out.WriteString(" = ")
+	if a.IsConditional() {
+		out.WriteString(" " + a.Token.Literal + " ")
+	} else {
+		out.WriteString(" = ")
+	}
 	out.WriteString(encloseInParensIfNeeded(a.Right))
 	return out.String()
 }
+
+// IsConditional returns true for `||=` and `&&=`, which only assign Right
+// if the current value of Left is falsey or truthy respectively
+func (a *Assignment) IsConditional() bool {
+	return a.Token.Type == token.LOGICALORASSIGN || a.Token.Type == token.LOGICALANDASSIGN
+}
+
 func (a *Assignment) expressionNode() {}
 
 // Pos returns the position of first character belonging to the node
@@ -367,6 +415,53 @@ func (y *YieldExpression) End() int {
 // TokenLiteral returns the literal of the token.YIELD token
 func (y *YieldExpression) TokenLiteral() string { return y.Token.Literal }
 
+// SuperExpression represents a call of the method overridden by the current method
+type SuperExpression struct {
+	Token     token.Token  // the token.SUPER token
+	Arguments []Expression // The arguments to super
+	Block     *BlockExpression
+	// Implicit indicates a bare super without arguments and parens, which
+	// passes on the arguments of the current method
+	Implicit bool
+}
+
+func (s *SuperExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString(s.Token.Literal)
+	if !s.Implicit {
+		args := []string{}
+		for _, a := range s.Arguments {
+			args = append(args, a.String())
+		}
+		out.WriteString("(")
+		out.WriteString(strings.Join(args, ", "))
+		out.WriteString(")")
+	}
+	if s.Block != nil {
+		out.WriteString(" ")
+		out.WriteString(s.Block.String())
+	}
+	return out.String()
+}
+func (s *SuperExpression) expressionNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (s *SuperExpression) Pos() int { return s.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (s *SuperExpression) End() int {
+	if s.Block != nil {
+		return s.Block.End()
+	}
+	if len(s.Arguments) == 0 {
+		return s.Pos() + 5
+	}
+	return s.Arguments[len(s.Arguments)-1].End()
+}
+
+// TokenLiteral returns the literal of the token.SUPER token
+func (s *SuperExpression) TokenLiteral() string { return s.Token.Literal }
+
 // Keyword__FILE__ represents __FILE__ in the AST
 type Keyword__FILE__ struct {
 	Token    token.Token // the token.FILE__ token
@@ -386,6 +481,68 @@ func (f *Keyword__FILE__) End() int { return f.Token.Pos + 8 }
 // TokenLiteral returns the literal of the token.FILE__ token
 func (f *Keyword__FILE__) TokenLiteral() string { return f.Token.Literal }
 
+// Keyword__LINE__ represents __LINE__ in the AST
+type Keyword__LINE__ struct {
+	Token token.Token // the token.KEYWORD__LINE__ token
+	Line  int
+}
+
+func (l *Keyword__LINE__) String() string  { return l.Token.Literal }
+func (l *Keyword__LINE__) expressionNode() {}
+func (l *Keyword__LINE__) literalNode()    {}
+
+// Pos returns the position of first character belonging to the node
+func (l *Keyword__LINE__) Pos() int { return l.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (l *Keyword__LINE__) End() int { return l.Token.Pos + 8 }
+
+// TokenLiteral returns the literal of the token.KEYWORD__LINE__ token
+func (l *Keyword__LINE__) TokenLiteral() string { return l.Token.Literal }
+
+// Keyword__DIR__ represents __dir__ in the AST
+type Keyword__DIR__ struct {
+	Token    token.Token // the token.KEYWORD__DIR__ token
+	Filename string
+}
+
+func (d *Keyword__DIR__) String() string  { return d.Token.Literal }
+func (d *Keyword__DIR__) expressionNode() {}
+func (d *Keyword__DIR__) literalNode()    {}
+
+// Pos returns the position of first character belonging to the node
+func (d *Keyword__DIR__) Pos() int { return d.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (d *Keyword__DIR__) End() int { return d.Token.Pos + 7 }
+
+// TokenLiteral returns the literal of the token.KEYWORD__DIR__ token
+func (d *Keyword__DIR__) TokenLiteral() string { return d.Token.Literal }
+
+// A ProgramHook represents a BEGIN or END block, which runs before or after
+// the rest of the program
+type ProgramHook struct {
+	Token token.Token // the token.KEYWORD_BEGIN or token.KEYWORD_END
+	Body  *BlockStatement
+}
+
+func (ph *ProgramHook) statementNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (ph *ProgramHook) Pos() int { return ph.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (ph *ProgramHook) End() int { return ph.Body.End() + 1 }
+
+// TokenLiteral returns the literal of the token.KEYWORD_BEGIN or token.KEYWORD_END token
+func (ph *ProgramHook) TokenLiteral() string { return ph.Token.Literal }
+func (ph *ProgramHook) String() string {
+	return ph.Token.Literal + " { " + ph.Body.String() + " }"
+}
+
+// IsBegin reports whether ph is a BEGIN block
+func (ph *ProgramHook) IsBegin() bool { return ph.Token.Type == token.KEYWORD_BEGIN }
+
 // An Identifier represents an identifier in the program
 type Identifier struct {
 	Token token.Token // the token.IDENT token
@@ -472,6 +629,25 @@ func (il *IntegerLiteral) End() int { return il.Token.Pos + len(fmt.Sprintf("%d"
 func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Literal }
 func (il *IntegerLiteral) String() string       { return fmt.Sprintf("%d", il.Value) }
 
+// FloatLiteral represents a float in the AST
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+func (fl *FloatLiteral) expressionNode() {}
+func (fl *FloatLiteral) literalNode()    {}
+
+// Pos returns the position of first character belonging to the node
+func (fl *FloatLiteral) Pos() int { return fl.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (fl *FloatLiteral) End() int { return fl.Token.Pos + len(fl.Token.Literal) }
+
+// TokenLiteral returns the literal from the token.FLOAT token
+func (fl *FloatLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FloatLiteral) String() string       { return fl.Token.Literal }
+
 // Nil represents the 'nil' keyword
 type Nil struct {
 	Token token.Token
@@ -528,6 +704,71 @@ func (sl *StringLiteral) End() int { return sl.Token.Pos + len(sl.Value) }
 func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
 func (sl *StringLiteral) String() string       { return sl.Value }
 
+// InterpolatedString represents a double quoted string with embedded
+// expressions in the AST
+type InterpolatedString struct {
+	Token token.Token // the first string token
+	Parts []Expression
+}
+
+func (is *InterpolatedString) expressionNode() {}
+func (is *InterpolatedString) literalNode()    {}
+
+// Pos returns the position of first character belonging to the node
+func (is *InterpolatedString) Pos() int { return is.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (is *InterpolatedString) End() int {
+	if len(is.Parts) == 0 {
+		return is.Token.Pos
+	}
+	return is.Parts[len(is.Parts)-1].End()
+}
+
+// TokenLiteral returns the literal from the first token.STRING
+func (is *InterpolatedString) TokenLiteral() string { return is.Token.Literal }
+func (is *InterpolatedString) String() string {
+	var out bytes.Buffer
+	out.WriteString("\"")
+	for _, part := range is.Parts {
+		if str, ok := part.(*StringLiteral); ok {
+			out.WriteString(str.Value)
+			continue
+		}
+		out.WriteString("#{")
+		out.WriteString(part.String())
+		out.WriteString("}")
+	}
+	out.WriteString("\"")
+	return out.String()
+}
+
+// Interpolation represents the statements embedded with #{...} in an
+// InterpolatedString
+type Interpolation struct {
+	Token    token.Token // the #{ token
+	Body     *BlockStatement
+	EndToken token.Token // the } token
+}
+
+func (i *Interpolation) expressionNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (i *Interpolation) Pos() int { return i.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (i *Interpolation) End() int { return i.EndToken.Pos }
+
+// TokenLiteral returns the literal from the #{ token
+func (i *Interpolation) TokenLiteral() string { return i.Token.Literal }
+func (i *Interpolation) String() string {
+	statements := make([]string, 0, len(i.Body.Statements))
+	for _, s := range i.Body.Statements {
+		statements = append(statements, s.String())
+	}
+	return strings.Join(statements, "; ")
+}
+
 // Comment represents a double quoted string in the AST
 type Comment struct {
 	Token token.Token // the #
@@ -580,6 +821,23 @@ func (ce *ConditionalExpression) IsNegated() bool {
 	return ce.Token.Type == token.UNLESS
 }
 
+// Elsif returns the conditional of an elsif branch following the consequence,
+// or nil if there is none
+func (ce *ConditionalExpression) Elsif() *ConditionalExpression {
+	if ce.Alternative == nil || len(ce.Alternative.Statements) != 1 {
+		return nil
+	}
+	stmt, ok := ce.Alternative.Statements[0].(*ExpressionStatement)
+	if !ok {
+		return nil
+	}
+	elsif, ok := stmt.Expression.(*ConditionalExpression)
+	if !ok || elsif.Token.Type != token.ELSIF {
+		return nil
+	}
+	return elsif
+}
+
 func (ce *ConditionalExpression) expressionNode() {}
 
 // Pos returns the position of first character belonging to the node
@@ -603,42 +861,544 @@ func (ce *ConditionalExpression) TokenLiteral() string { return ce.Token.Literal
 func (ce *ConditionalExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString(ce.Token.Literal)
+	out.WriteString(" ")
 	out.WriteString(ce.Condition.String())
 	out.WriteString(" ")
 	out.WriteString(ce.Consequence.String())
-	if ce.Alternative != nil {
-		out.WriteString("else ")
+	if elsif := ce.Elsif(); elsif != nil {
+		out.WriteString(" ")
+		out.WriteString(elsif.String())
+	} else if ce.Alternative != nil {
+		out.WriteString(" else ")
 		out.WriteString(ce.Alternative.String())
 	}
+	if ce.Token.Type != token.ELSIF {
+		out.WriteString(" end")
+	}
+	return out.String()
+}
+
+// CaseExpression represents a case expression within the AST
+type CaseExpression struct {
+	Token    token.Token // The 'case' token
+	EndToken token.Token // The 'end' token
+	Subject  Expression  // nil for a case without subject
+	Whens    []*WhenClause
+	Else     *BlockStatement
+}
+
+func (ce *CaseExpression) expressionNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (ce *CaseExpression) Pos() int { return ce.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (ce *CaseExpression) End() int { return ce.EndToken.Pos }
+
+// TokenLiteral returns the literal from token token.CASE
+func (ce *CaseExpression) TokenLiteral() string { return ce.Token.Literal }
+func (ce *CaseExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("case")
+	if ce.Subject != nil {
+		out.WriteString(" ")
+		out.WriteString(ce.Subject.String())
+	}
+	for _, w := range ce.Whens {
+		out.WriteString(" ")
+		out.WriteString(w.String())
+	}
+	if ce.Else != nil {
+		out.WriteString(" else ")
+		out.WriteString(ce.Else.String())
+	}
 	out.WriteString(" end")
 	return out.String()
 }
 
+// A WhenClause represents a single when branch of a case expression
+type WhenClause struct {
+	Token       token.Token // The 'when' token
+	Conditions  []Expression
+	Consequence *BlockStatement
+}
+
+func (wc *WhenClause) expressionNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (wc *WhenClause) Pos() int { return wc.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (wc *WhenClause) End() int { return wc.Consequence.End() }
+
+// TokenLiteral returns the literal from token token.WHEN
+func (wc *WhenClause) TokenLiteral() string { return wc.Token.Literal }
+func (wc *WhenClause) String() string {
+	var out bytes.Buffer
+	conditions := make([]string, len(wc.Conditions))
+	for i, c := range wc.Conditions {
+		conditions[i] = c.String()
+	}
+	out.WriteString("when ")
+	out.WriteString(strings.Join(conditions, ", "))
+	out.WriteString(" then ")
+	out.WriteString(wc.Consequence.String())
+	return out.String()
+}
+
+// A CaseMatchExpression represents a case expression matching its subject
+// against patterns, i.e. `case subject in pattern then ... end`
+type CaseMatchExpression struct {
+	Token    token.Token // The 'case' token
+	EndToken token.Token // The 'end' token
+	Subject  Expression
+	Ins      []*InClause
+	Else     *BlockStatement // nil raises a NoMatchingPatternError if no pattern matches
+}
+
+func (ce *CaseMatchExpression) expressionNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (ce *CaseMatchExpression) Pos() int { return ce.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (ce *CaseMatchExpression) End() int { return ce.EndToken.Pos }
+
+// TokenLiteral returns the literal from token token.CASE
+func (ce *CaseMatchExpression) TokenLiteral() string { return ce.Token.Literal }
+func (ce *CaseMatchExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("case ")
+	out.WriteString(ce.Subject.String())
+	for _, in := range ce.Ins {
+		out.WriteString(" ")
+		out.WriteString(in.String())
+	}
+	if ce.Else != nil {
+		out.WriteString(" else ")
+		out.WriteString(ce.Else.String())
+	}
+	out.WriteString(" end")
+	return out.String()
+}
+
+// An InClause represents a single in branch of a case expression
+type InClause struct {
+	Token       token.Token // The 'in' token
+	Pattern     Pattern
+	GuardToken  token.Token // The 'if' or 'unless' token of the guard
+	Guard       Expression  // nil for a branch without guard
+	Consequence *BlockStatement
+}
+
+func (ic *InClause) expressionNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (ic *InClause) Pos() int { return ic.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (ic *InClause) End() int { return ic.Consequence.End() }
+
+// TokenLiteral returns the literal from token token.IN
+func (ic *InClause) TokenLiteral() string { return ic.Token.Literal }
+func (ic *InClause) String() string {
+	var out bytes.Buffer
+	out.WriteString("in ")
+	out.WriteString(ic.Pattern.String())
+	if ic.Guard != nil {
+		out.WriteString(" " + ic.GuardToken.Literal + " ")
+		out.WriteString(ic.Guard.String())
+	}
+	out.WriteString(" then ")
+	out.WriteString(ic.Consequence.String())
+	return out.String()
+}
+
+// IsUnlessGuard reports whether the guard is given with `unless`
+func (ic *InClause) IsUnlessGuard() bool {
+	return ic.GuardToken.Type == token.UNLESS
+}
+
+// A PatternMatchExpression represents a standalone pattern match, i.e.
+// `value => pattern` or `value in pattern`
+type PatternMatchExpression struct {
+	Token   token.Token // The '=>' or 'in' token
+	Value   Expression
+	Pattern Pattern
+}
+
+func (pm *PatternMatchExpression) expressionNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (pm *PatternMatchExpression) Pos() int { return pm.Value.Pos() }
+
+// End returns the position of first character immediately after the node
+func (pm *PatternMatchExpression) End() int { return pm.Pattern.End() }
+
+// TokenLiteral returns the literal from the '=>' or 'in' token
+func (pm *PatternMatchExpression) TokenLiteral() string { return pm.Token.Literal }
+func (pm *PatternMatchExpression) String() string {
+	return pm.Value.String() + " " + pm.Token.Literal + " " + pm.Pattern.String()
+}
+
+// IsTest reports whether the match returns whether the pattern matched,
+// i.e. `value in pattern`, instead of raising an error if it does not.
+func (pm *PatternMatchExpression) IsTest() bool {
+	return pm.Token.Type == token.IN
+}
+
+// A Pattern represents a pattern matched against a value within a
+// CaseMatchExpression or a PatternMatchExpression
+//
+// All pattern nodes implement the Pattern interface.
+type Pattern interface {
+	Node
+	patternNode()
+}
+
+// A ValuePattern represents a pattern matching any value which the
+// expression is case equal to, e.g. a literal, a range or a class
+type ValuePattern struct {
+	Value Expression
+}
+
+func (vp *ValuePattern) patternNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (vp *ValuePattern) Pos() int { return vp.Value.Pos() }
+
+// End returns the position of first character immediately after the node
+func (vp *ValuePattern) End() int { return vp.Value.End() }
+
+// TokenLiteral returns the literal of the value
+func (vp *ValuePattern) TokenLiteral() string { return vp.Value.TokenLiteral() }
+func (vp *ValuePattern) String() string       { return vp.Value.String() }
+
+// A PinPattern represents a pattern matching the value of an expression
+// evaluated when matching, e.g. `^x` or `^(x + 1)`
+type PinPattern struct {
+	Token token.Token // The '^' token
+	Value Expression
+}
+
+func (pp *PinPattern) patternNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (pp *PinPattern) Pos() int { return pp.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (pp *PinPattern) End() int { return pp.Value.End() }
+
+// TokenLiteral returns the literal from token token.CARET
+func (pp *PinPattern) TokenLiteral() string { return pp.Token.Literal }
+func (pp *PinPattern) String() string       { return "^" + pp.Value.String() }
+
+// A VariablePattern represents a pattern matching any value and binding it
+// to a local variable
+type VariablePattern struct {
+	Name *Identifier
+}
+
+func (vp *VariablePattern) patternNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (vp *VariablePattern) Pos() int { return vp.Name.Pos() }
+
+// End returns the position of first character immediately after the node
+func (vp *VariablePattern) End() int { return vp.Name.End() }
+
+// TokenLiteral returns the literal from token token.IDENT
+func (vp *VariablePattern) TokenLiteral() string { return vp.Name.TokenLiteral() }
+func (vp *VariablePattern) String() string       { return vp.Name.String() }
+
+// An AlternativePattern represents a pattern matching if any of its
+// alternatives match, e.g. `1 | 2`
+type AlternativePattern struct {
+	Alternatives []Pattern
+}
+
+func (ap *AlternativePattern) patternNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (ap *AlternativePattern) Pos() int { return ap.Alternatives[0].Pos() }
+
+// End returns the position of first character immediately after the node
+func (ap *AlternativePattern) End() int {
+	return ap.Alternatives[len(ap.Alternatives)-1].End()
+}
+
+// TokenLiteral returns the literal of the first alternative
+func (ap *AlternativePattern) TokenLiteral() string { return ap.Alternatives[0].TokenLiteral() }
+func (ap *AlternativePattern) String() string {
+	alternatives := make([]string, len(ap.Alternatives))
+	for i, a := range ap.Alternatives {
+		alternatives[i] = a.String()
+	}
+	return strings.Join(alternatives, " | ")
+}
+
+// A CapturePattern represents a pattern binding the value matched by
+// Pattern to a local variable, e.g. `Integer => n`
+type CapturePattern struct {
+	Token   token.Token // The '=>' token
+	Pattern Pattern
+	Name    *Identifier
+}
+
+func (cp *CapturePattern) patternNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (cp *CapturePattern) Pos() int { return cp.Pattern.Pos() }
+
+// End returns the position of first character immediately after the node
+func (cp *CapturePattern) End() int { return cp.Name.End() }
+
+// TokenLiteral returns the literal from token token.HASHROCKET
+func (cp *CapturePattern) TokenLiteral() string { return cp.Token.Literal }
+func (cp *CapturePattern) String() string {
+	return cp.Pattern.String() + " => " + cp.Name.String()
+}
+
+// A SplatPattern represents the rest of an array or hash pattern, e.g.
+// `*rest`, `*` or `**rest`. The rest is not bound if Name is nil.
+type SplatPattern struct {
+	Token token.Token // The '*' or '**' token
+	Name  *Identifier
+}
+
+func (sp *SplatPattern) patternNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (sp *SplatPattern) Pos() int { return sp.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (sp *SplatPattern) End() int {
+	if sp.Name == nil {
+		return sp.Token.Pos + len(sp.Token.Literal)
+	}
+	return sp.Name.End()
+}
+
+// TokenLiteral returns the literal from the '*' or '**' token
+func (sp *SplatPattern) TokenLiteral() string { return sp.Token.Literal }
+func (sp *SplatPattern) String() string {
+	if sp.Name == nil {
+		return sp.Token.Literal
+	}
+	return sp.Token.Literal + sp.Name.String()
+}
+
+// An ArrayPattern represents a pattern matching the elements of an array,
+// e.g. `[Integer, *rest]` or `Point[x, y]`
+type ArrayPattern struct {
+	Token    token.Token // The first token of the pattern
+	EndToken token.Token // The closing bracket, or token.ILLEGAL if there is none
+	Constant Expression  // nil for a pattern without class constraint
+	Pre      []Pattern
+	Rest     *SplatPattern // nil for a pattern matching arrays of a fixed size
+	Post     []Pattern
+}
+
+func (ap *ArrayPattern) patternNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (ap *ArrayPattern) Pos() int { return ap.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (ap *ArrayPattern) End() int {
+	if ap.EndToken.Type != token.ILLEGAL {
+		return ap.EndToken.Pos
+	}
+	elements := ap.Elements()
+	if len(elements) == 0 {
+		return ap.Token.Pos
+	}
+	return elements[len(elements)-1].End()
+}
+
+// TokenLiteral returns the literal of the first token of the pattern
+func (ap *ArrayPattern) TokenLiteral() string { return ap.Token.Literal }
+func (ap *ArrayPattern) String() string {
+	return constantPatternString(ap.Constant) + "[" + patternListString(ap.Elements()) + "]"
+}
+
+// Elements returns all patterns of the array pattern in order
+func (ap *ArrayPattern) Elements() []Pattern {
+	elements := append([]Pattern{}, ap.Pre...)
+	if ap.Rest != nil {
+		elements = append(elements, ap.Rest)
+	}
+	return append(elements, ap.Post...)
+}
+
+// A FindPattern represents a pattern searching an array for consecutive
+// elements, e.g. `[*, Integer => x, *]`
+type FindPattern struct {
+	Token    token.Token // The first token of the pattern
+	EndToken token.Token // The closing bracket, or token.ILLEGAL if there is none
+	Constant Expression  // nil for a pattern without class constraint
+	Pre      *SplatPattern
+	Patterns []Pattern
+	Post     *SplatPattern
+}
+
+func (fp *FindPattern) patternNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (fp *FindPattern) Pos() int { return fp.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (fp *FindPattern) End() int {
+	if fp.EndToken.Type != token.ILLEGAL {
+		return fp.EndToken.Pos
+	}
+	return fp.Post.End()
+}
+
+// TokenLiteral returns the literal of the first token of the pattern
+func (fp *FindPattern) TokenLiteral() string { return fp.Token.Literal }
+func (fp *FindPattern) String() string {
+	elements := append(append([]Pattern{fp.Pre}, fp.Patterns...), fp.Post)
+	return constantPatternString(fp.Constant) + "[" + patternListString(elements) + "]"
+}
+
+// A HashPatternPair represents a key of a hash pattern along with the
+// pattern its value must match. A nil Value binds the value to a local
+// variable named like the key, i.e. `{name:}`.
+type HashPatternPair struct {
+	Key   *Identifier
+	Value Pattern
+}
+
+func (hp *HashPatternPair) String() string {
+	if hp.Value == nil {
+		return hp.Key.Value + ":"
+	}
+	return hp.Key.Value + ": " + hp.Value.String()
+}
+
+// A HashPattern represents a pattern matching the values of a hash, e.g.
+// `{name: String => name, **rest}`
+type HashPattern struct {
+	Token    token.Token // The first token of the pattern
+	EndToken token.Token // The closing brace, or token.ILLEGAL if there is none
+	Constant Expression  // nil for a pattern without class constraint
+	Pairs    []*HashPatternPair
+	Rest     *SplatPattern // nil if other keys are allowed but not bound
+	// NoRest marks a pattern forbidding other keys, i.e. `**nil`
+	NoRest bool
+}
+
+func (hp *HashPattern) patternNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (hp *HashPattern) Pos() int { return hp.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (hp *HashPattern) End() int {
+	if hp.EndToken.Type != token.ILLEGAL {
+		return hp.EndToken.Pos
+	}
+	if hp.Rest != nil {
+		return hp.Rest.End()
+	}
+	if len(hp.Pairs) == 0 {
+		return hp.Token.Pos
+	}
+	last := hp.Pairs[len(hp.Pairs)-1]
+	if last.Value != nil {
+		return last.Value.End()
+	}
+	return last.Key.End() + 1
+}
+
+// TokenLiteral returns the literal of the first token of the pattern
+func (hp *HashPattern) TokenLiteral() string { return hp.Token.Literal }
+func (hp *HashPattern) String() string {
+	elements := []string{}
+	for _, pair := range hp.Pairs {
+		elements = append(elements, pair.String())
+	}
+	if hp.Rest != nil {
+		elements = append(elements, hp.Rest.String())
+	}
+	if hp.NoRest {
+		elements = append(elements, "**nil")
+	}
+	if hp.Constant != nil {
+		return hp.Constant.String() + "(" + strings.Join(elements, ", ") + ")"
+	}
+	return "{" + strings.Join(elements, ", ") + "}"
+}
+
+func constantPatternString(constant Expression) string {
+	if constant == nil {
+		return ""
+	}
+	return constant.String()
+}
+
+func patternListString(patterns []Pattern) string {
+	elements := make([]string, len(patterns))
+	for i, p := range patterns {
+		elements[i] = p.String()
+	}
+	return strings.Join(elements, ", ")
+}
+
 // A LoopExpression represents a loop
 type LoopExpression struct {
-	Token     token.Token // while
-	EndToken  token.Token // end
+	Token     token.Token // while or until
+	EndToken  token.Token // end, or token.ILLEGAL for the modifier form
 	Condition Expression
 	Block     *BlockStatement
+	// DoWhile indicates that the block is evaluated once before the
+	// condition is checked, i.e. `begin ... end while cond`
+	DoWhile bool
+}
+
+// IsNegated indicates if the condition uses until, i.e. is negated
+func (ce *LoopExpression) IsNegated() bool {
+	return ce.Token.Type == token.UNTIL
+}
+
+// IsModifier indicates if the loop is in the modifier form, i.e. `x while cond`
+func (ce *LoopExpression) IsModifier() bool {
+	return ce.EndToken.Type == token.ILLEGAL
 }
 
 func (ce *LoopExpression) expressionNode() {}
 
 // Pos returns the position of first character belonging to the node
 func (ce *LoopExpression) Pos() int {
+	if ce.IsModifier() {
+		return ce.Block.Pos()
+	}
 	return ce.Token.Pos
 }
 
 // End returns the position of first character immediately after the node
 func (ce *LoopExpression) End() int {
+	if ce.IsModifier() {
+		return ce.Condition.End()
+	}
 	return ce.EndToken.Pos
 }
 
-// TokenLiteral returns the literal from token token.WHILE
+// TokenLiteral returns the literal from token token.WHILE or token.UNTIL
 func (ce *LoopExpression) TokenLiteral() string { return ce.Token.Literal }
 func (ce *LoopExpression) String() string {
 	var out bytes.Buffer
+	if ce.IsModifier() {
+		out.WriteString(ce.Block.String())
+		out.WriteString(" ")
+		out.WriteString(ce.Token.Literal)
+		out.WriteString(" ")
+		out.WriteString(ce.Condition.String())
+		return out.String()
+	}
 	out.WriteString(ce.Token.Literal)
+	out.WriteString(" ")
 	out.WriteString(ce.Condition.String())
 	out.WriteString(" do ")
 	out.WriteString(ce.Block.String())
@@ -646,6 +1406,79 @@ func (ce *LoopExpression) String() string {
 	return out.String()
 }
 
+// A JumpExpression represents a break, next, redo or retry
+type JumpExpression struct {
+	Token token.Token // break, next, redo or retry
+	Value Expression  // nil if no value is given
+}
+
+func (je *JumpExpression) expressionNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (je *JumpExpression) Pos() int { return je.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (je *JumpExpression) End() int {
+	if je.Value != nil {
+		return je.Value.End()
+	}
+	return je.Token.Pos + len(je.Token.Literal)
+}
+
+// TokenLiteral returns the literal from token token.BREAK, token.NEXT, token.REDO or token.RETRY
+func (je *JumpExpression) TokenLiteral() string { return je.Token.Literal }
+func (je *JumpExpression) String() string {
+	if je.Value == nil {
+		return je.Token.Literal
+	}
+	return je.Token.Literal + " " + je.Value.String()
+}
+
+// An AliasExpression represents an alias of a method or a global variable
+type AliasExpression struct {
+	Token token.Token // the token.ALIAS
+	New   Expression  // an *Identifier or a *Global
+	Old   Expression  // an *Identifier or a *Global
+}
+
+func (ae *AliasExpression) expressionNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (ae *AliasExpression) Pos() int { return ae.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (ae *AliasExpression) End() int { return ae.Old.End() }
+
+// TokenLiteral returns the literal from token token.ALIAS
+func (ae *AliasExpression) TokenLiteral() string { return ae.Token.Literal }
+func (ae *AliasExpression) String() string {
+	return ae.Token.Literal + " " + ae.New.String() + " " + ae.Old.String()
+}
+
+// An UndefExpression represents the undefinition of one or more methods
+type UndefExpression struct {
+	Token   token.Token // the token.UNDEF
+	Methods []*Identifier
+}
+
+func (ue *UndefExpression) expressionNode() {}
+
+// Pos returns the position of first character belonging to the node
+func (ue *UndefExpression) Pos() int { return ue.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (ue *UndefExpression) End() int { return ue.Methods[len(ue.Methods)-1].End() }
+
+// TokenLiteral returns the literal from token token.UNDEF
+func (ue *UndefExpression) TokenLiteral() string { return ue.Token.Literal }
+func (ue *UndefExpression) String() string {
+	names := make([]string, len(ue.Methods))
+	for i, method := range ue.Methods {
+		names[i] = method.String()
+	}
+	return ue.Token.Literal + " " + strings.Join(names, ", ")
+}
+
 // ExpressionList represents a list of expressions within the AST divided by commas
 type ExpressionList []Expression
 
@@ -741,16 +1574,55 @@ func (hl *HashLiteral) String() string {
 	for key, val := range hl.Map {
 		elements = append(elements, fmt.Sprintf("%q => %q", key.String(), val.String()))
 	}
+	if hl.IsBraceless() {
+		out.WriteString(strings.Join(elements, ", "))
+		return out.String()
+	}
 	out.WriteString("{")
 	out.WriteString(strings.Join(elements, ", "))
 	out.WriteString("}")
 	return out.String()
 }
 
-// A BlockCapture represents a function scoped variable capturing a block
+// IsBraceless reports whether the hash is a keyword argument given without
+// braces, i.e. `foo(key: value)`
+func (hl *HashLiteral) IsBraceless() bool {
+	return hl.Token.Type == token.LABEL
+}
+
+// A Splat represents a splatted argument, i.e. `*args` or `**opts`
+type Splat struct {
+	Token token.Token // the `*` or `**`
+	Value Expression
+}
+
+func (s *Splat) expressionNode() {}
+
+// Pos returns the position of the asterisk
+func (s *Splat) Pos() int { return s.Token.Pos }
+
+// End returns the position of the last character of Value
+func (s *Splat) End() int { return s.Value.End() }
+
+// TokenLiteral returns the literal of the token
+func (s *Splat) TokenLiteral() string { return s.Token.Literal }
+func (s *Splat) String() string {
+	return s.Token.Literal + s.Value.String()
+}
+
+// IsDoubleSplat reports whether the splat uses `**`, i.e. expands a hash
+// into keyword arguments
+func (s *Splat) IsDoubleSplat() bool {
+	return s.Token.Type == token.POW
+}
+
+// A BlockCapture represents a function scoped variable capturing a block.
+// Within call arguments it passes a block, which is taken from Value if the
+// passed expression is not a plain identifier.
 type BlockCapture struct {
 	Token token.Token // the `&`
 	Name  *Identifier
+	Value Expression
 }
 
 func (b *BlockCapture) expressionNode() {}
@@ -759,10 +1631,19 @@ func (b *BlockCapture) literalNode()    {}
 // Pos returns the position of the ampersand
 func (b *BlockCapture) Pos() int { return b.Token.Pos }
 
-// End returns the position of the last character of Name
-func (b *BlockCapture) End() int { return b.Name.End() }
+// End returns the position of the last character of Name or Value
+func (b *BlockCapture) End() int { return b.Block().End() }
 func (b *BlockCapture) String() string {
-	return "&" + b.Name.Value
+	return "&" + b.Block().String()
+}
+
+// Block returns the expression the block is passed from, i.e. Value if set
+// and Name otherwise
+func (b *BlockCapture) Block() Expression {
+	if b.Value != nil {
+		return b.Value
+	}
+	return b.Name
 }
 
 // TokenLiteral returns the literal of the token
@@ -778,6 +1659,8 @@ type FunctionLiteral struct {
 	CapturedBlock *BlockCapture
 	Body          *BlockStatement
 	Rescues       []*RescueBlock
+	Else          *BlockStatement // nil if there is no else clause
+	Ensure        *BlockStatement // nil if there is no ensure clause
 }
 
 func (fl *FunctionLiteral) expressionNode() {}
@@ -815,23 +1698,59 @@ func (fl *FunctionLiteral) String() string {
 	for _, r := range fl.Rescues {
 		out.WriteString(r.String())
 	}
+	if fl.Else != nil {
+		out.WriteString("else\n")
+		out.WriteString(fl.Else.String())
+		out.WriteString("\n")
+	}
+	if fl.Ensure != nil {
+		out.WriteString("ensure\n")
+		out.WriteString(fl.Ensure.String())
+		out.WriteString("\n")
+	}
 	out.WriteString(" end")
 	return out.String()
 }
 
+// ParameterKind describes how a FunctionParameter binds the arguments of a call
+type ParameterKind int
+
+// The kinds of function parameters
+const (
+	PositionalParameter    ParameterKind = iota // x or x = 1
+	SplatParameter                              // *rest
+	KeywordParameter                            // key: or key: 1
+	DoubleSplatParameter                        // **opts
+	DestructuringParameter                      // (a, b)
+)
+
 // A FunctionParameter represents a parameter in a function literal
 type FunctionParameter struct {
 	Name    *Identifier
 	Default Expression
+	Kind    ParameterKind
+	// Lparen, Rparen and Parameters are only set for a
+	// DestructuringParameter, which has no Name
+	Lparen     token.Token
+	Rparen     token.Token
+	Parameters []*FunctionParameter
 }
 
 func (f *FunctionParameter) expressionNode() {}
 
 // Pos returns the position of first character belonging to the node
-func (f *FunctionParameter) Pos() int { return f.Name.Pos() }
+func (f *FunctionParameter) Pos() int {
+	if f.Kind == DestructuringParameter {
+		return f.Lparen.Pos
+	}
+	return f.Name.Pos()
+}
 
 // End returns the position of the default end if it exists, otherwise the end position of Name
 func (f *FunctionParameter) End() int {
+	if f.Kind == DestructuringParameter {
+		return f.Rparen.Pos
+	}
 	if f.Default != nil {
 		return f.Default.End()
 	}
@@ -839,10 +1758,35 @@ func (f *FunctionParameter) End() int {
 }
 
 // TokenLiteral returns the token of the parameter name
-func (f *FunctionParameter) TokenLiteral() string { return f.Name.TokenLiteral() }
+func (f *FunctionParameter) TokenLiteral() string {
+	if f.Kind == DestructuringParameter {
+		return f.Lparen.Literal
+	}
+	return f.Name.TokenLiteral()
+}
 func (f *FunctionParameter) String() string {
 	var out bytes.Buffer
+	switch f.Kind {
+	case DestructuringParameter:
+		params := []string{}
+		for _, p := range f.Parameters {
+			params = append(params, p.String())
+		}
+		return "(" + strings.Join(params, ", ") + ")"
+	case SplatParameter:
+		out.WriteString("*")
+	case DoubleSplatParameter:
+		out.WriteString("**")
+	}
 	out.WriteString(f.Name.String())
+	if f.Kind == KeywordParameter {
+		out.WriteString(":")
+		if f.Default != nil {
+			out.WriteString(" ")
+			out.WriteString(encloseInParensIfNeeded(f.Default))
+		}
+		return out.String()
+	}
 	if f.Default != nil {
 		out.WriteString(" = ")
 		out.WriteString(encloseInParensIfNeeded(f.Default))
@@ -916,11 +1860,26 @@ func (ce *ContextCallExpression) End() int {
 
 // TokenLiteral returns the literal from token.DOT
 func (ce *ContextCallExpression) TokenLiteral() string { return ce.Token.Literal }
+
+// IsSafeNavigation returns true if the call was made with `&.`, i.e.
+// `foo&.bar`, which evaluates to nil for a nil receiver
+func (ce *ContextCallExpression) IsSafeNavigation() bool { return ce.Token.Type == token.ANDDOT }
+
+// IsAttribute returns true if the call is an attribute reference without
+// arguments and block, i.e. `foo.bar`, which can be assigned to
+func (ce *ContextCallExpression) IsAttribute() bool {
+	return ce.Context != nil && len(ce.Arguments) == 0 && ce.Block == nil
+}
+
 func (ce *ContextCallExpression) String() string {
 	var out bytes.Buffer
 	if ce.Context != nil {
 		out.WriteString(ce.Context.String())
-		out.WriteString(".")
+		if ce.IsSafeNavigation() {
+			out.WriteString("&.")
+		} else {
+			out.WriteString(".")
+		}
 	}
 	args := []string{}
 	for _, a := range ce.Arguments {
@@ -942,6 +1901,7 @@ type BlockExpression struct {
 	Token      token.Token          // token.DO or token.LBRACE
 	EndToken   token.Token          // token.END or token.RBRACE
 	Parameters []*FunctionParameter // the block parameters
+	Locals     []*Identifier        // the block-local variables, i.e. `|x; y|`
 	Body       *BlockStatement      // the block body
 }
 
@@ -960,13 +1920,21 @@ func (b *BlockExpression) TokenLiteral() string { return b.Token.Literal }
 func (b *BlockExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString(b.Token.Literal)
-	if len(b.Parameters) != 0 {
-		args := []string{}
-		for _, a := range b.Parameters {
-			args = append(args, a.String())
-		}
+	args := []string{}
+	for _, a := range b.Parameters {
+		args = append(args, a.String())
+	}
+	locals := []string{}
+	for _, l := range b.Locals {
+		locals = append(locals, l.String())
+	}
+	if len(args) != 0 || len(locals) != 0 {
 		out.WriteString("|")
 		out.WriteString(strings.Join(args, ", "))
+		if len(locals) != 0 {
+			out.WriteString("; ")
+			out.WriteString(strings.Join(locals, ", "))
+		}
 		out.WriteString("|")
 		out.WriteString("\n")
 	}
@@ -980,6 +1948,38 @@ func (b *BlockExpression) String() string {
 	return out.String()
 }
 
+// A LambdaLiteral represents a lambda defined with `->`, i.e. `->(x) { x }`
+type LambdaLiteral struct {
+	Token token.Token      // the '->'
+	Block *BlockExpression // the lambda parameters and body
+}
+
+func (l *LambdaLiteral) expressionNode() {}
+func (l *LambdaLiteral) literalNode()    {}
+
+// Pos returns the position of the arrow
+func (l *LambdaLiteral) Pos() int { return l.Token.Pos }
+
+// End returns the position of the end of the block
+func (l *LambdaLiteral) End() int { return l.Block.End() }
+
+// TokenLiteral returns the literal of the token
+func (l *LambdaLiteral) TokenLiteral() string { return l.Token.Literal }
+func (l *LambdaLiteral) String() string {
+	var out bytes.Buffer
+	params := []string{}
+	for _, p := range l.Block.Parameters {
+		params = append(params, p.String())
+	}
+	out.WriteString("->(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") ")
+	block := *l.Block
+	block.Parameters = nil
+	out.WriteString(block.String())
+	return out.String()
+}
+
 // ModuleExpression represents a module definition
 type ModuleExpression struct {
 	Token    token.Token // The module keyword
@@ -1068,11 +2068,97 @@ func (pe *PrefixExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString("(")
 	out.WriteString(pe.Operator)
+	if pe.Token.Type == token.KEYWORD_NOT {
+		out.WriteString(" ")
+	}
 	out.WriteString(pe.Right.String())
 	out.WriteString(")")
 	return out.String()
 }
 
+// RegexLiteral represents a regular expression literal in the AST. Pattern is
+// either a *StringLiteral or an *InterpolatedString.
+type RegexLiteral struct {
+	Token   token.Token // the token.REGEXBEG token
+	Pattern Expression
+	Flags   string
+}
+
+func (r *RegexLiteral) expressionNode() {}
+func (r *RegexLiteral) literalNode()    {}
+
+// Pos returns the position of first character belonging to the node
+func (r *RegexLiteral) Pos() int { return r.Token.Pos }
+
+// End returns the position of first character immediately after the node
+func (r *RegexLiteral) End() int { return r.Pattern.End() + 1 + len(r.Flags) }
+
+// TokenLiteral returns the literal from the token.REGEXBEG token
+func (r *RegexLiteral) TokenLiteral() string { return r.Token.Literal }
+func (r *RegexLiteral) String() string {
+	var out bytes.Buffer
+	out.WriteString("/")
+	switch pattern := r.Pattern.(type) {
+	case *InterpolatedString:
+		for _, part := range pattern.Parts {
+			if str, ok := part.(*StringLiteral); ok {
+				out.WriteString(str.Value)
+				continue
+			}
+			out.WriteString("#{" + part.String() + "}")
+		}
+	default:
+		out.WriteString(pattern.String())
+	}
+	out.WriteString("/")
+	out.WriteString(r.Flags)
+	return out.String()
+}
+
+// A RangeLiteral represents a range in the AST, e.g. 1..5 or 1...5. Left is
+// nil for beginless ranges, Right is nil for endless ranges.
+type RangeLiteral struct {
+	Token     token.Token // The .. or ... token
+	Left      Expression
+	Right     Expression
+	Exclusive bool
+}
+
+func (r *RangeLiteral) expressionNode() {}
+func (r *RangeLiteral) literalNode()    {}
+
+// Pos returns the position of first character belonging to the node
+func (r *RangeLiteral) Pos() int {
+	if r.Left != nil {
+		return r.Left.Pos()
+	}
+	return r.Token.Pos
+}
+
+// End returns the position of first character immediately after the node
+func (r *RangeLiteral) End() int {
+	if r.Right != nil {
+		return r.Right.End()
+	}
+	return r.Token.Pos + len(r.Token.Literal)
+}
+
+// TokenLiteral returns the literal from the .. or ... token
+func (r *RangeLiteral) TokenLiteral() string { return r.Token.Literal }
+func (r *RangeLiteral) String() string {
+	var out bytes.Buffer
+	out.WriteString("(")
+	if r.Left != nil {
+		out.WriteString(r.Left.String())
+	}
+	out.WriteString(r.Token.Literal)
+	if r.Right != nil {
+		out.WriteString(r.Right.String())
+	}
+	out.WriteString(")")
+	return out.String()
+}
+
 // An InfixExpression represents an infix operator in the AST
 type InfixExpression struct {
 	Token    token.Token // The operator token, e.g. +
@@ -1082,15 +2168,27 @@ type InfixExpression struct {
 }
 
 // MustEvaluateRight returns true if it is mandatory to evaluate the right side
-// of the operator, false otherwise
-func (oe *InfixExpression) MustEvaluateRight() bool {
-	return oe.Token.Type != token.LOGICALOR
+// of the operator given the truthiness of the left side, false otherwise. The
+// right side of `||` and `or` is evaluated for a falsy left side only, the
+// right side of `&&` and `and` for a truthy left side only.
+func (oe *InfixExpression) MustEvaluateRight(leftTruthy bool) bool {
+	switch oe.Token.Type {
+	case token.LOGICALOR, token.KEYWORD_OR:
+		return !leftTruthy
+	case token.LOGICALAND, token.KEYWORD_AND:
+		return leftTruthy
+	}
+	return true
 }
 
 // IsControlExpression returns true if the infix is used for control flow,
 // false otherwise
 func (oe *InfixExpression) IsControlExpression() bool {
-	return oe.Token.Type == token.LOGICALOR || oe.Token.Type == token.LOGICALAND
+	switch oe.Token.Type {
+	case token.LOGICALOR, token.LOGICALAND, token.KEYWORD_OR, token.KEYWORD_AND:
+		return true
+	}
+	return false
 }
 
 func (oe *InfixExpression) expressionNode() {}
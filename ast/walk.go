@@ -127,6 +127,12 @@ func walkExprList(v Visitor, list []Expression) {
 	}
 }
 
+func walkPatternList(v Visitor, list []Pattern) {
+	for _, x := range list {
+		Walk(v, x)
+	}
+}
+
 func walkStmtList(v Visitor, list []Statement) {
 	for _, x := range list {
 		Walk(v, x)
@@ -152,18 +158,30 @@ func Walk(v Visitor, node Node) {
 	case *Identifier,
 		*Global,
 		*IntegerLiteral,
+		*FloatLiteral,
 		*StringLiteral,
 		*SymbolLiteral,
 		*Boolean,
 		*Nil,
 		*Self,
-		*BlockCapture,
 		*Keyword__FILE__,
+		*Keyword__LINE__,
+		*Keyword__DIR__,
 		*Comment:
 		// nothing to do
 
 	case *BlockExpression:
 		walkParameterList(v, n.Parameters)
+		walkIdentifierList(v, n.Locals)
+		Walk(v, n.Body)
+
+	case *LambdaLiteral:
+		Walk(v, n.Block)
+
+	case *InterpolatedString:
+		walkExprList(v, n.Parts)
+
+	case *Interpolation:
 		Walk(v, n.Body)
 
 	case *ExceptionHandlingBlock:
@@ -171,6 +189,12 @@ func Walk(v Visitor, node Node) {
 		for _, r := range n.Rescues {
 			Walk(v, r)
 		}
+		if n.Else != nil {
+			Walk(v, n.Else)
+		}
+		if n.Ensure != nil {
+			Walk(v, n.Ensure)
+		}
 
 	case *RescueBlock:
 		if len(n.ExceptionClasses) != 0 {
@@ -196,10 +220,19 @@ func Walk(v Visitor, node Node) {
 		for _, r := range n.Rescues {
 			Walk(v, r)
 		}
+		if n.Else != nil {
+			Walk(v, n.Else)
+		}
+		if n.Ensure != nil {
+			Walk(v, n.Ensure)
+		}
 
 	case *FunctionParameter:
-		Walk(v, n.Name)
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
 		Walk(v, n.Default)
+		walkParameterList(v, n.Parameters)
 
 	case *IndexExpression:
 		Walk(v, n.Left)
@@ -229,6 +262,12 @@ func Walk(v Visitor, node Node) {
 	case *YieldExpression:
 		walkExprList(v, n.Arguments)
 
+	case *SuperExpression:
+		walkExprList(v, n.Arguments)
+		if n.Block != nil {
+			Walk(v, n.Block)
+		}
+
 	case *PrefixExpression:
 		Walk(v, n.Right)
 
@@ -236,6 +275,17 @@ func Walk(v Visitor, node Node) {
 		Walk(v, n.Left)
 		Walk(v, n.Right)
 
+	case *RegexLiteral:
+		Walk(v, n.Pattern)
+
+	case *RangeLiteral:
+		if n.Left != nil {
+			Walk(v, n.Left)
+		}
+		if n.Right != nil {
+			Walk(v, n.Right)
+		}
+
 	case *MultiAssignment:
 		walkIdentifierList(v, n.Variables)
 		walkExprList(v, n.Values)
@@ -253,6 +303,12 @@ func Walk(v Visitor, node Node) {
 			Walk(v, val)
 		}
 
+	case *Splat:
+		Walk(v, n.Value)
+
+	case *BlockCapture:
+		Walk(v, n.Block())
+
 	case *ExpressionStatement:
 		Walk(v, n.Expression)
 
@@ -269,6 +325,9 @@ func Walk(v Visitor, node Node) {
 	case *BlockStatement:
 		walkStmtList(v, n.Statements)
 
+	case *ProgramHook:
+		Walk(v, n.Body)
+
 	case *ScopedIdentifier:
 		Walk(v, n.Outer)
 		Walk(v, n.Inner)
@@ -280,10 +339,108 @@ func Walk(v Visitor, node Node) {
 			Walk(v, n.Alternative)
 		}
 
+	case *CaseExpression:
+		if n.Subject != nil {
+			Walk(v, n.Subject)
+		}
+		for _, w := range n.Whens {
+			Walk(v, w)
+		}
+		if n.Else != nil {
+			Walk(v, n.Else)
+		}
+
+	case *WhenClause:
+		walkExprList(v, n.Conditions)
+		Walk(v, n.Consequence)
+
+	case *CaseMatchExpression:
+		Walk(v, n.Subject)
+		for _, in := range n.Ins {
+			Walk(v, in)
+		}
+		if n.Else != nil {
+			Walk(v, n.Else)
+		}
+
+	case *InClause:
+		Walk(v, n.Pattern)
+		if n.Guard != nil {
+			Walk(v, n.Guard)
+		}
+		Walk(v, n.Consequence)
+
+	case *PatternMatchExpression:
+		Walk(v, n.Value)
+		Walk(v, n.Pattern)
+
+	case *ValuePattern:
+		Walk(v, n.Value)
+
+	case *PinPattern:
+		Walk(v, n.Value)
+
+	case *VariablePattern:
+		Walk(v, n.Name)
+
+	case *AlternativePattern:
+		walkPatternList(v, n.Alternatives)
+
+	case *CapturePattern:
+		Walk(v, n.Pattern)
+		Walk(v, n.Name)
+
+	case *SplatPattern:
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+
+	case *ArrayPattern:
+		if n.Constant != nil {
+			Walk(v, n.Constant)
+		}
+		walkPatternList(v, n.Elements())
+
+	case *FindPattern:
+		if n.Constant != nil {
+			Walk(v, n.Constant)
+		}
+		Walk(v, n.Pre)
+		walkPatternList(v, n.Patterns)
+		Walk(v, n.Post)
+
+	case *HashPattern:
+		if n.Constant != nil {
+			Walk(v, n.Constant)
+		}
+		for _, pair := range n.Pairs {
+			Walk(v, pair.Key)
+			if pair.Value != nil {
+				Walk(v, pair.Value)
+			}
+		}
+		if n.Rest != nil {
+			Walk(v, n.Rest)
+		}
+
 	case *LoopExpression:
 		Walk(v, n.Condition)
 		Walk(v, n.Block)
 
+	case *JumpExpression:
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+
+	case *AliasExpression:
+		Walk(v, n.New)
+		Walk(v, n.Old)
+
+	case *UndefExpression:
+		for _, m := range n.Methods {
+			Walk(v, m)
+		}
+
 	// Program
 	case *Program:
 		walkStmtList(v, n.Statements)
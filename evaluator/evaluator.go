@@ -1,12 +1,15 @@
 package evaluator
 
 import (
+	"bytes"
 	"fmt"
-	"sort"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/goruby/goruby/ast"
 	"github.com/goruby/goruby/object"
+	"github.com/goruby/goruby/token"
 	"github.com/pkg/errors"
 )
 
@@ -44,10 +47,13 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 
 	// Statements
 	case *ast.Program:
-		return evalProgram(node.Statements, env)
+		return evalProgram(node, env)
 	case *ast.ExpressionStatement:
 		return Eval(node.Expression, env)
 	case *ast.ReturnStatement:
+		if node.ReturnValue == nil {
+			return &object.ReturnValue{Value: object.NIL}, nil
+		}
 		val, err := Eval(node.ReturnValue, env)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval of return statement")
@@ -59,6 +65,8 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 	// Literals
 	case (*ast.IntegerLiteral):
 		return object.NewInteger(node.Value), nil
+	case (*ast.FloatLiteral):
+		return object.NewFloat(node.Value), nil
 	case (*ast.Boolean):
 		return nativeBoolToBooleanObject(node.Value), nil
 	case (*ast.Nil):
@@ -68,6 +76,17 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 		return self, nil
 	case (*ast.Keyword__FILE__):
 		return &object.String{Value: node.Filename}, nil
+	case (*ast.Keyword__LINE__):
+		return object.NewInteger(int64(node.Line)), nil
+	case (*ast.Keyword__DIR__):
+		if node.Filename == "" {
+			return object.NIL, nil
+		}
+		abs, err := filepath.Abs(node.Filename)
+		if err != nil {
+			return nil, errors.WithStack(object.NewRuntimeError("%s", err))
+		}
+		return &object.String{Value: filepath.Dir(abs)}, nil
 	case (*ast.InstanceVariable):
 		self, _ := env.Get("self")
 		selfObj := self.(*object.Self)
@@ -88,6 +107,9 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
 	case *ast.Global:
+		if group, err := strconv.Atoi(strings.TrimPrefix(node.Value, "$")); err == nil {
+			return evalMatchGroup(group, env), nil
+		}
 		val, ok := env.Get(node.Value)
 		if !ok {
 			return object.NIL, nil
@@ -95,11 +117,15 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 		return val, nil
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}, nil
+	case *ast.InterpolatedString:
+		return evalInterpolatedString(node, env)
+	case *ast.Interpolation:
+		return evalBlockStatement(node.Body, env)
 	case *ast.SymbolLiteral:
 		switch value := node.Value.(type) {
 		case *ast.Identifier:
 			return &object.Symbol{Value: value.Value}, nil
-		case *ast.StringLiteral:
+		case *ast.StringLiteral, *ast.InterpolatedString:
 			str, err := Eval(value, env)
 			if err != nil {
 				return nil, errors.WithMessage(err, "eval symbol literal string")
@@ -136,14 +162,35 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 			if err != nil {
 				return nil, errors.WithMessage(err, "eval function literal param")
 			}
-			params[i] = &object.FunctionParameter{Name: param.Name.Value, Default: def}
+			params[i] = &object.FunctionParameter{Name: param.Name.Value, Default: def, Kind: param.Kind}
 		}
 		body := node.Body
+		if len(node.Rescues) != 0 || node.Else != nil || node.Ensure != nil {
+			body = &ast.BlockStatement{
+				Token: node.Body.Token,
+				Statements: []ast.Statement{
+					&ast.ExpressionStatement{
+						Expression: &ast.ExceptionHandlingBlock{
+							BeginToken: node.Token,
+							EndToken:   node.EndToken,
+							TryBody:    node.Body,
+							Rescues:    node.Rescues,
+							Else:       node.Else,
+							Ensure:     node.Ensure,
+						},
+					},
+				},
+			}
+		}
 		function := &object.Function{
+			Name:       node.Name.Value,
 			Parameters: params,
 			Env:        env,
 			Body:       body,
 		}
+		if node.CapturedBlock != nil {
+			function.BlockParameter = node.CapturedBlock.Name.Value
+		}
 		extended := object.AddMethod(context, node.Name.Value, function)
 		if node.Receiver != nil && !inClassOrModule {
 			envInfo, _ := object.EnvStat(env, context)
@@ -155,12 +202,22 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 		body := node.Body
 		block := &object.Proc{
 			Parameters: params,
+			Locals:     node.Locals,
 			Body:       body,
 			Env:        env,
 		}
 		return block, nil
+	case *ast.LambdaLiteral:
+		lambda := &object.Proc{
+			Parameters:             node.Block.Parameters,
+			Locals:                 node.Block.Locals,
+			Body:                   node.Block.Body,
+			Env:                    env,
+			ArgumentCountMandatory: true,
+		}
+		return lambda, nil
 	case *ast.ArrayLiteral:
-		elements, err := evalExpressions(node.Elements, env)
+		elements, err := evalArguments(node.Elements, env)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval array literal")
 		}
@@ -192,6 +249,24 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 
 	// Expressions
 	case *ast.Assignment:
+		switch node.Left.(type) {
+		case *ast.IndexExpression, *ast.ContextCallExpression:
+			if node.IsConditional() || isCompoundAssignment(node) {
+				return evalOperatorAssignment(node, env)
+			}
+		}
+		if node.IsConditional() {
+			current, err := evalConditionalAssignmentTarget(node.Left, env)
+			if err != nil {
+				return nil, errors.WithMessage(err, "eval left hand Assignment side")
+			}
+			if isTruthy(current) == (node.Token.Type == token.LOGICALORASSIGN) {
+				return current, nil
+			}
+		}
+		if attribute, ok := node.Left.(*ast.ContextCallExpression); ok {
+			return evalAttributeAssignment(attribute, node.Right, env)
+		}
 		right, err := Eval(node.Right, env)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval right hand Assignment side")
@@ -207,7 +282,7 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 			if err != nil {
 				return nil, errors.WithMessage(err, "eval left hand Assignment side: eval right side of IndexExpression")
 			}
-			return evalIndexExpressionAssignment(indexLeft, index, expandToArrayIfNeeded(right))
+			return evalIndexExpressionAssignment(indexLeft, index, expandToArrayIfNeeded(right), env)
 		case *ast.InstanceVariable:
 			self, _ := env.Get("self")
 			selfObj := self.(*object.Self)
@@ -265,7 +340,9 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 					if err != nil {
 						return nil, errors.WithMessage(err, "eval left hand Assignment side: eval right side of IndexExpression")
 					}
-					evalIndexExpressionAssignment(indexLeft, index, values[i])
+					if _, err := evalIndexExpressionAssignment(indexLeft, index, values[i], env); err != nil {
+						return nil, errors.WithMessage(err, "eval left hand Assignment side")
+					}
 					continue
 				}
 				env.Set(exp.String(), values[i])
@@ -318,38 +395,22 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 		env.Set(node.Name.Value, self.RubyObject)
 		return bodyReturn, nil
 	case *ast.ContextCallExpression:
-		context, err := Eval(node.Context, env)
-		if err != nil {
-			return nil, errors.WithMessage(err, "eval method call receiver")
-		}
-		if context == nil {
-			context, _ = env.Get("self")
-		}
-		args, err := evalExpressions(node.Arguments, env)
-		if err != nil {
-			return nil, errors.WithMessage(err, "eval method call arguments")
-		}
-		if node.Block != nil {
-			block, err := Eval(node.Block, env)
-			if err != nil {
-				return nil, errors.WithMessage(err, "eval method call block")
-			}
-			args = append(args, block)
-		}
-		callContext := &callContext{object.NewCallContext(env, context)}
-		return object.Send(callContext, node.Function.Value, args...)
+		result, _, err := evalContextCallExpression(node, env)
+		return result, err
 	case *ast.YieldExpression:
 		selfObject, _ := env.Get("self")
 		self := selfObject.(*object.Self)
 		if self.Block == nil {
 			return nil, errors.WithStack(object.NewNoBlockGivenLocalJumpError())
 		}
-		args, err := evalExpressions(node.Arguments, env)
+		args, err := evalArguments(node.Arguments, env)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval yield arguments")
 		}
 		callContext := &callContext{object.NewCallContext(env, self)}
 		return self.Block.Call(callContext, args...)
+	case *ast.SuperExpression:
+		return evalSuperExpression(node, env)
 	case *ast.IndexExpression:
 		left, err := Eval(node.Left, env)
 		if err != nil {
@@ -359,20 +420,28 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval IndexExpression index")
 		}
-		return evalIndexExpression(left, index)
+		args := []object.RubyObject{index}
+		if node.Length != nil {
+			length, err := Eval(node.Length, env)
+			if err != nil {
+				return nil, errors.WithMessage(err, "eval IndexExpression length")
+			}
+			args = append(args, length)
+		}
+		return evalIndexExpression(left, args, env)
 	case *ast.PrefixExpression:
 		right, err := Eval(node.Right, env)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval prefix right side")
 		}
-		return evalPrefixExpression(node.Operator, right)
+		return evalPrefixExpression(node.Operator, right, env)
 	case *ast.InfixExpression:
 		left, err := Eval(node.Left, env)
 		if err != nil {
 			return nil, errors.WithMessage(err, "eval operator left side")
 		}
 
-		if node.IsControlExpression() && !node.MustEvaluateRight() && isTruthy(left) {
+		if node.IsControlExpression() && !node.MustEvaluateRight(isTruthy(left)) {
 			return left, nil
 		}
 
@@ -385,8 +454,30 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 		}
 		context := &callContext{object.NewCallContext(env, left)}
 		return object.Send(context, node.Operator, right)
+	case *ast.RangeLiteral:
+		return evalRangeLiteral(node, env)
+	case *ast.RegexLiteral:
+		pattern, err := Eval(node.Pattern, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval regex literal pattern")
+		}
+		return object.NewRegexp(pattern.(*object.String).Value, node.Flags)
 	case *ast.ConditionalExpression:
 		return evalConditionalExpression(node, env)
+	case *ast.CaseMatchExpression:
+		return evalCaseMatchExpression(node, env)
+	case *ast.PatternMatchExpression:
+		return evalPatternMatchExpression(node, env)
+	case *ast.CaseExpression:
+		return evalCaseExpression(node, env)
+	case *ast.LoopExpression:
+		return evalLoopExpression(node, env)
+	case *ast.JumpExpression:
+		return evalJumpExpression(node, env)
+	case *ast.AliasExpression:
+		return evalAliasExpression(node, env)
+	case *ast.UndefExpression:
+		return evalUndefExpression(node, env)
 	case *ast.ScopedIdentifier:
 		self, _ := env.Get("self")
 		outer, ok := env.Get(node.Outer.Value)
@@ -409,11 +500,7 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 		}
 		return inner, nil
 	case *ast.ExceptionHandlingBlock:
-		bodyReturn, err := Eval(node.TryBody, env)
-		if err == nil {
-			return bodyReturn, nil
-		}
-		return handleException(err, node.Rescues, env)
+		return evalExceptionHandlingBlock(node, env)
 
 	case *ast.Comment:
 		// ignore comments
@@ -428,26 +515,53 @@ func Eval(node ast.Node, env object.Environment) (object.RubyObject, error) {
 
 }
 
-func evalProgram(stmts []ast.Statement, env object.Environment) (object.RubyObject, error) {
+// evalProgram evaluates the BEGIN blocks of program, its statements and
+// finally its END blocks in reverse order of their registration. The END
+// blocks run even if a statement fails.
+func evalProgram(program *ast.Program, env object.Environment) (object.RubyObject, error) {
+	if program.Data != nil {
+		env.Set("DATA", object.NewStringIO(*program.Data))
+	}
+	for _, statement := range program.Statements {
+		if hook, ok := statement.(*ast.ProgramHook); ok && hook.IsBegin() {
+			_, err := Eval(hook.Body, env)
+			if err != nil {
+				return nil, errors.WithMessage(object.LocalJump(err, true), "eval BEGIN block")
+			}
+		}
+	}
 	var result object.RubyObject
 	var err error
-	for _, statement := range stmts {
-		if _, ok := statement.(*ast.Comment); ok {
+	var endBlocks []*ast.ProgramHook
+	for _, statement := range program.Statements {
+		switch statement := statement.(type) {
+		case *ast.Comment:
+			continue
+		case *ast.ProgramHook:
+			if !statement.IsBegin() {
+				endBlocks = append(endBlocks, statement)
+			}
 			continue
 		}
 		result, err = Eval(statement, env)
 
 		if err != nil {
-			return nil, errors.WithMessage(err, "eval program statement")
+			result, err = nil, errors.WithMessage(object.LocalJump(err, true), "eval program statement")
+			break
 		}
 
-		switch result := result.(type) {
-		case *object.ReturnValue:
-			return result.Value, nil
+		if returnValue, ok := result.(*object.ReturnValue); ok {
+			result = returnValue.Value
+			break
 		}
-
 	}
-	return result, nil
+	for i := len(endBlocks) - 1; i >= 0; i-- {
+		_, endErr := Eval(endBlocks[i].Body, env)
+		if endErr != nil && err == nil {
+			result, err = nil, errors.WithMessage(object.LocalJump(endErr, true), "eval END block")
+		}
+	}
+	return result, err
 }
 
 func evalExpressions(exps []ast.Expression, env object.Environment) ([]object.RubyObject, error) {
@@ -463,37 +577,181 @@ func evalExpressions(exps []ast.Expression, env object.Environment) ([]object.Ru
 	return result, nil
 }
 
-func evalPrefixExpression(operator string, right object.RubyObject) (object.RubyObject, error) {
-	switch operator {
-	case "!":
-		return evalBangOperatorExpression(right), nil
-	case "-":
-		return evalMinusPrefixOperatorExpression(right)
-	default:
-		return nil, errors.WithStack(object.NewException("unknown operator: %s%s", operator, right.Type()))
+// evalArguments evaluates the arguments of a call. Splatted arguments are
+// expanded, keyword arguments are merged into one trailing hash and a block
+// argument is passed on as last argument.
+func evalArguments(exps []ast.Expression, env object.Environment) ([]object.RubyObject, error) {
+	var args []object.RubyObject
+	var keywords *object.Hash
+	var block object.RubyObject
+	mergeKeywords := func(hash *object.Hash) {
+		if keywords == nil {
+			keywords = &object.Hash{}
+		}
+		for k, v := range hash.Map() {
+			keywords.Set(k, v)
+		}
+	}
+
+	for _, e := range exps {
+		switch e := e.(type) {
+		case *ast.Splat:
+			value, err := Eval(e.Value, env)
+			if err != nil {
+				return nil, err
+			}
+			if !e.IsDoubleSplat() {
+				args = append(args, splatValues(value)...)
+				continue
+			}
+			hash, ok := value.(*object.Hash)
+			if !ok {
+				return nil, errors.WithStack(
+					object.NewImplicitConversionTypeError(&object.Hash{}, value),
+				)
+			}
+			mergeKeywords(hash)
+		case *ast.BlockCapture:
+			value, err := Eval(e.Block(), env)
+			if err != nil {
+				return nil, err
+			}
+			if value == object.NIL {
+				continue
+			}
+			proc, err := toProc(value, env)
+			if err != nil {
+				return nil, err
+			}
+			block = object.NewBlockArgument(proc)
+		default:
+			evaluated, err := Eval(e, env)
+			if err != nil {
+				return nil, err
+			}
+			if hash, ok := e.(*ast.HashLiteral); ok && hash.IsBraceless() {
+				mergeKeywords(evaluated.(*object.Hash))
+				continue
+			}
+			args = append(args, evaluated)
+		}
+	}
+	if keywords != nil {
+		args = append(args, keywords)
 	}
+	if block != nil {
+		args = append(args, block)
+	}
+	return args, nil
 }
 
-func evalBangOperatorExpression(right object.RubyObject) object.RubyObject {
-	switch right {
-	case object.TRUE:
-		return object.FALSE
-	case object.FALSE:
-		return object.TRUE
-	case object.NIL:
-		return object.TRUE
-	default:
-		return object.FALSE
+// splatValues returns the elements of value if it is an array, no values for
+// nil and value itself otherwise.
+// toProc converts value passed as block argument into a proc by calling
+// its to_proc method
+func toProc(value object.RubyObject, env object.Environment) (*object.Proc, error) {
+	if proc, ok := value.(*object.Proc); ok {
+		return proc, nil
+	}
+	if !object.RespondTo(value, "to_proc") {
+		return nil, errors.WithStack(
+			object.NewWrongArgumentTypeError(&object.Proc{}, value),
+		)
+	}
+	context := &callContext{object.NewCallContext(env, value)}
+	converted, err := object.Send(context, "to_proc")
+	if err != nil {
+		return nil, err
+	}
+	proc, ok := converted.(*object.Proc)
+	if !ok {
+		return nil, errors.WithStack(
+			object.NewConversionResultTypeError("Proc", value, "to_proc", converted),
+		)
 	}
+	return proc, nil
 }
 
-func evalMinusPrefixOperatorExpression(right object.RubyObject) (object.RubyObject, error) {
-	switch right := right.(type) {
-	case *object.Integer:
-		return &object.Integer{Value: -right.Value}, nil
-	default:
-		return nil, errors.WithStack(object.NewException("unknown operator: -%s", right.Type()))
+func splatValues(value object.RubyObject) []object.RubyObject {
+	if value == object.NIL {
+		return nil
+	}
+	if array, ok := value.(*object.Array); ok {
+		return array.Elements
+	}
+	return []object.RubyObject{value}
+}
+
+func evalInterpolatedString(node *ast.InterpolatedString, env object.Environment) (object.RubyObject, error) {
+	var out bytes.Buffer
+	for _, part := range node.Parts {
+		evaluated, err := Eval(part, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval string interpolation")
+		}
+		if str, ok := evaluated.(*object.String); ok {
+			out.WriteString(str.Value)
+			continue
+		}
+		context := &callContext{object.NewCallContext(env, evaluated)}
+		str, err := object.Send(context, "to_s")
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval string interpolation")
+		}
+		out.WriteString(str.Inspect())
+	}
+	return &object.String{Value: out.String()}, nil
+}
+
+// evalPrefixExpression evaluates a prefix operator by sending it to the
+// operand. The unary `-` and `+` are sent as `-@` and `+@`, unless the operand
+// is a number.
+func evalPrefixExpression(operator string, right object.RubyObject, env object.Environment) (object.RubyObject, error) {
+	switch operator {
+	case "-":
+		switch right := right.(type) {
+		case *object.Integer:
+			return &object.Integer{Value: -right.Value}, nil
+		case *object.Float:
+			return object.NewFloat(-right.Value), nil
+		}
+		operator = "-@"
+	case "+":
+		switch right.(type) {
+		case *object.Integer, *object.Float:
+			return right, nil
+		}
+		operator = "+@"
+	case "not":
+		operator = "!"
+	}
+	context := &callContext{object.NewCallContext(env, right)}
+	return object.Send(context, operator)
+}
+
+func evalRangeLiteral(node *ast.RangeLiteral, env object.Environment) (object.RubyObject, error) {
+	var left, right object.RubyObject = object.NIL, object.NIL
+	var err error
+	if node.Left != nil {
+		left, err = Eval(node.Left, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval range begin")
+		}
 	}
+	if node.Right != nil {
+		right, err = Eval(node.Right, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval range end")
+		}
+	}
+	if left != object.NIL && right != object.NIL {
+		context := &callContext{object.NewCallContext(env, left)}
+		cmp, err := object.Send(context, "<=>", right)
+		if err != nil || cmp == object.NIL {
+			return nil, errors.WithStack(object.NewArgumentError("bad value for range"))
+		}
+	}
+	return object.NewRange(left, right, node.Exclusive), nil
 }
 
 func evalConditionalExpression(ce *ast.ConditionalExpression, env object.Environment) (object.RubyObject, error) {
@@ -514,7 +772,183 @@ func evalConditionalExpression(ce *ast.ConditionalExpression, env object.Environ
 	}
 }
 
-func evalIndexExpressionAssignment(left, index, right object.RubyObject) (object.RubyObject, error) {
+// evalConditionalAssignmentTarget returns the current value of the target
+// of `||=` or `&&=`. Like in `x ||= 1` the target may not be defined yet.
+func evalConditionalAssignmentTarget(target ast.Expression, env object.Environment) (object.RubyObject, error) {
+	if ident, ok := target.(*ast.Identifier); ok {
+		if val, ok := env.Get(ident.Value); ok {
+			return val, nil
+		}
+		return object.NIL, nil
+	}
+	return Eval(target, env)
+}
+
+// isCompoundAssignment reports whether node is an assignment like `x += 1`,
+// which the parser expands to `x = x + 1`
+func isCompoundAssignment(node *ast.Assignment) bool {
+	if node.Token.Type == token.ASSIGN {
+		return false
+	}
+	operation, ok := node.Right.(*ast.InfixExpression)
+	return ok && operation.Left == node.Left
+}
+
+// evalOperatorAssignment evaluates a conditional or compound assignment to an
+// index or an attribute, i.e. `a[i] ||= v` or `obj.x += 1`. The receiver and
+// the index are evaluated only once for both reading and writing the target.
+func evalOperatorAssignment(node *ast.Assignment, env object.Environment) (object.RubyObject, error) {
+	var read func() (object.RubyObject, error)
+	var write func(object.RubyObject) (object.RubyObject, error)
+	switch target := node.Left.(type) {
+	case *ast.IndexExpression:
+		receiver, err := Eval(target.Left, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval left hand Assignment side: eval left side of IndexExpression")
+		}
+		index, err := Eval(target.Index, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval left hand Assignment side: eval right side of IndexExpression")
+		}
+		args := []object.RubyObject{index}
+		if target.Length != nil {
+			length, err := Eval(target.Length, env)
+			if err != nil {
+				return nil, errors.WithMessage(err, "eval left hand Assignment side: eval IndexExpression length")
+			}
+			args = append(args, length)
+		}
+		read = func() (object.RubyObject, error) {
+			return evalIndexExpression(receiver, args, env)
+		}
+		write = func(value object.RubyObject) (object.RubyObject, error) {
+			return evalIndexExpressionAssignment(receiver, index, value, env)
+		}
+	case *ast.ContextCallExpression:
+		receiver, skipped, err := evalCallReceiver(target, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval left hand Assignment side: eval attribute receiver")
+		}
+		if skipped {
+			return object.NIL, nil
+		}
+		context := &callContext{object.NewCallContext(env, receiver)}
+		read = func() (object.RubyObject, error) {
+			return object.Send(context, target.Function.Value)
+		}
+		write = func(value object.RubyObject) (object.RubyObject, error) {
+			if _, err := object.Send(context, target.Function.Value+"=", value); err != nil {
+				return nil, err
+			}
+			return value, nil
+		}
+	}
+
+	current, err := read()
+	if err != nil {
+		return nil, errors.WithMessage(err, "eval left hand Assignment side")
+	}
+	if node.IsConditional() {
+		if isTruthy(current) == (node.Token.Type == token.LOGICALORASSIGN) {
+			return current, nil
+		}
+		value, err := Eval(node.Right, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval right hand Assignment side")
+		}
+		return write(expandToArrayIfNeeded(value))
+	}
+	operation := node.Right.(*ast.InfixExpression)
+	operand, err := Eval(operation.Right, env)
+	if err != nil {
+		return nil, errors.WithMessage(err, "eval operator right side")
+	}
+	context := &callContext{object.NewCallContext(env, current)}
+	value, err := object.Send(context, operation.Operator, operand)
+	if err != nil {
+		return nil, err
+	}
+	return write(value)
+}
+
+// evalContextCallExpression evaluates a method call. It reports whether the
+// call was skipped, because a safe navigation within its call chain found a
+// nil receiver, i.e. `x&.foo.bar` does not call bar if x is nil.
+func evalContextCallExpression(node *ast.ContextCallExpression, env object.Environment) (object.RubyObject, bool, error) {
+	context, skipped, err := evalCallReceiver(node, env)
+	if err != nil {
+		return nil, false, errors.WithMessage(err, "eval method call receiver")
+	}
+	if skipped {
+		return object.NIL, true, nil
+	}
+	args, err := evalArguments(node.Arguments, env)
+	if err != nil {
+		return nil, false, errors.WithMessage(err, "eval method call arguments")
+	}
+	var block *object.Proc
+	if node.Block != nil {
+		evaluated, err := Eval(node.Block, env)
+		if err != nil {
+			return nil, false, errors.WithMessage(err, "eval method call block")
+		}
+		block = evaluated.(*object.Proc)
+		args = append(args, object.NewBlockArgument(block))
+	}
+	callContext := &callContext{object.NewCallContext(env, context)}
+	result, err := object.Send(callContext, node.Function.Value, args...)
+	result, err = returnFromBlockCall(block, result, err)
+	return result, false, err
+}
+
+// evalCallReceiver evaluates the receiver of call, defaulting to self. It
+// reports whether the call is skipped by a safe navigation on a nil receiver,
+// either of call itself or of a preceding call in the chain.
+func evalCallReceiver(call *ast.ContextCallExpression, env object.Environment) (object.RubyObject, bool, error) {
+	var receiver object.RubyObject
+	if inner, ok := call.Context.(*ast.ContextCallExpression); ok {
+		result, skipped, err := evalContextCallExpression(inner, env)
+		if err != nil || skipped {
+			return nil, skipped, err
+		}
+		receiver = result
+	} else {
+		result, err := Eval(call.Context, env)
+		if err != nil {
+			return nil, false, err
+		}
+		receiver = result
+	}
+	if receiver == nil {
+		receiver, _ = env.Get("self")
+	}
+	return receiver, call.IsSafeNavigation() && receiver == object.NIL, nil
+}
+
+// evalAttributeAssignment evaluates `receiver.name = value` by sending
+// `name=` to the receiver. With safe navigation the value is not evaluated
+// when the receiver is nil.
+func evalAttributeAssignment(attribute *ast.ContextCallExpression, value ast.Expression, env object.Environment) (object.RubyObject, error) {
+	receiver, skipped, err := evalCallReceiver(attribute, env)
+	if err != nil {
+		return nil, errors.WithMessage(err, "eval left hand Assignment side: eval attribute receiver")
+	}
+	if skipped {
+		return object.NIL, nil
+	}
+	right, err := Eval(value, env)
+	if err != nil {
+		return nil, errors.WithMessage(err, "eval right hand Assignment side")
+	}
+	right = expandToArrayIfNeeded(right)
+	callContext := &callContext{object.NewCallContext(env, receiver)}
+	if _, err := object.Send(callContext, attribute.Function.Value+"=", right); err != nil {
+		return nil, err
+	}
+	return right, nil
+}
+
+func evalIndexExpressionAssignment(left, index, right object.RubyObject, env object.Environment) (object.RubyObject, error) {
 	switch target := left.(type) {
 	case *object.Array:
 		integer, ok := index.(*object.Integer)
@@ -537,21 +971,26 @@ func evalIndexExpressionAssignment(left, index, right object.RubyObject) (object
 		target.Set(index, right)
 		return right, nil
 	default:
-		return nil, errors.Wrap(
-			object.NewException("assignment target not supported: %s", left.Type()),
-			"eval IndexExpression Assignment",
-		)
+		context := &callContext{object.NewCallContext(env, left)}
+		if _, err := object.Send(context, "[]=", index, right); err != nil {
+			return nil, errors.WithMessage(err, "eval IndexExpression Assignment")
+		}
+		return right, nil
 	}
 }
 
-func evalIndexExpression(left, index object.RubyObject) (object.RubyObject, error) {
+func evalIndexExpression(left object.RubyObject, args []object.RubyObject, env object.Environment) (object.RubyObject, error) {
 	switch target := left.(type) {
 	case *object.Array:
-		return evalArrayIndexExpression(target, index), nil
+		if rng, ok := args[0].(*object.Range); ok {
+			return evalArraySliceExpression(target, rng)
+		}
+		return evalArrayIndexExpression(target, args[0]), nil
 	case *object.Hash:
-		return evalHashIndexExpression(target, index), nil
+		return evalHashIndexExpression(target, args[0]), nil
 	default:
-		return nil, errors.WithStack(object.NewException("index operator not supported: %s", left.Type()))
+		context := &callContext{object.NewCallContext(env, left)}
+		return object.Send(context, "[]", args...)
 	}
 }
 
@@ -575,6 +1014,44 @@ func evalArrayIndexExpression(arrayObject *object.Array, index object.RubyObject
 	return arrayObject.Elements[idx]
 }
 
+func evalArraySliceExpression(arrayObject *object.Array, rng *object.Range) (object.RubyObject, error) {
+	length := int64(len(arrayObject.Elements))
+	bound := func(obj object.RubyObject, fallback int64) (int64, error) {
+		if obj == object.NIL {
+			return fallback, nil
+		}
+		i, ok := obj.(*object.Integer)
+		if !ok {
+			return 0, errors.WithStack(object.NewImplicitConversionTypeError(&object.Integer{}, obj))
+		}
+		if i.Value < 0 {
+			return i.Value + length, nil
+		}
+		return i.Value, nil
+	}
+	start, err := bound(rng.Left, 0)
+	if err != nil {
+		return nil, err
+	}
+	end, err := bound(rng.Right, length-1)
+	if err != nil {
+		return nil, err
+	}
+	if rng.Exclusive && rng.Right != object.NIL {
+		end--
+	}
+	if start < 0 || start > length {
+		return object.NIL, nil
+	}
+	if end >= length {
+		end = length - 1
+	}
+	if end < start {
+		return object.NewArray(), nil
+	}
+	return object.NewArray(arrayObject.Elements[start : end+1]...), nil
+}
+
 func evalHashIndexExpression(hash *object.Hash, index object.RubyObject) object.RubyObject {
 	result, ok := hash.Get(index)
 	if !ok {
@@ -605,6 +1082,270 @@ func evalBlockStatement(block *ast.BlockStatement, env object.Environment) (obje
 	return result, nil
 }
 
+func evalLoopExpression(loop *ast.LoopExpression, env object.Environment) (object.RubyObject, error) {
+	skipCondition := loop.DoWhile
+	for {
+		if !skipCondition {
+			condition, err := Eval(loop.Condition, env)
+			if err != nil {
+				return nil, errors.WithMessage(err, "eval loop condition")
+			}
+			if isTruthy(condition) == loop.IsNegated() {
+				return object.NIL, nil
+			}
+		}
+		skipCondition = false
+		result, err := Eval(loop.Block, env)
+		switch jump := errors.Cause(err).(type) {
+		case nil:
+		case *object.BreakError:
+			if jump.Block != nil {
+				return nil, err
+			}
+			return jump.Value, nil
+		case *object.NextError:
+			continue
+		case *object.RedoError:
+			skipCondition = true
+			continue
+		default:
+			return nil, err
+		}
+		if result.Type() == object.RETURN_VALUE_OBJ {
+			return result, nil
+		}
+	}
+}
+
+func evalExceptionHandlingBlock(block *ast.ExceptionHandlingBlock, env object.Environment) (object.RubyObject, error) {
+	result, err := evalRescue(block, env)
+	if block.Ensure == nil {
+		return result, err
+	}
+	ensureResult, ensureErr := Eval(block.Ensure, env)
+	if ensureErr != nil {
+		return nil, errors.WithMessage(ensureErr, "eval ensure")
+	}
+	// an explicit return within ensure discards the outcome of the body
+	if ensureResult != nil && ensureResult.Type() == object.RETURN_VALUE_OBJ {
+		return ensureResult, nil
+	}
+	return result, err
+}
+
+// evalRescue evaluates the body of block and handles any exception raised
+// within it. The body is reevaluated as long as a rescue clause issues a retry.
+func evalRescue(block *ast.ExceptionHandlingBlock, env object.Environment) (object.RubyObject, error) {
+	for {
+		result, err := Eval(block.TryBody, env)
+		if err == nil {
+			if block.Else == nil || result.Type() == object.RETURN_VALUE_OBJ {
+				return result, nil
+			}
+			return Eval(block.Else, env)
+		}
+		if object.IsJump(err) {
+			return nil, err
+		}
+		result, err = handleException(err, block.Rescues, env)
+		if _, ok := errors.Cause(err).(*object.RetryError); ok {
+			continue
+		}
+		return result, err
+	}
+}
+
+func evalSuperExpression(super *ast.SuperExpression, env object.Environment) (object.RubyObject, error) {
+	selfObject, _ := env.Get("self")
+	self := selfObject.(*object.Self)
+	if self.Method == nil {
+		return nil, errors.WithStack(
+			object.NewRuntimeError("super called outside of method"),
+		)
+	}
+	var args []object.RubyObject
+	if super.Implicit {
+		// pass on the current values of the method parameters
+		var keywords *object.Hash
+		for _, param := range self.Method.Parameters {
+			arg, _ := env.Get(param.Name)
+			switch param.Kind {
+			case ast.SplatParameter:
+				args = append(args, splatValues(arg)...)
+			case ast.KeywordParameter:
+				if keywords == nil {
+					keywords = &object.Hash{}
+				}
+				keywords.Set(&object.Symbol{Value: param.Name}, arg)
+			case ast.DoubleSplatParameter:
+				if keywords == nil {
+					keywords = &object.Hash{}
+				}
+				if hash, ok := arg.(*object.Hash); ok {
+					for k, v := range hash.Map() {
+						keywords.Set(k, v)
+					}
+				}
+			default:
+				args = append(args, arg)
+			}
+		}
+		if keywords != nil {
+			args = append(args, keywords)
+		}
+	} else {
+		var err error
+		args, err = evalArguments(super.Arguments, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval super arguments")
+		}
+	}
+	// without a block of its own super passes on the block of the current method
+	block := self.Block
+	if super.Block != nil {
+		evaluated, err := Eval(super.Block, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval super block")
+		}
+		block = evaluated.(*object.Proc)
+	}
+	if block != nil {
+		args = append(args, object.NewBlockArgument(block))
+	}
+	callContext := &callContext{object.NewCallContext(env, self)}
+	result, err := object.Super(callContext, self.Method, args...)
+	if super.Block == nil {
+		return result, err
+	}
+	return returnFromBlockCall(block, result, err)
+}
+
+func evalJumpExpression(jump *ast.JumpExpression, env object.Environment) (object.RubyObject, error) {
+	var value object.RubyObject = object.NIL
+	if jump.Value != nil {
+		var err error
+		value, err = Eval(jump.Value, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval jump value")
+		}
+	}
+	switch jump.Token.Type {
+	case token.BREAK:
+		return nil, errors.WithStack(&object.BreakError{Value: value})
+	case token.NEXT:
+		return nil, errors.WithStack(&object.NextError{Value: value})
+	case token.RETRY:
+		return nil, errors.WithStack(&object.RetryError{})
+	default:
+		return nil, errors.WithStack(&object.RedoError{})
+	}
+}
+
+func evalAliasExpression(alias *ast.AliasExpression, env object.Environment) (object.RubyObject, error) {
+	if newName, ok := alias.New.(*ast.Global); ok {
+		env.AliasGlobal(newName.Value, alias.Old.(*ast.Global).Value)
+		return object.NIL, nil
+	}
+	context, _ := env.Get("self")
+	_, err := object.AliasMethod(context, alias.New.(*ast.Identifier).Value, alias.Old.(*ast.Identifier).Value)
+	if err != nil {
+		return nil, errors.WithMessage(err, "eval alias")
+	}
+	return object.NIL, nil
+}
+
+func evalUndefExpression(undef *ast.UndefExpression, env object.Environment) (object.RubyObject, error) {
+	context, _ := env.Get("self")
+	for _, method := range undef.Methods {
+		_, err := object.UndefineMethod(context, method.Value)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval undef")
+		}
+	}
+	return object.NIL, nil
+}
+
+// returnFromBlockCall returns the outcome of a method call block was
+// attached to as block literal. A break issued within block ends the call
+// with the value of the break. block is orphaned as the call returned.
+func returnFromBlockCall(block *object.Proc, result object.RubyObject, err error) (object.RubyObject, error) {
+	if block == nil {
+		return result, err
+	}
+	block.Orphan()
+	if brk, ok := errors.Cause(err).(*object.BreakError); ok && brk.Block == block {
+		return brk.Value, nil
+	}
+	return result, err
+}
+
+func evalCaseExpression(ce *ast.CaseExpression, env object.Environment) (object.RubyObject, error) {
+	var subject object.RubyObject
+	if ce.Subject != nil {
+		var err error
+		subject, err = Eval(ce.Subject, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval case subject")
+		}
+	}
+	for _, when := range ce.Whens {
+		for _, condition := range when.Conditions {
+			patterns, err := evalWhenCondition(condition, env)
+			if err != nil {
+				return nil, errors.WithMessage(err, "eval when condition")
+			}
+			for _, pattern := range patterns {
+				matched := pattern
+				if subject != nil {
+					context := &callContext{object.NewCallContext(env, pattern)}
+					matched, err = object.Send(context, "===", subject)
+					if err != nil {
+						return nil, errors.WithMessage(err, "eval when condition")
+					}
+				}
+				if isTruthy(matched) {
+					return Eval(when.Consequence, env)
+				}
+			}
+		}
+	}
+	if ce.Else != nil {
+		return Eval(ce.Else, env)
+	}
+	return object.NIL, nil
+}
+
+// evalWhenCondition evaluates a when condition into the patterns to test.
+// A splatted condition contributes each of its elements.
+func evalWhenCondition(condition ast.Expression, env object.Environment) ([]object.RubyObject, error) {
+	splat, ok := condition.(*ast.Splat)
+	if !ok {
+		pattern, err := Eval(condition, env)
+		if err != nil {
+			return nil, err
+		}
+		return []object.RubyObject{pattern}, nil
+	}
+	value, err := Eval(splat.Value, env)
+	if err != nil {
+		return nil, err
+	}
+	return splatValues(value), nil
+}
+
+// evalMatchGroup returns the group of the last match referenced by $1 to $9
+func evalMatchGroup(group int, env object.Environment) object.RubyObject {
+	lastMatch, ok := env.Get("$~")
+	if !ok {
+		return object.NIL
+	}
+	match, ok := lastMatch.(*object.MatchData)
+	if !ok || group == 0 {
+		return object.NIL
+	}
+	return match.Group(group)
+}
+
 func evalIdentifier(node *ast.Identifier, env object.Environment) (object.RubyObject, error) {
 	val, ok := env.Get(node.Value)
 	if ok {
@@ -621,6 +1362,12 @@ func evalIdentifier(node *ast.Identifier, env object.Environment) (object.RubyOb
 	self, _ := env.Get("self")
 	context := &callContext{object.NewCallContext(env, self)}
 	val, err := object.Send(context, node.Value)
+	// only a missing method node.Value is reported as missing identifier,
+	// errors raised within the method are passed through
+	noMethod, ok := errors.Cause(err).(*object.NoMethodError)
+	if err != nil && (!ok || noMethod.Error() != object.NewNoMethodError(self, node.Value).Error()) {
+		return nil, err
+	}
 	if err != nil {
 		return nil, errors.Wrap(
 			object.NewUndefinedLocalVariableOrMethodNameError(self, node.Value),
@@ -637,62 +1384,55 @@ func unwrapReturnValue(obj object.RubyObject) object.RubyObject {
 	return obj
 }
 
+// handleException evaluates the first rescue clause matching the exception
+// err. If there is none, err is returned unchanged.
 func handleException(err error, rescues []*ast.RescueBlock, env object.Environment) (object.RubyObject, error) {
-	if err != nil && len(rescues) == 0 {
+	errorObject, ok := errors.Cause(err).(object.RubyObject)
+	if !ok {
 		return nil, err
 	}
-	errorObject := err.(object.RubyObject)
-	errClass := errorObject.Class().Name()
-	rescueEnv := object.WithScopedLocalVariables(env)
-
-	var catchAll *ast.RescueBlock
 	for _, r := range rescues {
-		if len(r.ExceptionClasses) == 0 {
-			catchAll = r
+		matched, matchErr := rescueMatches(r, errorObject, env)
+		if matchErr != nil {
+			return nil, matchErr
+		}
+		if !matched {
 			continue
 		}
 		if r.Exception != nil {
-			rescueEnv.Set(r.Exception.Value, errorObject)
-		}
-		for _, cl := range r.ExceptionClasses {
-			if cl.Value == errClass {
-				rescueRet, err := Eval(r.Body, rescueEnv)
-				return rescueRet, err
-			}
+			env.Set(r.Exception.Value, errorObject)
 		}
+		return Eval(r.Body, env)
 	}
-
-	if catchAll != nil {
-		ancestors := getAncestors(errorObject)
-		sort.Strings(ancestors)
-		if sort.SearchStrings(ancestors, "StandardError") >= len(ancestors) {
-			return nil, err
-		}
-
-		if catchAll.Exception != nil {
-			rescueEnv.Set(catchAll.Exception.Value, errorObject)
-		}
-		rescueRet, err := Eval(catchAll.Body, rescueEnv)
-		return rescueRet, err
-	}
-
 	return nil, err
 }
 
-func getAncestors(obj object.RubyObject) []string {
-	class := obj.Class()
-	if c, ok := obj.(object.RubyClass); ok {
-		class = c
+// rescueMatches reports whether exception is kind of one of the exception
+// classes of rescue. A rescue without classes matches any StandardError.
+func rescueMatches(rescue *ast.RescueBlock, exception object.RubyObject, env object.Environment) (bool, error) {
+	var classes []object.RubyObject
+	for _, cl := range rescue.ExceptionClasses {
+		class, err := Eval(cl, env)
+		if err != nil {
+			return false, errors.WithMessage(err, "eval rescue exception class")
+		}
+		classes = append(classes, class)
 	}
-	var ancestors []string
-	ancestors = append(ancestors, class.Name())
-
-	superClass := class.SuperClass()
-	if superClass != nil {
-		superAncestors := getAncestors(superClass.(object.RubyClassObject))
-		ancestors = append(ancestors, superAncestors...)
+	if len(classes) == 0 {
+		standardError, _ := env.Get("StandardError")
+		classes = append(classes, standardError)
+	}
+	for _, class := range classes {
+		context := &callContext{object.NewCallContext(env, class)}
+		matched, err := object.Send(context, "===", exception)
+		if err != nil {
+			return false, errors.WithMessage(err, "eval rescue match")
+		}
+		if isTruthy(matched) {
+			return true, nil
+		}
 	}
-	return ancestors
+	return false, nil
 }
 
 func isTruthy(obj object.RubyObject) bool {
@@ -28,6 +28,9 @@ func TestEvalIntegerExpression(t *testing.T) {
 	}{
 		{"5", 5},
 		{"10", 10},
+		{"0x1F & 0b11", 3},
+		{"0o17 + 017", 30},
+		{"1_000 - 0d1_0", 990},
 		{"-5", -5},
 		{"-10", -10},
 		{"5 + 5 + 5 + 5 - 10", 10},
@@ -42,6 +45,16 @@ func TestEvalIntegerExpression(t *testing.T) {
 		{"3 * (3 * 3) + 10", 37},
 		{"(5 + 10 * 2 + 15 / 3) * 2 + -10", 50},
 		{"5 % 2", 1},
+		{"2 ** 3 ** 2", 512},
+		{"-2 ** 2", -4},
+		{"2 * 3 ** 2", 18},
+		{"6 & 3 | 8", 10},
+		{"6 ^ 3", 5},
+		{"~5", -6},
+		{"1 << 2 + 1", 8},
+		{"-256 >> 4", -16},
+		{"5[2]", 1},
+		{"x = 3\nx ^= 1\nx >>= 1", 1},
 	}
 
 	for _, tt := range tests {
@@ -51,6 +64,50 @@ func TestEvalIntegerExpression(t *testing.T) {
 	}
 }
 
+func TestEvalFloatExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"1.5", 1.5},
+		{"-2.5", -2.5},
+		{"1.5 + 2", 3.5},
+		{"2 + 1.5", 3.5},
+		{"10 / 4.0", 2.5},
+		{"3 * 0.5 - 1", 0.5},
+		{"7 % 2.5", 2.0},
+		{"1.fdiv(4)", 0.25},
+		{"3.to_f", 3.0},
+		{"2.5.round", 3},
+		{"3.14159.round(2)", 3.14},
+		{"3.7.floor", 3},
+		{"3.2.ceil", 4},
+		{"-3.9.to_i", -3},
+		{"1 < 1.5", true},
+		{"2 == 2.0", true},
+		{"x = 0.0 / 0; x.nan?", true},
+		{"x = 1.0 / 0; x.infinite?", 1},
+		{"1.5.infinite?", nil},
+		{"1e20.to_s", "1.0e+20"},
+		{"2.0 ** 3", 8.0},
+		{"2 ** 0.5", 1.4142135623730951},
+		{"4.0 ** 0.5", 2.0},
+		{"(-2.5).abs", 2.5},
+		{"x = 2.5\n-x", -2.5},
+		{"7.5.divmod(2)", []string{"3", "1.5"}},
+		{"7.5.div(2)", 3},
+		{"7.div(2.0)", 3},
+		{"x = -7\nx.div(2)", -4},
+		{"7.5.modulo(2)", 1.5},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
 func TestEvalBooleanExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -99,6 +156,9 @@ func TestBangOperator(t *testing.T) {
 		{"!!true", true},
 		{"!!false", false},
 		{"!!5", true},
+		{"not true", false},
+		{"not 5", false},
+		{"not 1 == 2", true},
 	}
 
 	for _, tt := range tests {
@@ -108,6 +168,46 @@ func TestBangOperator(t *testing.T) {
 	}
 }
 
+func TestLogicalOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"false && raise('not evaluated')", false},
+		{"nil && raise('not evaluated')", nil},
+		{"1 && 2", 2},
+		{"1 || raise('not evaluated')", 1},
+		{"nil || 2", 2},
+		{"false and raise('not evaluated')", false},
+		{"1 and 2", 2},
+		{"1 or raise('not evaluated')", 1},
+		{"false or 2", 2},
+		{"x = false or 2\nx", false},
+		{"x = 1 and 2\nx", 1},
+		{"x = nil\nx = 3 unless x.nil? or false\nx", nil},
+		{"x = raise('failed') rescue 1 and 2\nx", 1},
+		{"def m\nv = yield\nv + 1\nend\nx = m do\n2\nend and 5\nx", 3},
+		{"l = lambda do |y|\ny * 2\nend\nl.call(4)", 8},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+
+	t.Run("raise after or", func(t *testing.T) {
+		_, err := testEval("x = nil or raise('failed')", object.NewMainEnvironment())
+
+		actual, ok := errors.Cause(err).(object.RubyObject)
+		if !ok {
+			t.Logf("Error is not a RubyObject, got %T:%v\n", err, err)
+			t.FailNow()
+		}
+		testExceptionObject(t, actual, "RuntimeError: failed")
+	})
+}
+
 func TestConditionalExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -141,6 +241,547 @@ func TestConditionalExpression(t *testing.T) {
 	}
 }
 
+func TestConditionalExpressionWithElsif(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"x = 1\nif x == 1\n:a\nelsif x == 2\n:b\nelse\n:c\nend", ":a"},
+		{"x = 2\nif x == 1\n:a\nelsif x == 2\n:b\nelse\n:c\nend", ":b"},
+		{"x = 3\nif x == 1\n:a\nelsif x == 2\n:b\nelse\n:c\nend", ":c"},
+		{"x = 3\nif x == 1 then :a\nelsif x == 2 then :b\nend", nil},
+		{"x = 4\nif x < 2 then :a\nelsif x < 3 then :b\nelsif x < 5 then :c\nend", ":c"},
+		{"x = 3\nif x == 1 then :a elsif x == 2 then :b else :c end", ":c"},
+		{"x = 2\nif x == 1 then :a elsif x == 2 then :b else :c end", ":b"},
+		{"x = 3\nunless x == 3 then :a else :b end", ":b"},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input)
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestLoopExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"x = 1\nwhile x < 5\nx += 1\nend\nx", 5},
+		{"x = 1\nwhile x < 5 do x += 1 end\nx", 5},
+		{"x = 1\nwhile x > 5\nx += 1\nend", nil},
+		{"x = 5\nuntil x == 0\nx -= 1\nend\nx", 0},
+		{"x = 1\nx += 1 while x < 10\nx", 10},
+		{"x = 1\nx *= 2 until x > 100\nx", 128},
+		{"x = 0\nbegin\nx += 1\nend while false\nx", 1},
+		{"x = 0\nbegin\nx += 1\nend until true\nx", 1},
+		{"x = 0\nbegin\nx += 1\nend until x == 3\nx", 3},
+		{"x = 0\nx += 1 while false\nx", 0},
+		{"def f\nx = 0\nwhile true\nx += 1\nreturn x\nend\nend\nf", 1},
+		{"x = 1 > 2 ? 3 : 4\nx", 4},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestJumpExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"while true\nbreak\nend", nil},
+		{"x = 0\nwhile true\nx += 1\nbreak x * 2 if x == 3\nend", 6},
+		{"x = 0\nwhile true\nx += 1\nbreak x, 2 if x == 3\nend", []string{"3", "2"}},
+		{"x = 0\ny = 0\nwhile x < 5\nx += 1\nnext if x == 3\ny += x\nend\ny", 12},
+		{"x = 0\nc = 0\nuntil x == 3\nx += 1\nc += 1\nredo if c == 2\nend\nc", 3},
+		{"def f\n(1..10).each do |i|\nbreak i * 10 if i == 4\nend\nend\nf", 40},
+		{"def f\n(1..3).each do |i|\nbreak\nend\n:after\nend\nf", ":after"},
+		{"def f\nyield\nend\nf do\nnext 42\n1\nend", 42},
+		{"def f\nx = yield\nx + 1\nend\nf do\nbreak 42\nend", 42},
+		{"def f\nyield\nend\nx = 0\nf do\nx += 1\nredo if x < 3\nx\nend", 3},
+		{"while true\nx = (1..3).each do |i|\nbreak i * 5\nend\nbreak x\nend", 5},
+		{"def f(&b)\nb.call\n5\nend\nf { break 7 }", 7},
+		{"def f(&b)\n(1..3).each(&b)\n5\nend\nf { break 7 }", 7},
+		{"pr = proc { break 7 }\nbegin\npr.call\nrescue LocalJumpError\n:rescued\nend", ":rescued"},
+		{"def f(x)\nx.call\n5\nrescue LocalJumpError => e\n:rescued\nend\nf(proc { break 7 })", ":rescued"},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestAliasAndUndef(t *testing.T) {
+	classes := `
+class A
+  def name
+    "a"
+  end
+  def name=(value)
+    @name = value
+  end
+  def assigned
+    @name
+  end
+  alias full_name name
+  alias :set_name= :name=
+  alias_method "title", :name
+end
+class B < A
+  def name
+    "b"
+  end
+end
+`
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"A.new.full_name", "a"},
+		{"A.new.title", "a"},
+		{"a = A.new\na.set_name = 'x'\na.assigned", "x"},
+		{"B.new.full_name", "a"},
+		{"class B\n  alias full_name name\nend\nB.new.full_name", "b"},
+		{"class B\n  remove_method :name\nend\nB.new.name", "a"},
+		{"class B\n  undef name\nend\nbegin\n  B.new.name\nrescue NoMethodError\n  :undefined\nend", ":undefined"},
+		{"class B\n  undef_method :name\nend\nA.new.name", "a"},
+		{"class B\n  undef name\nend\nclass B\n  def name\n    'c'\n  end\nend\nB.new.name", "c"},
+		{"def foo\n  1\nend\nalias bar foo\nbar", 1},
+		{"begin\n  class A\n    alias x y\n  end\nrescue NameError => e\n  e.to_s\nend", "undefined method `y' for class `A'"},
+		{"begin\n  class A\n    undef y\n  end\nrescue NameError => e\n  e.to_s\nend", "undefined method `y' for class `A'"},
+		{"begin\n  class B\n    remove_method :full_name\n  end\nrescue NameError => e\n  e.to_s\nend", "method `full_name' not defined in B"},
+		{"$old = 1\nalias $new $old\n$new", 1},
+		{"$old = 1\nalias $new $old\n$new = 2\n$old", 2},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(classes+tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestSuperExpression(t *testing.T) {
+	classes := `
+class A
+  def initialize(name)
+    @name = name
+  end
+  def name
+    @name
+  end
+  def greet(greeting, punct = "!")
+    greeting + " " + @name + punct
+  end
+  def each_value
+    yield @name
+  end
+end
+class B < A
+  def initialize(name, suffix)
+    super(name + suffix)
+  end
+  def greet(greeting, punct = "?")
+    greeting = "Hey"
+    super
+  end
+  def each_value
+    super
+  end
+end
+class C < B
+  def greet(greeting, punct = ".")
+    super(greeting) + "!"
+  end
+  def name
+    super() + "?"
+  end
+end
+`
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"B.new('bob', 'by').name", "bobby"},
+		{"B.new('bob', '').greet('Hi')", "Hey bob?"},
+		{"C.new('carl', '').greet('Hi')", "Hey carl?!"},
+		{"C.new('carl', '').name", "carl?"},
+		{"B.new('bob', '').each_value do |v|\nv + '!'\nend", "bob!"},
+		{"x = B.new('bob', '').each_value do |v|\nbreak 3\nend\nx", 3},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(classes+tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestMethodParameters(t *testing.T) {
+	methods := `
+def positional(a, b = 2, *rest, c)
+  [a, b, rest, c]
+end
+def keywords(a, key:, opt: 5, **others)
+  [a, key, opt, others]
+end
+def block_param(x, &blk)
+  blk
+end
+def block_yield(&blk)
+  yield 3
+end
+class A
+  def kw(a, key: 1)
+    [a, key]
+  end
+end
+class B < A
+  def kw(a, key: 2)
+    super
+  end
+end
+`
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"positional(1, 9)", []string{"1", "2", "[]", "9"}},
+		{"positional(1, 9, 8)", []string{"1", "9", "[]", "8"}},
+		{"positional(1, 2, 3, 4, 5)", []string{"1", "2", "[3, 4]", "5"}},
+		{"x = [1, 2, 3]\npositional(*x)", []string{"1", "2", "[]", "3"}},
+		{"positional(0, *[1, 2], 3)", []string{"0", "1", "[2]", "3"}},
+		{"keywords(1, key: 3)", []string{"1", "3", "5", "{}"}},
+		{"keywords 1, key: 3, opt: 4", []string{"1", "3", "4", "{}"}},
+		{"keywords(1, key: 3, z: 4)[3][:z]", 4},
+		{"h = {key: 7}\nkeywords(1, **h)[1]", 7},
+		{"keywords(1, {key: 8})[1]", 8},
+		{"block_param(1) == nil", true},
+		{"block_yield { |x| x * 2 }", 6},
+		{"b = block_param(1) { |x| x * 3 }\nblock_yield(&b)", 9},
+		{"B.new.kw(1)", []string{"1", "2"}},
+		{"B.new.kw(1, key: 4)", []string{"1", "4"}},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(methods+tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestProcs(t *testing.T) {
+	methods := `
+def lambda_return
+  l = -> { return 1 }
+  l.call
+  2
+end
+def proc_return
+  pr = proc { return 1 }
+  pr.call
+  2
+end
+def make_proc
+  proc { return 1 }
+end
+`
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"add = ->(a, b) { a + b }\nadd.call(1, 2)", 3},
+		{"add = ->(a, b) { a + b }\nadd.(1, 2)", 3},
+		{"add = ->(a, b) { a + b }\nadd[1, 2]", 3},
+		{"add = lambda { |a, b| a + b }\nadd.call(2, 2)", 4},
+		{"sq = -> x { x * x }\nsq.call(3)", 9},
+		{"l = -> do\n5\nend\nl.call", 5},
+		{"->(a, b) { a }.arity", 2},
+		{"->(a, b = 1) { a }.arity", -2},
+		{"proc { |a, b| a }.arity", 2},
+		{"-> { 1 }.lambda?", true},
+		{"proc { 1 }.lambda?", false},
+		{"Proc.new { 1 }.lambda?", false},
+		{"proc { |a, b| b }.call(1)", nil},
+		{"proc { |a| a }.call(1, 2)", 1},
+		{"add = ->(a, b, c) { a + b + c }\nadd.curry[1][2][3]", 6},
+		{"add = ->(a, b, c) { a + b + c }\nadd.curry.(1, 2).(3)", 6},
+		{"->(a, b = 1, *c, d:) { a }.parameters[3][0] == :keyreq", true},
+		{"->(a) { a }.parameters[0][0] == :req", true},
+		{"->(a, b: 1) { a + b }.call(1, b: 2)", 3},
+		{"->(a, b: 1) { a + b }.call(1)", 2},
+		{"proc { |a, **o| o[:x] }.call(1, x: 5)", 5},
+		{"def f(a, b = nil)\nb.nil?\nend\nf(1, -> { 2 })", false},
+		{"def f(a, b = nil)\nb.call\nend\nf(1, -> { 2 })", 2},
+		{"proc { |a| a }.parameters[0][0] == :opt", true},
+		{"lambda_return", 2},
+		{"proc_return", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(methods+tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestBlockParameters(t *testing.T) {
+	methods := `
+def pairs
+  yield [1, [2, 3]], 4
+end
+def one
+  yield [5, 6]
+end
+`
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"pairs { |(a, (b, c)), d| [a, b, c, d] }", []string{"1", "2", "3", "4"}},
+		{"pairs { |(a, *b), c| b }", []string{"[2, 3]"}},
+		{"pairs { |(a, b, c)| c }", nil},
+		{"one { |a, b| b }", 6},
+		{"one { |a| a }", []string{"5", "6"}},
+		{"one { |a, *b| b }", []string{"6"}},
+		{"x = 10\none { |a; x| x }", nil},
+		{"x = 10\none { |a; x| x = a }\nx", 10},
+		{"one do |a, b; x, y|\n[x, y]\nend", []string{"nil", "nil"}},
+		{"proc { |(a, b), c| a }.arity", 2},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(methods+tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestSafeNavigation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"x = nil\nx&.foo", nil},
+		{"x = nil\nx&.foo&.bar", nil},
+		{"x = nil\nx&.foo(raise('not evaluated'))", nil},
+		{"x = nil\nx&.each { |a| raise 'not evaluated' }", nil},
+		{"x = nil\nx&.foo = raise('not evaluated')", nil},
+		{"x = 'a'\nx&.+('b')", "ab"},
+		{"nil&.length.to_s", nil},
+		{"x = nil\nx&.foo.bar(raise('not evaluated')).baz", nil},
+		{"x = nil\nx&.foo.bar = raise('not evaluated')", nil},
+		{"x = nil\nx&.foo.bar += 1", nil},
+		{"'ab'&.upcase.to_s", "AB"},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestOperatorMethods(t *testing.T) {
+	classes := `
+class Vector
+  def initialize(x, y)
+    @x = x
+    @y = y
+  end
+  def x
+    @x
+  end
+  def y
+    @y
+  end
+  def +(other)
+    Vector.new(@x + other.x, @y + other.y)
+  end
+  def ==(other)
+    @x == other.x && @y == other.y
+  end
+  def -@
+    Vector.new(-@x, -@y)
+  end
+  def +@
+    :plus
+  end
+  def !
+    :bang
+  end
+  def ~
+    :tilde
+  end
+  def [](i, j = 0)
+    i == 0 ? @x + j : @y + j
+  end
+  def []=(i, v)
+    if i == 0
+      @x = v
+    else
+      @y = v
+    end
+  end
+  def x=(v)
+    @x = v * 10
+    :ignored
+  end
+end
+`
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"v = Vector.new(1, 2) + Vector.new(3, 4)\nv.x", 4},
+		{"Vector.new(1, 2) == Vector.new(1, 2)", true},
+		{"Vector.new(1, 2) != Vector.new(1, 3)", true},
+		{"v = Vector.new(1, 2)\nw = -v\nw.y", -2},
+		{"v = Vector.new(1, 2)\n+v", ":plus"},
+		{"v = Vector.new(1, 2)\n!v", ":bang"},
+		{"v = Vector.new(1, 2)\n~v", ":tilde"},
+		{"v = Vector.new(1, 2)\nv[1]", 2},
+		{"v = Vector.new(1, 2)\nv[1, 3]", 5},
+		{"v = Vector.new(1, 2)\nv[1] = 7", 7},
+		{"v = Vector.new(1, 2)\nv[1] = 7\nv.y", 7},
+		{"v = Vector.new(1, 2)\nv.x = 3", 3},
+		{"v = Vector.new(1, 2)\nv.x = 3\nv.x", 30},
+		{"!nil", true},
+		{"!Vector", false},
+		{"+3", 3},
+	}
+
+	for _, tt := range tests {
+		env := object.NewMainEnvironment()
+		_, err := testEval(classes, env)
+		checkError(t, err)
+		evaluated, err := testEval(tt.input, env)
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestPercentLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"%w(foo bar)", []string{"foo", "bar"}},
+		{"%w[a\\ b c]", []string{"a b", "c"}},
+		{"%i(foo bar)", []string{":foo", ":bar"}},
+		{"x = 1\n%W(a#{x} b)", []string{"a1", "b"}},
+		{"x = 1\n%I(a#{x})", []string{":a1"}},
+		{"x = 1\n%q(a #{x} (b))", "a #{x} (b)"},
+		{"x = 1\n%Q(a #{x})", "a 1"},
+		{"x = 1\n%(a #{x}\\t)", "a 1\t"},
+		{"x = 7\nz = x %(3)\nz", 1},
+		{"%!a (b!", "a (b"},
+		{"%s(foo)", ":foo"},
+		{"7 % 2", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input)
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestConditionalAssignment(t *testing.T) {
+	classes := `
+class Memo
+  def initialize
+    @calls = 0
+  end
+  def value
+    @value ||= compute
+  end
+  def compute
+    @calls += 1
+    @calls
+  end
+end
+`
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"x ||= 1", 1},
+		{"x = 2\nx ||= raise('not evaluated')", 2},
+		{"x = false\nx ||= 3", 3},
+		{"x = nil\nx &&= raise('not evaluated')", nil},
+		{"x = 2\nx &&= x + 3", 5},
+		{"$memo ||= 4", 4},
+		{"h = {}\nh[:a] ||= 5\nh[:a] ||= 6", 5},
+		{"h = {a: 1}\nh[:a] &&= 7\nh[:a]", 7},
+		{"m = Memo.new\nm.value\nm.value", 1},
+		{"x = nil\nx&.foo ||= 1", nil},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(classes+tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestOperatorAssignmentEvaluatesTargetOnce(t *testing.T) {
+	classes := `
+class Counter
+  def initialize
+    @calls = 0
+    @x = 1
+    @items = [1]
+  end
+  def calls
+    @calls
+  end
+  def tick
+    @calls += 1
+    self
+  end
+  def index
+    @calls += 1
+    0
+  end
+  def items
+    @calls += 1
+    @items
+  end
+  def x
+    @x
+  end
+  def x=(value)
+    @x = value
+  end
+end
+c = Counter.new
+`
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"c.tick.x += 1\n[c.calls, c.x]", []string{"1", "2"}},
+		{"c.tick.x ||= 5\n[c.calls, c.x]", []string{"1", "1"}},
+		{"c.tick.x &&= 5\n[c.calls, c.x]", []string{"1", "5"}},
+		{"c.items[c.index] += 1\n[c.calls, c.items[0]]", []string{"2", "2"}},
+		{"c.items[c.index] ||= 5\n[c.calls, c.items[0]]", []string{"2", "1"}},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(classes+tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
 func TestReturnStatements(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -150,6 +791,7 @@ func TestReturnStatements(t *testing.T) {
 		{"return 10; 9;", 10},
 		{"return 2 * 5; 9;", 10},
 		{"9; return 2 * 5; 9;", 10},
+		{"return 1 if false; 2", 2},
 		{`if 10 > 1
 			if 10 > 1
 				return 10
@@ -180,7 +822,7 @@ func TestErrorHandling(t *testing.T) {
 		},
 		{
 			"-true",
-			"Exception: unknown operator: -BOOLEAN",
+			"NoMethodError: undefined method `-@' for true:TrueClass",
 		},
 		{
 			"true + false;",
@@ -234,6 +876,106 @@ end
 			`,
 			"ArgumentError: wrong number of arguments (given 1, expected 2)",
 		},
+		{
+			"break",
+			"LocalJumpError: Invalid break",
+		},
+		{
+			"def foo\nnext 3\nend\nfoo",
+			"LocalJumpError: Invalid next",
+		},
+		{
+			"def f(x)\nx.call\n5\nend\nf(proc { break 7 })",
+			"LocalJumpError: break from proc-closure",
+		},
+		{
+			"redo",
+			"LocalJumpError: Invalid redo",
+		},
+		{
+			"retry",
+			"LocalJumpError: Invalid retry",
+		},
+		{
+			"super",
+			"RuntimeError: super called outside of method",
+		},
+		{
+			"def foo(a, b = 1); end\nfoo",
+			"ArgumentError: wrong number of arguments (given 0, expected 1..2)",
+		},
+		{
+			"def foo(a, *b); end\nfoo",
+			"ArgumentError: wrong number of arguments (given 0, expected 1+)",
+		},
+		{
+			"def foo(a:, b:); end\nfoo(b: 1)",
+			"ArgumentError: missing keyword: :a",
+		},
+		{
+			"def foo(a:, b:); end\nfoo",
+			"ArgumentError: missing keywords: :a, :b",
+		},
+		{
+			"def foo(a: 1); end\nfoo(c: 1, b: 2)",
+			"ArgumentError: unknown keywords: :b, :c",
+		},
+		{
+			"def foo(&b); end\nx = 1\nfoo(&x)",
+			"TypeError: wrong argument type Integer (expected Proc)",
+		},
+		{
+			"[1].map(&:foo)",
+			"NoMethodError: undefined method `foo' for 1:Integer",
+		},
+		{
+			"add = ->(a, b) { a + b }\nadd.call(1)",
+			"ArgumentError: wrong number of arguments (given 1, expected 2)",
+		},
+		{
+			"2 ** 64",
+			"RangeError: 2 ** 64 exceeds the Integer range",
+		},
+		{
+			"1 << 64",
+			"RangeError: 1 << 64 exceeds the Integer range",
+		},
+		{
+			"0 ** -1",
+			"ZeroDivisionError: divided by 0",
+		},
+		{
+			"->(a:) { a }.call",
+			"ArgumentError: missing keyword: :a",
+		},
+		{
+			"proc { |a: 1| a }.call(b: 2)",
+			"ArgumentError: unknown keyword: :b",
+		},
+		{
+			"def foo(a, &b); end\nfoo(1, -> { 2 })",
+			"ArgumentError: wrong number of arguments (given 2, expected 1)",
+		},
+		{
+			"proc { return 1 }.call",
+			"LocalJumpError: unexpected return",
+		},
+		{
+			"def make_proc\nproc { return 1 }\nend\nmake_proc.call",
+			"LocalJumpError: unexpected return",
+		},
+		{
+			"x = 'a'\nx&.foo = 1",
+			"NoMethodError: undefined method `foo=' for a:String",
+		},
+		{
+			"x = 'a'\nx.foo ||= 1",
+			"NoMethodError: undefined method `foo' for a:String",
+		},
+		{
+			"proc",
+			"ArgumentError: tried to create Proc object without a block",
+		},
 	}
 
 	for _, tt := range tests {
@@ -387,6 +1129,37 @@ end`,
 	}
 }
 
+func TestExceptionHandlingClauses(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"begin\n2\nrescue\n3\nelse\n4\nend", 4},
+		{"begin\nraise 'x'\nrescue\n3\nelse\n4\nend", 3},
+		{"x = 0\nbegin\n2\nensure\nx = 5\nend\nx", 5},
+		{"x = 0\nbegin\nbegin\nraise 'x'\nensure\nx = 5\nend\nrescue\nend\nx", 5},
+		{"begin\n2\nensure\n5\nend", 2},
+		{"def f\nreturn 1\nensure\n$x = 7\nend\nf\n$x", 7},
+		{"def f\nreturn 1\nensure\nreturn 2\nend\nf", 2},
+		{"def f\nraise 'x'\nensure\nreturn 2\nend\nf", 2},
+		{"x = 0\nwhile true\nbegin\nbreak\nensure\nx = 3\nend\nend\nx", 3},
+		{"n = 0\nbegin\nn += 1\nraise 'x' if n < 3\nn\nrescue\nretry\nend", 3},
+		{"begin\nraise ArgumentError.new 'a'\nrescue TypeError, StandardError => e\ne.to_s\nend", "a"},
+		{"begin\nraise 'x'\nrescue => e\nend\ne.to_s", "x"},
+		{"begin\n1 / 0\nrescue ArgumentError\n1\nrescue ZeroDivisionError\n2\nend", 2},
+		{"(raise 'x') rescue 3", 3},
+		{"x = raise('x') rescue 3\nx", 3},
+		{"def f\nraise 'x'\nrescue\n3\nend\nf", 3},
+		{"def f\n1\nrescue\n3\nelse\n4\nend\nf", 4},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
 func TestScopedIdentifierExpression(t *testing.T) {
 	objectClassObject, _ := object.NewMainEnvironment().Get("Object")
 	objectClass := objectClassObject.(object.RubyClassObject)
@@ -522,6 +1295,15 @@ end`,
 			t.Fail()
 		}
 	}
+
+	t.Run("stored on the method receiver", func(t *testing.T) {
+		input := "class Box\ndef set(x)\n@x = x\nself\nend\ndef get\n@x\nend\nend\na = Box.new.set(1)\nb = Box.new.set(2)\n[a.get(), b.get()]"
+
+		evaluated, err := testEval(input, object.NewMainEnvironment())
+		checkError(t, err)
+
+		testObject(t, evaluated, []string{"1", "2"})
+	})
 }
 
 func TestAssignment(t *testing.T) {
@@ -1305,6 +2087,73 @@ func TestStringLiteral(t *testing.T) {
 	}
 }
 
+func TestCharacterLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"?a", "a"},
+		{"?a + ?b", "ab"},
+		{`?\n`, "\n"},
+		{`?\s`, " "},
+		{`?\C-a`, "\x01"},
+		{`?\M-\C-a`, "\x81"},
+		{`?\u{1F600}`, "😀"},
+		{`x = 1; x > 0 ? ?y : ?n`, "y"},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input)
+		checkError(t, err)
+		testStringObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestStringInterpolation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`x = 3; "x is #{x}"`, "x is 3"},
+		{`"#{1 + 2}#{nil}#{true}"`, "3true"},
+		{`"sum: #{[1, 2]}"`, "sum: [1, 2]"},
+		{`x = "in"; "out #{"#{x}side"}"`, "out inside"},
+		{`"tab\tnewline\n"`, "tab\tnewline\n"},
+		{`:"sym#{1}"`, ":sym1"},
+		{`"#{a = 1; a + 1}"`, "2"},
+		{"\"x#{\n  b = 2\n  b * 3\n}y\"", "x6y"},
+		{`"#{a = 1; a + 1}#{a}"`, "21"},
+		{`@name = "Bob"; "Hello #@name!"`, "Hello Bob!"},
+		{`$count = 3; "#$count items"`, "3 items"},
+		{`"xay" =~ /(a)/; "got #$1."`, "got a."},
+		{`"#@missing|"`, "|"},
+		{`'#@name' + "#@ #$ #"`, "#@name#@ #$ #"},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestHeredoc(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x = 3\ny = <<~EOS\n  x is\n    #{x}\nEOS\ny", "x is\n  3\n"},
+		{"<<-EOS + \"!\"\n  foo\n  EOS\n", "  foo\n!"},
+		{"<<~'EOS'\n  #{x}\nEOS\n", "#{x}\n"},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input)
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
 func TestStringConcatenation(t *testing.T) {
 	input := `"Hello" + " " + "World!"`
 
@@ -1437,6 +2286,200 @@ func TestArrayIndexExpressions(t *testing.T) {
 	}
 }
 
+func TestArraySliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"[1, 2, 3, 4, 5][1..3]", []string{"2", "3", "4"}},
+		{"[1, 2, 3, 4, 5][1...3]", []string{"2", "3"}},
+		{"[1, 2, 3, 4, 5][1..-2]", []string{"2", "3", "4"}},
+		{"[1, 2, 3, 4, 5][-2..]", []string{"4", "5"}},
+		{"[1, 2, 3, 4, 5][..1]", []string{"1", "2"}},
+		{"[1, 2, 3, 4, 5][3..10]", []string{"4", "5"}},
+		{"[1, 2, 3, 4, 5][5..]", []string{}},
+		{"[1, 2, 3, 4, 5][3..1]", []string{}},
+		{"[1, 2, 3, 4, 5][6..]", nil},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input)
+		checkError(t, err)
+		if tt.expected == nil {
+			testNilObject(t, evaluated)
+			continue
+		}
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestRangeLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"(1..5).to_a", []string{"1", "2", "3", "4", "5"}},
+		{"(1...5).to_a", []string{"1", "2", "3", "4"}},
+		{"x = 2; (x..x + 2).to_a", []string{"2", "3", "4"}},
+		{"(1..).first(2)", []string{"1", "2"}},
+		{"(..5).include?(-10)", true},
+		{"(1...5).cover?(5)", false},
+		{"(1..10).step(4)", []string{"1", "5", "9"}},
+		{"(1..5).size", 5},
+		{"a = []; (1..3).each do |i|; a.push(i * 2); end; a", []string{"2", "4", "6"}},
+		{"(1..5).to_s", "1..5"},
+		{"(1...).to_s", "1..."},
+		{"(..1).to_s", "..1"},
+		{"(1..3) == (1..3)", true},
+		{"(1..3) == (1...3)", false},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestRegexLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"hello world" =~ /wor/`, 6},
+		{`"hello world" =~ /xyz/`, nil},
+		{`"hello" !~ /xyz/`, true},
+		{`/l+/ =~ "hello"`, 2},
+		{`n = 2; "a2b" =~ /a#{n}b/`, 0},
+		{`"HELLO" =~ /hello/i`, 0},
+		{`"John Smith" =~ /(\w+) (\w+)/; $2`, "Smith"},
+		{`"John Smith" =~ /(\w+) (\w+)/; $~[1]`, "John"},
+		{`"John" =~ /(\w+)/; "x" =~ /y/; $1`, nil},
+		{`m = "2020-05".match(/(?<year>\d+)-(?<mon>\d+)/); m[:mon]`, "05"},
+		{`"2020-05".match(/(?<year>\d+)/)["year"]`, "2020"},
+		{`"abc".match?(/b/)`, true},
+		{`"a1b22c333".scan(/\d+/)`, []string{"1", "22", "333"}},
+		{`"hello".sub(/l/, "L")`, "heLlo"},
+		{`"hello".gsub(/l/, "L")`, "heLLo"},
+		{`"hello".sub(/(e)(l)/, "\\2\\1")`, "hlelo"},
+		{`"john smith".gsub(/(?<w>o|i)/, "<\\k<w>>")`, "j<o>hn sm<i>th"},
+		{`"cat hat".gsub(/[ch]at/, {"cat" => "dog", "hat" => "cap"})`, "dog cap"},
+		{`"a-b-c".gsub("-") { |m| m + m }`, "a--b--c"},
+		{`"ab".gsub(/b/) { $~.pre_match }`, "aa"},
+		{"x = 4; y = x /2 + 1; y", 3},
+		{"re = /\n  (\\d+) # digits\n  -(\\d+)\n/x\n\"12-34\" =~ re\n$2", "34"},
+		{"def m\n\"x\" =~ /x/\nend\n\"ab\" =~ /(b)/\nm\n$1", "b"},
+		{"def m\n$~\nend\n\"ab\" =~ /(b)/\nm", nil},
+		{"def m\n\"ab\" =~ /(b)/\n$1\nend\nm", "b"},
+		{"def m\nproc { \"ab\" =~ /(b)/ }.call\n$1\nend\nm", "b"},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestCaseExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"case 3\nwhen String then 1\nwhen Integer then 2\nend", 2},
+		{"case 3\nwhen 1, 2 then :low\nwhen 3, 4 then :high\nend", ":high"},
+		{`case "run"` + "\nwhen \"stop\" then 1\nwhen \"run\" then 2\nend", 2},
+		{"case :b\nwhen :a then 1\nelse 3\nend", 3},
+		{"case 7\nwhen 1..5 then :in\nelse :out\nend", ":out"},
+		{"case 2.5\nwhen 1..5 then :in\nelse :out\nend", ":in"},
+		{`case "hello"` + "\nwhen /^h(.)/ then $1\nend", "e"},
+		{"case 9\nwhen 1 then :a\nend", nil},
+		{"x = 5\ncase\nwhen x < 3 then :small\nwhen x < 10 then :medium\nend", ":medium"},
+		{"class Even\ndef ===(other)\nother % 2 == 0\nend\nend\ncase 4\nwhen Even.new then :even\nelse :odd\nend", ":even"},
+		{"class Foo\nend\ncase Foo.new\nwhen Foo then true\nend", true},
+		{"small = [1, 2]\ncase 2\nwhen *small then :small\nelse :big\nend", ":small"},
+		{"case 'b'\nwhen 'x', *['a', 'b'] then :found\nend", ":found"},
+		{"case 5\nwhen *[1..3, Float] then :a\nwhen *[Integer] then :b\nend", ":b"},
+		{"case 1\nwhen *nil then :a\nelse :none\nend", ":none"},
+		{"case\nwhen *[nil, false] then :a\nwhen *[nil, 1] then :b\nend", ":b"},
+		{"Integer === 3", true},
+		{"String === 3", false},
+		{"(1..3) === 2", true},
+		{":a === :a", true},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestPatternMatching(t *testing.T) {
+	point := "class Point\ndef initialize(x, y)\n@x = x\n@y = y\nend\ndef deconstruct\n[@x, @y]\nend\ndef deconstruct_keys(keys)\n{x: @x, y: @y}\nend\nend\n"
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"case 5\nin String then :s\nin Integer then :i\nend", ":i"},
+		{"case :b\nin :a | :b then 1\nend", 1},
+		{"case 7\nin 1..5 then :in\nelse :out\nend", ":out"},
+		{"case [1, 2]\nin [a, b] then a + b\nend", 3},
+		{"case [1, 2, 3]\nin [1] then :one\nin [1, *rest] then rest\nend", []string{"2", "3"}},
+		{"case [1, 2, 3]\nin [*, 3] then :last\nend", ":last"},
+		{"case [1, 42, :x, 4]\nin [*, Symbol => s, *post] then post\nend", []string{"4"}},
+		{"case [1, 2]\nin [*, 5, *] then :five\nelse :none\nend", ":none"},
+		{"case [2, :x]\nin [Integer => a, Symbol] if a > 2 then :big\nin [Integer, _] then :small\nend", ":small"},
+		{"case 2\nin Integer => a unless a > 2 then a\nend", 2},
+		{"x = 5\ncase 5\nin ^x then :pinned\nend", ":pinned"},
+		{"x = 4\ncase 5\nin ^x then :pinned\nin x then x\nend", 5},
+		{"case {name: \"bob\", age: 3}\nin {name: String => name} then name\nend", "bob"},
+		{"case {a: 1}\nin {a:, b:} then :both\nin {a:} then a\nend", 1},
+		{"case {a: 1, b: 2}\nin {a: 1, **rest} then rest[:b]\nend", 2},
+		{"case {a: 1, b: 2}\nin {a: 1, **nil} then :exact\nelse :more\nend", ":more"},
+		{"case {a: 1}\nin {} then :empty\nin a: Integer then :int\nend", ":int"},
+		{"case {}\nin {} then :empty\nend", ":empty"},
+		{"case 1\nin [] then :array\nin {} then :hash\nelse :other\nend", ":other"},
+		{point + "case Point.new(1, 0)\nin Point(x:, y: 0) then x\nend", 1},
+		{point + "case Point.new(1, 2)\nin Point[a, b] then a + b\nend", 3},
+		{point + "case Point.new(1, 2)\nin [x, y] then y\nend", 2},
+		{point + "case [1, 2]\nin Point[x, y] then :point\nelse :array\nend", ":array"},
+		{"5 in Integer", true},
+		{"5 in String", false},
+		{"[1, [2, 3]] in [_, [_, x]]\nx", 3},
+		{"{a: 1} => {a:}\na", 1},
+		{"5 => Integer", nil},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+
+	errorTests := []struct {
+		input   string
+		message string
+	}{
+		{"case 5\nin String then 1\nend", "NoMatchingPatternError: 5"},
+		{"[1] => [String]", "NoMatchingPatternError: [1]"},
+		{"class Foo\ndef deconstruct; 1; end\nend\nFoo.new in [x]", "TypeError: deconstruct must return Array"},
+		{"class Foo\ndef deconstruct_keys(k); 1; end\nend\nFoo.new in {x:}", "TypeError: deconstruct_keys must return Hash"},
+	}
+
+	for _, tt := range errorTests {
+		_, err := testEval(tt.input, object.NewMainEnvironment())
+
+		actual, ok := errors.Cause(err).(object.RubyObject)
+		if !ok {
+			t.Logf("Error is not a RubyObject, got %T:%v\n", err, err)
+			t.Fail()
+			continue
+		}
+		testExceptionObject(t, actual, tt.message)
+	}
+}
+
 func TestNilExpression(t *testing.T) {
 	input := "nil"
 	evaluated, err := testEval(input)
@@ -1587,6 +2630,154 @@ func TestKeyword__File__(t *testing.T) {
 	}
 }
 
+func TestSourceLocationKeywords(t *testing.T) {
+	tests := []struct {
+		filename string
+		input    string
+		expected interface{}
+	}{
+		{"", "x = 1\n__LINE__", 2},
+		{"", "def foo\n  __method__\nend\nfoo", ":foo"},
+		{"", "def foo\n  (1..1).each do |x|\n    return __method__\n  end\nend\nfoo", ":foo"},
+		{"", "__method__", nil},
+		{"", "__dir__", nil},
+		{"/tmp/dir/some_file.rb", "__dir__", "/tmp/dir"},
+	}
+
+	for _, tt := range tests {
+		program, err := parser.ParseFile(token.NewFileSet(), tt.filename, tt.input, 0)
+		checkError(t, err)
+		evaluated, err := Eval(program, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestBlockPass(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`["a", "b"].map(&:upcase)`, []string{"A", "B"}},
+		{"m = :upcase\n[\"a\"].map(&m)", []string{"A"}},
+		{"@m = :upcase\n[\"a\"].map(&@m)", []string{"A"}},
+		{"$m = :upcase\n[\"a\"].map(&$m)", []string{"A"}},
+		{`["a"].map(&->(x) { x + "!" })`, []string{"a!"}},
+		{"class Shout\ndef to_proc\n->(x) { x + \"!\" }\nend\nend\n[\"a\"].map(&Shout.new)", []string{"a!"}},
+		{"def foo(&b); b; end\nfoo(&:upcase).call(\"a\")", "A"},
+		{"def foo(&b); b; end\nfoo(&nil)", nil},
+		{":upcase.to_proc.call(\"a\")", "A"},
+		{"x = 6\nx & 3", 2},
+		{"def apply(a, b, &f)\nf.call(a, b)\nend\napply(7, 5, &:-)", 2},
+		{"def apply(a, b, &f)\nf.call(a, b)\nend\napply(2, 5, &:**)", 32},
+		{"def twice(x)\nx * 2\nend\n[1, 2].map(&method(:twice))", []string{"2", "4"}},
+		{"five = 5\n[1, 2].map(&five.method(:-))", []string{"4", "3"}},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+
+	t.Run("to_proc not returning a proc", func(t *testing.T) {
+		input := "class Foo\ndef to_proc; 1; end\nend\n[1].map(&Foo.new)"
+
+		_, err := testEval(input, object.NewMainEnvironment())
+
+		actual, ok := errors.Cause(err).(object.RubyObject)
+		if !ok {
+			t.Logf("Error is not a RubyObject, got %T:%v\n", err, err)
+			t.FailNow()
+		}
+		testExceptionObject(t, actual, "TypeError: can't convert Foo to Proc (Foo#to_proc gives Integer)")
+	})
+}
+
+func TestMethodObjects(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"5.method(:+).call(3)", 8},
+		{"5.method(:to_s).call", "5"},
+		{"m = 5.method(:+)\nm[1]", 6},
+		{"5.method(:+).name", ":+"},
+		{"5.method(:+).receiver", 5},
+		{"class Foo\ndef initialize(n)\n@n = n\nend\ndef add(x)\n@n + x\nend\nend\nFoo.new(10).method(:add).to_proc.call(2)", 12},
+		{"def greet\n:hi\nend\nmethod(:greet).call", ":hi"},
+		{"[:+, :[]=, :<=>, :!]", []string{":+", ":[]=", ":<=>", ":!"}},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestProgramHooks(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"$x = [1]\nBEGIN { $x = [] }\n$x", []string{"1"}},
+		{"BEGIN { $x = 2 }\n$x", 2},
+		{"$x = 1\nEND { $x = $x * 10 }\nEND { $x = $x + 2 }\n$x", 1},
+		{"BEGIN { a = 3 }\na", 3},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input, object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+
+	t.Run("END blocks run in reverse order", func(t *testing.T) {
+		env := object.NewMainEnvironment()
+		_, err := testEval("$x = 1\nEND { $x = $x * 10 }\nEND { $x = $x + 2 }", env)
+		checkError(t, err)
+
+		x, _ := env.Get("$x")
+		testObject(t, x, 30)
+	})
+
+	t.Run("END blocks run after errors", func(t *testing.T) {
+		env := object.NewMainEnvironment()
+		_, err := testEval("END { $x = 1 }\nraise 'boom'", env)
+		if err == nil {
+			t.Errorf("Expected error, got nil")
+		}
+
+		x, _ := env.Get("$x")
+		testObject(t, x, 1)
+	})
+}
+
+func TestDataSection(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"DATA.gets", "foo\n"},
+		{"DATA.read", "foo\nbar\n"},
+		{"DATA.gets\nDATA.gets", "bar\n"},
+		{"$lines = ''\nDATA.each_line { |l| $lines = l + $lines }\n$lines", "bar\nfoo\n"},
+		{"DATA.read\nDATA.eof?", true},
+	}
+
+	for _, tt := range tests {
+		evaluated, err := testEval(tt.input+"\n__END__\nfoo\nbar\n", object.NewMainEnvironment())
+		checkError(t, err)
+		testObject(t, evaluated, tt.expected)
+	}
+
+	_, err := testEval("DATA", object.NewMainEnvironment())
+	if err == nil {
+		t.Errorf("Expected DATA to be undefined without __END__")
+	}
+}
+
 func testExceptionObject(t *testing.T, obj object.RubyObject, errorMessage string) {
 	t.Helper()
 	if !IsError(obj) {
@@ -1638,6 +2829,8 @@ func testObject(t *testing.T, exp object.RubyObject, expected interface{}) bool
 		return testIntegerObject(t, exp, int64(v))
 	case int64:
 		return testIntegerObject(t, exp, v)
+	case float64:
+		return testFloatObject(t, exp, v)
 	case string:
 		if strings.HasPrefix(v, ":") {
 			return testSymbolObject(t, exp, strings.TrimPrefix(v, ":"))
@@ -1678,6 +2871,28 @@ func testBooleanObject(t *testing.T, obj object.RubyObject, expected bool) bool
 	return true
 }
 
+func testFloatObject(t *testing.T, obj object.RubyObject, expected float64) bool {
+	t.Helper()
+	result, ok := obj.(*object.Float)
+	if !ok {
+		t.Errorf(
+			"object is not Float. got=%T (%+v)",
+			obj,
+			obj,
+		)
+		return false
+	}
+	if result.Value != expected {
+		t.Errorf(
+			"object has wrong value. got=%v, want=%v",
+			result.Value,
+			expected,
+		)
+		return false
+	}
+	return true
+}
+
 func testIntegerObject(t *testing.T, obj object.RubyObject, expected int64) bool {
 	t.Helper()
 	result, ok := obj.(*object.Integer)
@@ -0,0 +1,271 @@
+package evaluator
+
+import (
+	"github.com/goruby/goruby/ast"
+	"github.com/goruby/goruby/object"
+	"github.com/pkg/errors"
+)
+
+func evalCaseMatchExpression(ce *ast.CaseMatchExpression, env object.Environment) (object.RubyObject, error) {
+	subject, err := Eval(ce.Subject, env)
+	if err != nil {
+		return nil, errors.WithMessage(err, "eval case subject")
+	}
+	for _, in := range ce.Ins {
+		matched, err := matchPattern(in.Pattern, subject, env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "eval in pattern")
+		}
+		if !matched {
+			continue
+		}
+		if in.Guard != nil {
+			guard, err := Eval(in.Guard, env)
+			if err != nil {
+				return nil, errors.WithMessage(err, "eval in guard")
+			}
+			if isTruthy(guard) == in.IsUnlessGuard() {
+				continue
+			}
+		}
+		return Eval(in.Consequence, env)
+	}
+	if ce.Else != nil {
+		return Eval(ce.Else, env)
+	}
+	return nil, errors.WithStack(object.NewNoMatchingPatternError(subject))
+}
+
+func evalPatternMatchExpression(pm *ast.PatternMatchExpression, env object.Environment) (object.RubyObject, error) {
+	value, err := Eval(pm.Value, env)
+	if err != nil {
+		return nil, errors.WithMessage(err, "eval pattern match value")
+	}
+	matched, err := matchPattern(pm.Pattern, value, env)
+	if err != nil {
+		return nil, errors.WithMessage(err, "eval pattern")
+	}
+	if pm.IsTest() {
+		return nativeBoolToBooleanObject(matched), nil
+	}
+	if !matched {
+		return nil, errors.WithStack(object.NewNoMatchingPatternError(value))
+	}
+	return object.NIL, nil
+}
+
+// matchPattern reports whether value matches pattern. The variables bound by
+// the pattern are set within env.
+func matchPattern(pattern ast.Pattern, value object.RubyObject, env object.Environment) (bool, error) {
+	switch pattern := pattern.(type) {
+	case *ast.ValuePattern:
+		return matchValue(pattern.Value, value, env)
+	case *ast.PinPattern:
+		return matchValue(pattern.Value, value, env)
+	case *ast.VariablePattern:
+		env.Set(pattern.Name.Value, value)
+		return true, nil
+	case *ast.AlternativePattern:
+		for _, alternative := range pattern.Alternatives {
+			matched, err := matchPattern(alternative, value, env)
+			if err != nil || matched {
+				return matched, err
+			}
+		}
+		return false, nil
+	case *ast.CapturePattern:
+		matched, err := matchPattern(pattern.Pattern, value, env)
+		if err != nil || !matched {
+			return false, err
+		}
+		env.Set(pattern.Name.Value, value)
+		return true, nil
+	case *ast.ArrayPattern:
+		return matchArrayPattern(pattern, value, env)
+	case *ast.FindPattern:
+		return matchFindPattern(pattern, value, env)
+	case *ast.HashPattern:
+		return matchHashPattern(pattern, value, env)
+	default:
+		return false, errors.Errorf("Unknown pattern type %T", pattern)
+	}
+}
+
+// matchValue reports whether the evaluated expression is case equal to value
+func matchValue(expression ast.Expression, value object.RubyObject, env object.Environment) (bool, error) {
+	expected, err := Eval(expression, env)
+	if err != nil {
+		return false, err
+	}
+	context := &callContext{object.NewCallContext(env, expected)}
+	result, err := object.Send(context, "===", value)
+	if err != nil {
+		return false, err
+	}
+	return isTruthy(result), nil
+}
+
+// matchConstant reports whether value satisfies the class constraint of an
+// array or hash pattern, which is always the case without constant
+func matchConstant(constant ast.Expression, value object.RubyObject, env object.Environment) (bool, error) {
+	if constant == nil {
+		return true, nil
+	}
+	return matchValue(constant, value, env)
+}
+
+// deconstruct returns the elements of value matched by array and find
+// patterns. It returns false if value does not respond to deconstruct.
+func deconstruct(value object.RubyObject, env object.Environment) ([]object.RubyObject, bool, error) {
+	if !object.RespondTo(value, "deconstruct") {
+		return nil, false, nil
+	}
+	context := &callContext{object.NewCallContext(env, value)}
+	result, err := object.Send(context, "deconstruct")
+	if err != nil {
+		return nil, false, err
+	}
+	array, ok := result.(*object.Array)
+	if !ok {
+		return nil, false, errors.WithStack(object.NewTypeError("deconstruct must return Array"))
+	}
+	return array.Elements, true, nil
+}
+
+func matchArrayPattern(pattern *ast.ArrayPattern, value object.RubyObject, env object.Environment) (bool, error) {
+	matched, err := matchConstant(pattern.Constant, value, env)
+	if err != nil || !matched {
+		return false, err
+	}
+	elements, ok, err := deconstruct(value, env)
+	if err != nil || !ok {
+		return false, err
+	}
+	pre, post := len(pattern.Pre), len(pattern.Post)
+	if pattern.Rest == nil && len(elements) != pre || len(elements) < pre+post {
+		return false, nil
+	}
+	rest := elements[pre : len(elements)-post]
+	for i, p := range pattern.Pre {
+		matched, err := matchPattern(p, elements[i], env)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	for i, p := range pattern.Post {
+		matched, err := matchPattern(p, elements[pre+len(rest)+i], env)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	bindSplat(pattern.Rest, rest, env)
+	return true, nil
+}
+
+func matchFindPattern(pattern *ast.FindPattern, value object.RubyObject, env object.Environment) (bool, error) {
+	matched, err := matchConstant(pattern.Constant, value, env)
+	if err != nil || !matched {
+		return false, err
+	}
+	elements, ok, err := deconstruct(value, env)
+	if err != nil || !ok {
+		return false, err
+	}
+	size := len(pattern.Patterns)
+	for start := 0; start+size <= len(elements); start++ {
+		matched, err := matchPatterns(pattern.Patterns, elements[start:start+size], env)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			bindSplat(pattern.Pre, elements[:start], env)
+			bindSplat(pattern.Post, elements[start+size:], env)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// matchPatterns reports whether every value matches the pattern at the same
+// index
+func matchPatterns(patterns []ast.Pattern, values []object.RubyObject, env object.Environment) (bool, error) {
+	for i, p := range patterns {
+		matched, err := matchPattern(p, values[i], env)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// bindSplat sets the variable of splat to an array holding elements, unless
+// the splat is anonymous
+func bindSplat(splat *ast.SplatPattern, elements []object.RubyObject, env object.Environment) {
+	if splat == nil || splat.Name == nil {
+		return
+	}
+	env.Set(splat.Name.Value, object.NewArray(append([]object.RubyObject{}, elements...)...))
+}
+
+func matchHashPattern(pattern *ast.HashPattern, value object.RubyObject, env object.Environment) (bool, error) {
+	matched, err := matchConstant(pattern.Constant, value, env)
+	if err != nil || !matched {
+		return false, err
+	}
+	if !object.RespondTo(value, "deconstruct_keys") {
+		return false, nil
+	}
+	keys := make([]object.RubyObject, len(pattern.Pairs))
+	for i, pair := range pattern.Pairs {
+		keys[i] = &object.Symbol{Value: pair.Key.Value}
+	}
+	// all keys are requested if the rest is bound
+	var requested object.RubyObject = object.NewArray(keys...)
+	if pattern.Rest != nil {
+		requested = object.NIL
+	}
+	context := &callContext{object.NewCallContext(env, value)}
+	result, err := object.Send(context, "deconstruct_keys", requested)
+	if err != nil {
+		return false, err
+	}
+	hash, ok := result.(*object.Hash)
+	if !ok {
+		return false, errors.WithStack(object.NewTypeError("deconstruct_keys must return Hash"))
+	}
+	entries := hash.Map()
+	// `{}` matches empty hashes only
+	isEmptyPattern := len(pattern.Pairs) == 0 && pattern.Rest == nil
+	if (pattern.NoRest || isEmptyPattern) && len(entries) != len(pattern.Pairs) {
+		return false, nil
+	}
+	for i, pair := range pattern.Pairs {
+		val, ok := hash.Get(keys[i])
+		if !ok {
+			return false, nil
+		}
+		if pair.Value == nil {
+			env.Set(pair.Key.Value, val)
+			continue
+		}
+		matched, err := matchPattern(pair.Value, val, env)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	if pattern.Rest != nil {
+		matchedKeys := make(map[string]bool)
+		for _, pair := range pattern.Pairs {
+			matchedKeys[pair.Key.Value] = true
+		}
+		rest := &object.Hash{}
+		for k, v := range entries {
+			if symbol, ok := k.(*object.Symbol); ok && matchedKeys[symbol.Value] {
+				continue
+			}
+			rest.Set(k, v)
+		}
+		env.Set(pattern.Rest.Name.Value, rest)
+	}
+	return true, nil
+}
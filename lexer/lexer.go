@@ -3,6 +3,8 @@ package lexer
 import (
 	"bytes"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -34,6 +36,7 @@ func New(input string) *Lexer {
 		input:  input,
 		state:  startLexer,
 		tokens: make(chan token.Token, 2), // Two token sufficient.
+		locals: make(map[string]bool),
 	}
 	return l
 }
@@ -47,6 +50,34 @@ type Lexer struct {
 	width     int              // width of last rune read from input.
 	tokens    chan token.Token // channel of scanned tokens.
 	lastToken token.Token      // lastToken stores the last token emitted by the lexer
+	literals  []*stringLiteral // stack of string literals currently lexed
+	heredocs  int              // position to continue at after the current line, if heredocs are pending
+	locals    map[string]bool  // local variables assigned or declared as parameter so far
+	params    token.Type       // token ending the parameter list being lexed, token.ILLEGAL if none
+}
+
+// stringLiteral holds the state of a string literal which is currently lexed.
+// The lexer keeps them on a stack, as string literals can be nested within
+// the interpolated code of other string literals.
+type stringLiteral struct {
+	term        rune // the rune terminating the literal
+	interpolate bool // whether the literal supports interpolation and escapes
+	braceDepth  int  // open braces within the current interpolation
+	lineStart   bool // whether the next rune starts a new line of a heredoc
+	regexp      bool // whether the literal is a regular expression
+	heredoc     *heredoc
+	open        rune // the opening rune of a paired percent literal delimiter
+	depth       int  // nested pairs of delimiters within a percent literal
+	words       bool // whether the literal is a %w or %i word list
+	inWord      bool // whether a word of a word list is being lexed
+}
+
+// heredoc holds the positions of a heredoc body within the input.
+type heredoc struct {
+	end    int // start of the line containing the terminator
+	resume int // position after the line containing the terminator
+	dedent int // indentation to remove from each line of a squiggly heredoc
+	origin int // position to continue lexing at after the heredoc identifier
 }
 
 // NextToken will return the next token processed from the lexer.
@@ -78,6 +109,18 @@ func (l *Lexer) HasNext() bool {
 // emit passes a token back to the client.
 func (l *Lexer) emit(t token.Type) {
 	token := token.NewToken(t, l.input[l.start:l.pos], l.start)
+	l.trackParameters(t)
+	l.lastToken = token
+	l.tokens <- token
+	l.start = l.pos
+}
+
+// emitLiteral passes a token with the given literal back to the client. It
+// is used for tokens whose literal differs from the raw input, e.g. strings
+// with escape sequences.
+func (l *Lexer) emitLiteral(t token.Type, literal string) {
+	token := token.NewToken(t, literal, l.start)
+	l.trackParameters(t)
 	l.lastToken = token
 	l.tokens <- token
 	l.start = l.pos
@@ -132,10 +175,17 @@ func startLexer(l *Lexer) StateFn {
 		return lexGlobal
 	case '\n':
 		l.emit(token.NEWLINE)
+		if l.heredocs > 0 && l.currentLiteral() == nil {
+			l.pos = l.heredocs
+			l.start = l.pos
+			l.heredocs = 0
+		}
 		return startLexer
 	case '\'':
 		return lexSingleQuoteString
 	case '"':
+		l.ignore()
+		l.literals = append(l.literals, &stringLiteral{term: '"', interpolate: true})
 		return lexString
 	case ':':
 		p := l.peek()
@@ -149,17 +199,40 @@ func startLexer(l *Lexer) StateFn {
 			return startLexer
 		}
 		l.emit(token.SYMBEG)
+		if op := l.operatorSymbolName(); op != "" {
+			// operator method names as symbols, e.g. `:+`
+			l.pos += len(op)
+			l.emitLiteral(token.IDENT, op)
+		}
 		return startLexer
 	case '.':
+		if l.peek() == '.' {
+			l.next()
+			if l.peek() == '.' {
+				l.next()
+				l.emit(token.DOT3)
+				return startLexer
+			}
+			l.emit(token.DOT2)
+			return startLexer
+		}
 		l.emit(token.DOT)
 		return startLexer
 	case '=':
 		if l.peek() == '=' {
 			l.next()
+			if l.peek() == '=' {
+				l.next()
+				l.emit(token.CASEEQ)
+				return startLexer
+			}
 			l.emit(token.EQ)
 		} else if l.peek() == '>' {
 			l.next()
 			l.emit(token.HASHROCKET)
+		} else if l.peek() == '~' {
+			l.next()
+			l.emit(token.MATCH)
 		} else {
 			l.emit(token.ASSIGN)
 		}
@@ -178,19 +251,27 @@ func startLexer(l *Lexer) StateFn {
 			l.emit(token.SUBASSIGN)
 			return startLexer
 		}
+		if l.peek() == '>' {
+			l.next()
+			l.emit(token.LAMBDA)
+			return startLexer
+		}
 		l.emit(token.MINUS)
 		return startLexer
 	case '!':
 		if l.peek() == '=' {
 			l.next()
 			l.emit(token.NOTEQ)
+		} else if l.peek() == '~' {
+			l.next()
+			l.emit(token.NMATCH)
 		} else {
 			l.emit(token.BANG)
 		}
 		return startLexer
 	case '?':
 		p := l.peek()
-		if isWhitespace(p) {
+		if isWhitespace(p) || !l.isOperandExpected() {
 			l.emit(token.QMARK)
 			return startLexer
 		}
@@ -201,6 +282,11 @@ func startLexer(l *Lexer) StateFn {
 		}
 		return lexCharacterLiteral
 	case '/':
+		if l.isRegexpStart() {
+			l.emit(token.REGEXBEG)
+			l.literals = append(l.literals, &stringLiteral{term: '/', interpolate: true, regexp: true})
+			return lexString
+		}
 		if l.peek() == '=' {
 			l.next()
 			l.emit(token.DIVASSIGN)
@@ -209,6 +295,16 @@ func startLexer(l *Lexer) StateFn {
 		l.emit(token.SLASH)
 		return startLexer
 	case '*':
+		if l.peek() == '*' {
+			l.next()
+			if l.peek() == '=' {
+				l.next()
+				l.emit(token.POWASSIGN)
+				return startLexer
+			}
+			l.emit(token.POW)
+			return startLexer
+		}
 		if l.peek() == '=' {
 			l.next()
 			l.emit(token.MULASSIGN)
@@ -217,6 +313,9 @@ func startLexer(l *Lexer) StateFn {
 		l.emit(token.ASTERISK)
 		return startLexer
 	case '%':
+		if l.isPercentLiteralStart() {
+			return lexPercentLiteral
+		}
 		if l.peek() == '=' {
 			l.next()
 			l.emit(token.MODASSIGN)
@@ -227,10 +326,25 @@ func startLexer(l *Lexer) StateFn {
 	case '&':
 		if p := l.peek(); p == '&' {
 			l.next()
+			if l.peek() == '=' {
+				l.next()
+				l.emit(token.LOGICALANDASSIGN)
+				return startLexer
+			}
 			l.emit(token.LOGICALAND)
 			return startLexer
 		}
-		if p := l.peek(); isLetter(p) {
+		if p := l.peek(); p == '=' {
+			l.next()
+			l.emit(token.ANDASSIGN)
+			return startLexer
+		}
+		if p := l.peek(); p == '.' {
+			l.next()
+			l.emit(token.ANDDOT)
+			return startLexer
+		}
+		if l.isBlockPassStart() {
 			l.emit(token.CAPTURE)
 			return startLexer
 		}
@@ -249,6 +363,14 @@ func startLexer(l *Lexer) StateFn {
 		}
 		if l.peek() == '<' {
 			l.next()
+			if l.isHeredocStart() {
+				return lexHeredoc
+			}
+			if l.peek() == '=' {
+				l.next()
+				l.emit(token.LSHIFTASSIGN)
+				return startLexer
+			}
 			l.emit(token.LSHIFT)
 			return startLexer
 		}
@@ -260,6 +382,16 @@ func startLexer(l *Lexer) StateFn {
 			l.emit(token.GTE)
 			return startLexer
 		}
+		if l.peek() == '>' {
+			l.next()
+			if l.peek() == '=' {
+				l.next()
+				l.emit(token.RSHIFTASSIGN)
+				return startLexer
+			}
+			l.emit(token.RSHIFT)
+			return startLexer
+		}
 		l.emit(token.GT)
 		return startLexer
 	case '(':
@@ -269,9 +401,19 @@ func startLexer(l *Lexer) StateFn {
 		l.emit(token.RPAREN)
 		return startLexer
 	case '{':
+		if lit := l.currentLiteral(); lit != nil {
+			lit.braceDepth++
+		}
 		l.emit(token.LBRACE)
 		return startLexer
 	case '}':
+		if lit := l.currentLiteral(); lit != nil {
+			if lit.braceDepth == 0 {
+				l.emit(token.INTERPEND)
+				return lexString
+			}
+			lit.braceDepth--
+		}
 		l.emit(token.RBRACE)
 		return startLexer
 	case '[':
@@ -298,14 +440,35 @@ func startLexer(l *Lexer) StateFn {
 		}
 		if p := l.peek(); p == '|' {
 			l.next()
+			if l.peek() == '=' {
+				l.next()
+				l.emit(token.LOGICALORASSIGN)
+				return startLexer
+			}
 			l.emit(token.LOGICALOR)
 			return startLexer
 		}
+		if p := l.peek(); p == '=' {
+			l.next()
+			l.emit(token.ORASSIGN)
+			return startLexer
+		}
 		l.emit(token.PIPE)
 		return startLexer
 	case '@':
 		l.emit(token.AT)
 		return startLexer
+	case '^':
+		if l.peek() == '=' {
+			l.next()
+			l.emit(token.XORASSIGN)
+			return startLexer
+		}
+		l.emit(token.CARET)
+		return startLexer
+	case '~':
+		l.emit(token.TILDE)
+		return startLexer
 
 	default:
 		if isDigit(r) {
@@ -333,32 +496,245 @@ func lexIdentifier(l *Lexer) StateFn {
 	}
 	l.backup()
 	literal := l.input[l.start:l.pos]
-	l.emit(token.LookupIdent(literal))
+	// labels, e.g. `key: value`
+	if l.peek() == ':' && !strings.HasPrefix(l.input[l.pos:], "::") && l.lastToken.Type != token.QMARK {
+		if l.params != token.ILLEGAL {
+			// keyword parameter, i.e. `def m(key: 1)`
+			l.locals[literal] = true
+		}
+		l.next()
+		l.emitLiteral(token.LABEL, literal)
+		return startLexer
+	}
+	typ := token.LookupIdent(literal)
+	// keywords used as method names, e.g. `x.next`
+	if l.lastToken.Type == token.DOT && typ.IsKeyword() && typ != token.CLASS {
+		typ = token.IDENT
+	}
+	// keywords used as symbols, e.g. `:in`
+	if l.lastToken.Type == token.SYMBEG && typ.IsKeyword() {
+		typ = token.IDENT
+	}
+	if typ == token.KEYWORD__END__ {
+		if l.isDataSectionStart() {
+			// the rest of the input is data and is not lexed
+			l.emit(typ)
+			l.pos = len(l.input)
+			l.start = l.pos
+			return startLexer
+		}
+		typ = token.IDENT
+	}
+	if typ == token.IDENT && l.isLocalDeclaration() {
+		l.locals[literal] = true
+	}
+	l.emit(typ)
 	return startLexer
 }
 
+// assignmentPattern matches the input following the target of an assignment,
+// including the remaining targets of a multiple assignment, i.e. `, b = 1`
+var assignmentPattern = regexp.MustCompile(
+	`^[ \t]*(,[ \t]*\*?[a-z_][a-zA-Z0-9_]*[ \t]*)*(=($|[^=~>])|(\|\||&&|\*\*|<<|>>|[-+*/%|&^])=)`,
+)
+
+// isLocalDeclaration reports whether the identifier just read declares a
+// local variable, either as a parameter or as target of an assignment. Like
+// in MRI a name is known as local variable from its declaration onwards,
+// though the lexer does not track the scopes of methods and blocks.
+func (l *Lexer) isLocalDeclaration() bool {
+	switch l.lastToken.Type {
+	case token.DEF, token.DOT, token.ANDDOT:
+		// method names and method calls
+		return false
+	}
+	return l.params != token.ILLEGAL || assignmentPattern.MatchString(l.input[l.pos:])
+}
+
+// trackParameters follows the parameter lists of methods, blocks and lambdas
+// with t being the next token emitted.
+func (l *Lexer) trackParameters(t token.Type) {
+	switch {
+	case l.params == token.ILLEGAL:
+		switch {
+		case t == token.DEF:
+			l.params = token.NEWLINE
+		case t == token.LAMBDA:
+			l.params = token.LBRACE
+		case t == token.PIPE && (l.lastToken.Type == token.DO || l.lastToken.Type == token.LBRACE):
+			l.params = token.PIPE
+		}
+	case t == l.params,
+		l.params == token.NEWLINE && t == token.SEMICOLON,
+		l.params == token.LBRACE && t == token.DO:
+		l.params = token.ILLEGAL
+	}
+}
+
+// isDataSectionStart reports whether the `__END__` just read is on a line of
+// its own and thus ends the program.
+func (l *Lexer) isDataSectionStart() bool {
+	if l.start > 0 && l.input[l.start-1] != '\n' {
+		return false
+	}
+	rest := l.input[l.pos:]
+	return rest == "" || rest[0] == '\n' || strings.HasPrefix(rest, "\r\n")
+}
+
+// isBlockPassStart reports whether the `&` just read passes a block from the
+// following operand, like in `&block`, `&:upcase` or `&@handler`, instead of
+// being the binary and operator.
+func (l *Lexer) isBlockPassStart() bool {
+	rest := l.input[l.pos:]
+	if rest == "" {
+		return false
+	}
+	switch r := rune(rest[0]); {
+	case isLetter(r), r == '@', r == '$':
+		return true
+	case r == ':':
+		return !strings.HasPrefix(rest, "::")
+	default:
+		return strings.HasPrefix(rest, "->")
+	}
+}
+
+// operatorSymbolNames lists the operator method names which can follow the
+// `:` of a symbol. Longer names precede their prefixes.
+var operatorSymbolNames = []string{
+	"[]=", "[]", "<=>", "===", "==", "=~", "!=", "!~", "**", "+@", "-@",
+	"<<", ">>", "<=", ">=", "+", "-", "*", "/", "%", "<", ">", "!", "&",
+	"|", "^", "~",
+}
+
+// operatorSymbolName returns the operator method name at the current position
+// if it forms a symbol on its own, like in `:+` or `inject(&:*)`. It returns
+// an empty string otherwise, e.g. for `:-1` or `:foo`.
+func (l *Lexer) operatorSymbolName() string {
+	rest := l.input[l.pos:]
+	for _, op := range operatorSymbolNames {
+		if !strings.HasPrefix(rest, op) {
+			continue
+		}
+		if len(rest) == len(op) {
+			return op
+		}
+		switch rest[len(op)] {
+		case ' ', '\t', '\r', '\n', ')', ']', '}', ',', ';', '.':
+			return op
+		}
+		return ""
+	}
+	return ""
+}
+
+// integerPrefixes maps the prefixes of integer literals in another notation
+// than plain decimal to the function validating their digits
+var integerPrefixes = map[rune]func(rune) bool{
+	'x': isHexDigit,
+	'X': isHexDigit,
+	'o': isOctalDigit,
+	'O': isOctalDigit,
+	'b': isBinaryDigit,
+	'B': isBinaryDigit,
+	'd': isDigit,
+	'D': isDigit,
+}
+
 func lexDigit(l *Lexer) StateFn {
-	r := l.next()
-	for isDigitOrUnderscore(r) {
-		r = l.next()
+	if l.input[l.start] == '0' {
+		if valid, ok := integerPrefixes[l.peek()]; ok {
+			l.next()
+			if err := l.acceptDigits(valid, false); err != nil {
+				return l.errorf("%s", err)
+			}
+			l.emit(token.INT)
+			return startLexer
+		}
+		if isDigitOrUnderscore(l.peek()) {
+			if err := l.acceptDigits(isOctalDigit, true); err != nil {
+				return l.errorf("%s", err)
+			}
+			if isDigit(l.peek()) {
+				return l.errorf("Invalid octal digit")
+			}
+			l.emit(token.INT)
+			return startLexer
+		}
 	}
-	l.backup()
-	l.emit(token.INT)
+	if err := l.acceptDigits(isDigit, true); err != nil {
+		return l.errorf("%s", err)
+	}
+	typ := token.INT
+	if l.peek() == '.' && l.pos+1 < len(l.input) && isDigit(rune(l.input[l.pos+1])) {
+		l.next()
+		if err := l.acceptDigits(isDigit, false); err != nil {
+			return l.errorf("%s", err)
+		}
+		typ = token.FLOAT
+	}
+	if r := l.peek(); r == 'e' || r == 'E' {
+		exponent := l.pos + 1
+		if exponent < len(l.input) && (l.input[exponent] == '+' || l.input[exponent] == '-') {
+			exponent++
+		}
+		if exponent < len(l.input) && isDigit(rune(l.input[exponent])) {
+			l.pos = exponent
+			if err := l.acceptDigits(isDigit, false); err != nil {
+				return l.errorf("%s", err)
+			}
+			typ = token.FLOAT
+		}
+	}
+	l.emit(typ)
 	return startLexer
 }
 
+// acceptDigits consumes a run of digits which may be separated by single
+// underscores. It returns an error if no digit is found or an underscore is
+// not followed by a digit. leadingDigit reports whether a digit has been
+// consumed already, like the 0 of an octal literal `0_17`.
+func (l *Lexer) acceptDigits(valid func(rune) bool, leadingDigit bool) error {
+	digits, underscore := leadingDigit, false
+	for {
+		r := l.next()
+		switch {
+		case valid(r):
+			digits, underscore = true, false
+		case r == '_' && digits && !underscore:
+			underscore = true
+		case !digits:
+			return fmt.Errorf("numeric literal without digits")
+		case r == '_' || underscore:
+			return fmt.Errorf("trailing '_' in number")
+		default:
+			l.backup()
+			return nil
+		}
+	}
+}
+
 func lexSingleQuoteString(l *Lexer) StateFn {
 	l.ignore()
-	r := l.next()
-
-	for r != '\'' {
-		r = l.next()
+	var value bytes.Buffer
+	for {
+		r := l.next()
+		switch r {
+		case eof:
+			return l.errorf("unterminated string meets end of file")
+		case '\\':
+			if p := l.peek(); p == '\'' || p == '\\' {
+				r = l.next()
+			}
+		case '\'':
+			l.backup()
+			l.emitLiteral(token.STRING, value.String())
+			l.next()
+			l.ignore()
+			return startLexer
+		}
+		value.WriteRune(r)
 	}
-	l.backup()
-	l.emit(token.STRING)
-	l.next()
-	l.ignore()
-	return startLexer
 }
 
 func lexCharacterLiteral(l *Lexer) StateFn {
@@ -367,28 +743,570 @@ func lexCharacterLiteral(l *Lexer) StateFn {
 	if isWhitespace(r) && r != '\t' && r != '\v' && r != '\f' && r != '\r' {
 		return l.errorf("invalid character syntax; use ?\\s")
 	}
+	var value bytes.Buffer
 	if r == '\\' {
-		r = l.next()
+		if err := l.readEscape(&value); err != nil {
+			return l.errorf("%s", err)
+		}
+	} else {
+		value.WriteRune(r)
 	}
-	if p := l.peek(); !isWhitespace(p) && !isExpressionDelimiter(p) {
+	if p := l.peek(); isLetter(p) || isDigit(p) {
 		return l.errorf("unexpected '?'")
 	}
-	l.emit(token.STRING)
+	l.emitLiteral(token.STRING, value.String())
 	return startLexer
 }
 
+// currentLiteral returns the innermost string literal the lexer is in, or nil
+// if it is not within a string literal
+func (l *Lexer) currentLiteral() *stringLiteral {
+	if len(l.literals) == 0 {
+		return nil
+	}
+	return l.literals[len(l.literals)-1]
+}
+
+// lexString lexes the content of the current string literal up to its
+// terminator or the next interpolation. Interpolated code is lexed by
+// startLexer until the matching closing brace hands control back.
 func lexString(l *Lexer) StateFn {
+	lit := l.currentLiteral()
+	var value bytes.Buffer
+	for {
+		if h := lit.heredoc; h != nil {
+			if l.pos >= h.end {
+				l.emitLiteral(token.STRING, value.String())
+				l.pos = h.origin
+				l.ignore()
+				l.literals = l.literals[:len(l.literals)-1]
+				return startLexer
+			}
+			if lit.lineStart {
+				for i := 0; i < h.dedent && isWhitespace(l.peek()); i++ {
+					l.next()
+				}
+				lit.lineStart = false
+			}
+		}
+		r := l.next()
+		switch {
+		case r == eof:
+			return l.errorf("unterminated string meets end of file")
+		case r == lit.open && lit.open != 0:
+			lit.depth++
+			value.WriteRune(r)
+			lit.inWord = lit.words
+		case r == lit.term && lit.depth > 0:
+			lit.depth--
+			value.WriteRune(r)
+			lit.inWord = lit.words
+		case lit.words && (r == lit.term || unicode.IsSpace(r)):
+			if lit.inWord {
+				l.backup()
+				l.emitLiteral(token.STRING, value.String())
+				lit.inWord = false
+				return lexString
+			}
+			if r == lit.term {
+				l.start = l.pos - l.width
+				l.emit(token.WORDSEND)
+				l.literals = l.literals[:len(l.literals)-1]
+				return startLexer
+			}
+			l.ignore()
+		case r == lit.term && lit.heredoc == nil:
+			l.backup()
+			l.emitLiteral(token.STRING, value.String())
+			l.next()
+			l.literals = l.literals[:len(l.literals)-1]
+			if lit.regexp {
+				for strings.ContainsRune(regexpOptions, l.peek()) {
+					l.next()
+				}
+				l.emit(token.REGEXEND)
+				return startLexer
+			}
+			l.ignore()
+			return startLexer
+		case r == '\\' && lit.regexp:
+			p := l.next()
+			switch p {
+			case eof:
+				return l.errorf("unterminated regexp meets end of file")
+			case lit.term:
+			default:
+				value.WriteRune('\\')
+			}
+			value.WriteRune(p)
+		case r == '\\' && lit.interpolate:
+			if err := l.readEscape(&value); err != nil {
+				return l.errorf("%s", err)
+			}
+			lit.inWord = lit.words
+		case r == '\\' && lit.heredoc == nil:
+			p := l.peek()
+			if p == lit.term || p == lit.open || p == '\\' || lit.words && unicode.IsSpace(p) {
+				r = l.next()
+			}
+			value.WriteRune(r)
+			lit.inWord = lit.words
+		case r == '#' && l.peek() == '{' && lit.interpolate:
+			l.backup()
+			l.emitLiteral(token.STRING, value.String())
+			l.next()
+			l.next()
+			l.emit(token.INTERPBEG)
+			lit.braceDepth = 0
+			lit.inWord = lit.words
+			return startLexer
+		case r == '#' && lit.interpolate && l.isVariableInterpolation():
+			l.backup()
+			l.emitLiteral(token.STRING, value.String())
+			l.next()
+			l.emit(token.INTERPBEG)
+			lit.inWord = lit.words
+			return lexInterpolatedVariable
+		default:
+			value.WriteRune(r)
+			lit.lineStart = r == '\n'
+			lit.inWord = lit.words
+		}
+	}
+}
+
+// isVariableInterpolation reports whether the `#` just read starts the
+// interpolation shorthand of an instance or a global variable, like in
+// "#@name" or "#$0".
+func (l *Lexer) isVariableInterpolation() bool {
+	rest := l.input[l.pos:]
+	if len(rest) < 2 {
+		return false
+	}
+	switch rest[0] {
+	case '@':
+		return isLetter(rune(rest[1]))
+	case '$':
+		return isLetter(rune(rest[1])) || isDigit(rune(rest[1]))
+	default:
+		return false
+	}
+}
+
+// lexInterpolatedVariable lexes the variable of the interpolation shorthand
+// `#@ivar` or `#$gvar`, which is equivalent to `#{@ivar}` or `#{$gvar}`.
+func lexInterpolatedVariable(l *Lexer) StateFn {
+	if l.peek() == '@' {
+		l.next()
+		l.emit(token.AT)
+	}
+	return lexInterpolatedVariableName
+}
+
+// lexInterpolatedVariableName lexes the name of an interpolated instance
+// variable or a global and hands control back to lexString.
+func lexInterpolatedVariableName(l *Lexer) StateFn {
+	typ := token.IDENT
+	if l.peek() == '$' {
+		l.next()
+		typ = token.GLOBAL
+	}
+	if isDigit(l.peek()) {
+		for isDigit(l.peek()) {
+			l.next()
+		}
+	} else {
+		for r := l.peek(); isLetter(r) || isDigit(r); r = l.peek() {
+			l.next()
+		}
+	}
+	l.emit(typ)
+	l.emit(token.INTERPEND)
+	return lexString
+}
+
+// regexpOptions contains the runes allowed as options after a regexp literal
+const regexpOptions = "imxo"
+
+// isRegexpStart reports whether a `/` starts a regexp literal instead of being
+// the division operator. This is the case when the previous token cannot end
+// an operand, or for a method name followed by a space and a `/` which is not
+// followed by a space, e.g. `split /,/`. Regexp literals spanning lines are
+// only recognized where an expression starts, e.g. after `=`, `(` or `,`.
+func (l *Lexer) isRegexpStart() bool {
+	if !l.isOperandExpected() {
+		return false
+	}
+	multiline := false
+	switch l.lastToken.Type {
+	case token.ILLEGAL, token.NEWLINE, token.SEMICOLON, token.ASSIGN,
+		token.LPAREN, token.LBRACKET, token.COMMA, token.MATCH, token.NMATCH,
+		token.WHEN, token.IDENT:
+		multiline = true
+	}
+	rest := l.input[l.pos:]
+	for i := 0; i < len(rest) && (multiline || rest[i] != '\n'); i++ {
+		switch rest[i] {
+		case '\\':
+			i++
+		case '/':
+			return true
+		}
+	}
+	return false
+}
+
+// isOperandExpected reports whether the rune just read starts an operand
+// rather than being a binary operator. This is the case when the previous
+// token cannot end an operand, or for an identifier followed by a space and
+// an operator which is not followed by a space, e.g. `split /,/`, unless the
+// identifier is a known local variable.
+func (l *Lexer) isOperandExpected() bool {
+	switch l.lastToken.Type {
+	case token.IDENT:
+		if l.locals[l.lastToken.Literal] {
+			return false
+		}
+		spaceBefore := l.start > 0 && isWhitespace(rune(l.input[l.start-1]))
+		p := l.peek()
+		if !spaceBefore || unicode.IsSpace(p) || p == '=' {
+			return false
+		}
+	case token.CONST, token.GLOBAL, token.INT, token.FLOAT, token.STRING,
+		token.RPAREN, token.RBRACKET, token.RBRACE, token.REGEXEND,
+		token.NIL, token.TRUE, token.FALSE, token.SELF, token.END,
+		token.WORDSEND, token.DEF, token.DOT, token.ALIAS, token.UNDEF:
+		return false
+	}
+	return true
+}
+
+// percentLiteralTypes contains the runes allowed as type of a percent literal
+const percentLiteralTypes = "qQwWiIs"
+
+// percentLiteralPairs maps the opening delimiters of percent literals which
+// are closed by a different rune to their closing rune.
+var percentLiteralPairs = map[rune]rune{
+	'(': ')',
+	'[': ']',
+	'{': '}',
+	'<': '>',
+}
+
+// isPercentLiteralStart reports whether a `%` starts a percent literal like
+// `%w(a b)` instead of being the modulo operator. As for regexp literals, the
+// `%` must be in operand position, so that `x %(3)` with x being a local
+// variable remains a modulo. A `%=` is always lexed as an assignment operator.
+func (l *Lexer) isPercentLiteralStart() bool {
+	if !l.isOperandExpected() {
+		return false
+	}
+	rest := l.input[l.pos:]
+	if strings.HasPrefix(rest, "=") {
+		return false
+	}
+	if rest != "" && strings.ContainsRune(percentLiteralTypes, rune(rest[0])) {
+		rest = rest[1:]
+	}
+	if rest == "" {
+		return false
+	}
+	r := rune(rest[0])
+	return r < utf8.RuneSelf && (unicode.IsPunct(r) || unicode.IsSymbol(r))
+}
+
+// lexPercentLiteral lexes the type and the opening delimiter of a percent
+// literal and continues with its content. A literal opened with a bracket is
+// terminated by the matching closing bracket, while any other delimiter
+// terminates the literal itself.
+func lexPercentLiteral(l *Lexer) StateFn {
+	typ := 'Q'
+	if strings.ContainsRune(percentLiteralTypes, l.peek()) {
+		typ = l.next()
+	}
+	lit := &stringLiteral{term: l.next(), interpolate: unicode.IsUpper(typ)}
+	if term, ok := percentLiteralPairs[lit.term]; ok {
+		lit.open, lit.term = lit.term, term
+	}
+	switch typ {
+	case 'w', 'W':
+		l.emit(token.WORDSBEG)
+		lit.words = true
+	case 'i', 'I':
+		l.emit(token.SYMBOLSBEG)
+		lit.words = true
+	case 's':
+		l.emitLiteral(token.SYMBEG, ":")
+	default:
+		l.ignore()
+	}
+	l.literals = append(l.literals, lit)
+	return lexString
+}
+
+// isHeredocStart reports whether the input following a `<<` starts a heredoc
+// identifier, i.e. `<<ID`, `<<-ID`, `<<~ID` or their quoted forms. As for
+// regexp literals, the `<<` must be in operand position, so that `a<<B` and
+// `x <<EOS` with x being a local variable remain a left shift.
+func (l *Lexer) isHeredocStart() bool {
+	if !l.isOperandExpected() {
+		return false
+	}
+	rest := l.input[l.pos:]
+	if strings.HasPrefix(rest, "~") || strings.HasPrefix(rest, "-") {
+		rest = rest[1:]
+		if rest == "" {
+			return false
+		}
+		return rest[0] == '"' || rest[0] == '\'' || isLetter(rune(rest[0]))
+	}
+	if rest == "" {
+		return false
+	}
+	return rest[0] == '"' || rest[0] == '\'' || unicode.IsUpper(rune(rest[0]))
+}
+
+// lexHeredoc lexes a heredoc identifier and continues with its body, which
+// starts on the line following the identifier, or after the body of a
+// preceding heredoc on the same line. Once the body is lexed the lexer returns
+// to the position after the identifier and skips the body at the end of the
+// line.
+func lexHeredoc(l *Lexer) StateFn {
+	indent, squiggly := false, false
+	switch l.peek() {
+	case '~':
+		indent, squiggly = true, true
+		l.next()
+	case '-':
+		indent = true
+		l.next()
+	}
+	interpolate := true
+	var id string
+	if q := l.peek(); q == '"' || q == '\'' {
+		l.next()
+		end := strings.IndexAny(l.input[l.pos:], string(q)+"\n")
+		if end == -1 || l.input[l.pos+end] == '\n' {
+			return l.errorf("unterminated here document identifier")
+		}
+		id = l.input[l.pos : l.pos+end]
+		l.pos += end + 1
+		interpolate = q == '"'
+	} else {
+		start := l.pos
+		for r := l.next(); isLetter(r) || isDigit(r); r = l.next() {
+		}
+		l.backup()
+		id = l.input[start:l.pos]
+	}
+
+	h := &heredoc{origin: l.pos}
+	bodyStart := l.heredocs
+	if bodyStart == 0 {
+		newline := strings.IndexByte(l.input[l.pos:], '\n')
+		if newline == -1 {
+			return l.errorf("can't find string %q anywhere before EOF", id)
+		}
+		bodyStart = l.pos + newline + 1
+	}
+	h.end = -1
+	for pos := bodyStart; pos < len(l.input); {
+		lineEnd := strings.IndexByte(l.input[pos:], '\n')
+		next := pos + lineEnd + 1
+		if lineEnd == -1 {
+			lineEnd = len(l.input) - pos
+			next = len(l.input)
+		}
+		line := strings.TrimSuffix(l.input[pos:pos+lineEnd], "\r")
+		if indent {
+			line = strings.TrimLeft(line, " \t")
+		}
+		if line == id {
+			h.end, h.resume = pos, next
+			break
+		}
+		pos = next
+	}
+	if h.end == -1 {
+		return l.errorf("can't find string %q anywhere before EOF", id)
+	}
+	if squiggly {
+		h.dedent = heredocIndentation(l.input[bodyStart:h.end])
+	}
+
+	l.heredocs = h.resume
+	l.literals = append(l.literals, &stringLiteral{
+		interpolate: interpolate,
+		lineStart:   true,
+		heredoc:     h,
+	})
+	l.pos = bodyStart
 	l.ignore()
+	return lexString
+}
+
+// heredocIndentation returns the indentation of the least indented line in
+// body, ignoring lines consisting only of whitespace.
+func heredocIndentation(body string) int {
+	dedent := -1
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		if strings.TrimSpace(trimmed) == "" {
+			continue
+		}
+		if indentation := len(line) - len(trimmed); dedent == -1 || indentation < dedent {
+			dedent = indentation
+		}
+	}
+	if dedent == -1 {
+		return 0
+	}
+	return dedent
+}
+
+// readEscape reads the escape sequence following a backslash and writes the
+// resulting characters into buf.
+func (l *Lexer) readEscape(buf *bytes.Buffer) error {
 	r := l.next()
+	switch r {
+	case eof:
+		return fmt.Errorf("unterminated string meets end of file")
+	case 'n':
+		buf.WriteRune('\n')
+	case 't':
+		buf.WriteRune('\t')
+	case 's':
+		buf.WriteRune(' ')
+	case 'r':
+		buf.WriteRune('\r')
+	case 'a':
+		buf.WriteRune('\a')
+	case 'b':
+		buf.WriteRune('\b')
+	case 'e':
+		buf.WriteRune(0x1b)
+	case 'f':
+		buf.WriteRune('\f')
+	case 'v':
+		buf.WriteRune('\v')
+	case '\n':
+		// line continuation
+	case 'u':
+		return l.readUnicodeEscape(buf)
+	case 'c':
+		return l.readControlEscape(buf)
+	case 'C':
+		if l.next() != '-' {
+			return fmt.Errorf("invalid escape character syntax")
+		}
+		return l.readControlEscape(buf)
+	case 'M':
+		if l.next() != '-' {
+			return fmt.Errorf("invalid escape character syntax")
+		}
+		c, err := l.readEscapedByte()
+		if err != nil {
+			return err
+		}
+		buf.WriteByte(c | 0x80)
+	case 'x':
+		digits := l.acceptRun(isHexDigit, 2)
+		if digits == "" {
+			return fmt.Errorf("invalid hex escape")
+		}
+		value, _ := strconv.ParseUint(digits, 16, 8)
+		buf.WriteByte(byte(value))
+	case '0', '1', '2', '3', '4', '5', '6', '7':
+		l.backup()
+		digits := l.acceptRun(isOctalDigit, 3)
+		value, _ := strconv.ParseUint(digits, 8, 16)
+		buf.WriteByte(byte(value))
+	default:
+		buf.WriteRune(r)
+	}
+	return nil
+}
 
-	for r != '"' {
-		r = l.next()
+// readControlEscape reads the character of a \cx or \C-x escape and writes
+// the corresponding control character into buf.
+func (l *Lexer) readControlEscape(buf *bytes.Buffer) error {
+	if l.peek() == '?' {
+		l.next()
+		buf.WriteByte(0x7f)
+		return nil
+	}
+	c, err := l.readEscapedByte()
+	if err != nil {
+		return err
+	}
+	buf.WriteByte(c & 0x9f)
+	return nil
+}
+
+// readEscapedByte reads the single byte character a control or meta escape
+// applies to, which may be an escape sequence itself like in `\M-\C-x`.
+func (l *Lexer) readEscapedByte() (byte, error) {
+	var c bytes.Buffer
+	switch r := l.next(); r {
+	case eof:
+		return 0, fmt.Errorf("unterminated string meets end of file")
+	case '\\':
+		if err := l.readEscape(&c); err != nil {
+			return 0, err
+		}
+	default:
+		c.WriteRune(r)
+	}
+	if c.Len() != 1 {
+		return 0, fmt.Errorf("invalid escape character syntax")
+	}
+	return c.Bytes()[0], nil
+}
+
+// readUnicodeEscape reads the codepoints of a \uXXXX or \u{X...} escape and
+// writes them UTF-8 encoded into buf.
+func (l *Lexer) readUnicodeEscape(buf *bytes.Buffer) error {
+	if l.peek() != '{' {
+		digits := l.acceptRun(isHexDigit, 4)
+		if len(digits) != 4 {
+			return fmt.Errorf("invalid Unicode escape")
+		}
+		value, _ := strconv.ParseUint(digits, 16, 32)
+		buf.WriteRune(rune(value))
+		return nil
 	}
-	l.backup()
-	l.emit(token.STRING)
 	l.next()
-	l.ignore()
-	return startLexer
+	for {
+		for l.peek() == ' ' {
+			l.next()
+		}
+		if l.peek() == '}' {
+			l.next()
+			return nil
+		}
+		digits := l.acceptRun(isHexDigit, 6)
+		if digits == "" {
+			return fmt.Errorf("invalid Unicode escape")
+		}
+		value, _ := strconv.ParseUint(digits, 16, 32)
+		if value > unicode.MaxRune {
+			return fmt.Errorf("invalid Unicode codepoint (too large)")
+		}
+		buf.WriteRune(rune(value))
+	}
+}
+
+// acceptRun consumes up to max runes as long as valid returns true and
+// returns the consumed input.
+func (l *Lexer) acceptRun(valid func(rune) bool, max int) string {
+	start := l.pos
+	for i := 0; i < max; i++ {
+		if !valid(l.next()) {
+			l.backup()
+			break
+		}
+	}
+	return l.input[start:l.pos]
 }
 
 func lexGlobal(l *Lexer) StateFn {
@@ -406,7 +1324,20 @@ func lexGlobal(l *Lexer) StateFn {
 		return l.errorf("Illegal character: '%c'", r)
 	}
 
-	for !isWhitespace(r) && !isExpressionDelimiter(r) && r != '.' && r != ',' {
+	if r == '~' {
+		l.emit(token.GLOBAL)
+		return startLexer
+	}
+
+	if isDigit(r) {
+		for isDigit(l.peek()) {
+			l.next()
+		}
+		l.emit(token.GLOBAL)
+		return startLexer
+	}
+
+	for !isWhitespace(r) && !isExpressionDelimiter(r) && !strings.ContainsRune(".,)]}", r) {
 		r = l.next()
 	}
 	l.backup()
@@ -438,6 +1369,18 @@ func isDigit(r rune) bool {
 	return '0' <= r && r <= '9'
 }
 
+func isHexDigit(r rune) bool {
+	return isDigit(r) || 'a' <= r && r <= 'f' || 'A' <= r && r <= 'F'
+}
+
+func isOctalDigit(r rune) bool {
+	return '0' <= r && r <= '7'
+}
+
+func isBinaryDigit(r rune) bool {
+	return r == '0' || r == '1'
+}
+
 func isDigitOrUnderscore(r rune) bool {
 	return isDigit(r) || r == '_'
 }
@@ -125,7 +125,7 @@ $a`
 		{token.NEWLINE, "\n"},
 		{token.STRING, "-"},
 		{token.NEWLINE, "\n"},
-		{token.STRING, "\\n"},
+		{token.STRING, "\n"},
 		{token.NEWLINE, "\n"},
 		{token.QMARK, "?"},
 		{token.IDENT, "foo"},
@@ -364,3 +364,874 @@ $a`
 		}
 	}
 }
+
+func TestLexerStringLiterals(t *testing.T) {
+	tests := []struct {
+		input  string
+		tokens []token.Token
+	}{
+		{
+			`"foo\tbar\n\"baz\"\\"`,
+			[]token.Token{
+				{Type: token.STRING, Literal: "foo\tbar\n\"baz\"\\"},
+			},
+		},
+		{
+			`"é\u{48 49}\x41\101\s"`,
+			[]token.Token{
+				{Type: token.STRING, Literal: "éHIAA "},
+			},
+		},
+		{
+			`"\C-a\ca\c?\M-a\M-\C-a"`,
+			[]token.Token{
+				{Type: token.STRING, Literal: "\x01\x01\x7f\xe1\x81"},
+			},
+		},
+		{
+			`[?a, ?\n, ?\C-a, ?\u{1F600}]`,
+			[]token.Token{
+				{Type: token.LBRACKET, Literal: "["},
+				{Type: token.STRING, Literal: "a"},
+				{Type: token.COMMA, Literal: ","},
+				{Type: token.STRING, Literal: "\n"},
+				{Type: token.COMMA, Literal: ","},
+				{Type: token.STRING, Literal: "\x01"},
+				{Type: token.COMMA, Literal: ","},
+				{Type: token.STRING, Literal: "😀"},
+				{Type: token.RBRACKET, Literal: "]"},
+			},
+		},
+		{
+			`x ?a :b`,
+			[]token.Token{
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.STRING, Literal: "a"},
+				{Type: token.SYMBEG, Literal: ":"},
+				{Type: token.IDENT, Literal: "b"},
+			},
+		},
+		{
+			`(x)?a :b`,
+			[]token.Token{
+				{Type: token.LPAREN, Literal: "("},
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.RPAREN, Literal: ")"},
+				{Type: token.QMARK, Literal: "?"},
+				{Type: token.IDENT, Literal: "a"},
+				{Type: token.SYMBEG, Literal: ":"},
+				{Type: token.IDENT, Literal: "b"},
+			},
+		},
+		{
+			`'it\'s \n'`,
+			[]token.Token{
+				{Type: token.STRING, Literal: `it's \n`},
+			},
+		},
+		{
+			`"foo #{bar} baz"`,
+			[]token.Token{
+				{Type: token.STRING, Literal: "foo "},
+				{Type: token.INTERPBEG, Literal: "#{"},
+				{Type: token.IDENT, Literal: "bar"},
+				{Type: token.INTERPEND, Literal: "}"},
+				{Type: token.STRING, Literal: " baz"},
+			},
+		},
+		{
+			`"#{{1 => "#{x}"}}"`,
+			[]token.Token{
+				{Type: token.STRING, Literal: ""},
+				{Type: token.INTERPBEG, Literal: "#{"},
+				{Type: token.LBRACE, Literal: "{"},
+				{Type: token.INT, Literal: "1"},
+				{Type: token.HASHROCKET, Literal: "=>"},
+				{Type: token.STRING, Literal: ""},
+				{Type: token.INTERPBEG, Literal: "#{"},
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.INTERPEND, Literal: "}"},
+				{Type: token.STRING, Literal: ""},
+				{Type: token.RBRACE, Literal: "}"},
+				{Type: token.INTERPEND, Literal: "}"},
+				{Type: token.STRING, Literal: ""},
+			},
+		},
+		{
+			`"a #@foo b#$bar#$1 #@ #$"`,
+			[]token.Token{
+				{Type: token.STRING, Literal: "a "},
+				{Type: token.INTERPBEG, Literal: "#"},
+				{Type: token.AT, Literal: "@"},
+				{Type: token.IDENT, Literal: "foo"},
+				{Type: token.INTERPEND, Literal: ""},
+				{Type: token.STRING, Literal: " b"},
+				{Type: token.INTERPBEG, Literal: "#"},
+				{Type: token.GLOBAL, Literal: "$bar"},
+				{Type: token.INTERPEND, Literal: ""},
+				{Type: token.STRING, Literal: ""},
+				{Type: token.INTERPBEG, Literal: "#"},
+				{Type: token.GLOBAL, Literal: "$1"},
+				{Type: token.INTERPEND, Literal: ""},
+				{Type: token.STRING, Literal: " #@ #$"},
+			},
+		},
+		{
+			`'#{foo}'`,
+			[]token.Token{
+				{Type: token.STRING, Literal: "#{foo}"},
+			},
+		},
+		{
+			`'#@foo'`,
+			[]token.Token{
+				{Type: token.STRING, Literal: "#@foo"},
+			},
+		},
+		{
+			`"foo`,
+			[]token.Token{
+				{Type: token.ILLEGAL, Literal: "unterminated string meets end of file"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		lexer := New(tt.input)
+		for pos, expected := range tt.tokens {
+			if !lexer.HasNext() {
+				t.Logf("Unexpected EOF at %d for input %q\n", pos, tt.input)
+				t.FailNow()
+			}
+			actual := lexer.NextToken()
+
+			if actual.Type != expected.Type {
+				t.Logf("Expected token with type %q at position %d, got type %q\n", expected.Type, pos, actual.Type)
+				t.Fail()
+			}
+
+			if actual.Literal != expected.Literal {
+				t.Logf("Expected token with literal %q at position %d, got literal %q\n", expected.Literal, pos, actual.Literal)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestLexerHeredocs(t *testing.T) {
+	tests := []struct {
+		input  string
+		tokens []token.Token
+	}{
+		{
+			"x = <<EOS\n  foo\n  bar\nEOS\ny",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.ASSIGN, Literal: "="},
+				{Type: token.STRING, Literal: "  foo\n  bar\n"},
+				{Type: token.NEWLINE, Literal: "\n"},
+				{Type: token.IDENT, Literal: "y"},
+			},
+		},
+		{
+			"x <<EOS\nfoo\nEOS\n",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.STRING, Literal: "foo\n"},
+				{Type: token.NEWLINE, Literal: "\n"},
+			},
+		},
+		{
+			"x = 1\nx <<EOS",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.ASSIGN, Literal: "="},
+				{Type: token.INT, Literal: "1"},
+				{Type: token.NEWLINE, Literal: "\n"},
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.LSHIFT, Literal: "<<"},
+				{Type: token.CONST, Literal: "EOS"},
+			},
+		},
+		{
+			"a<<B\nA << b",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "a"},
+				{Type: token.LSHIFT, Literal: "<<"},
+				{Type: token.CONST, Literal: "B"},
+				{Type: token.NEWLINE, Literal: "\n"},
+				{Type: token.CONST, Literal: "A"},
+				{Type: token.LSHIFT, Literal: "<<"},
+				{Type: token.IDENT, Literal: "b"},
+			},
+		},
+		{
+			"<<-EOS\n  foo\n  EOS\n",
+			[]token.Token{
+				{Type: token.STRING, Literal: "  foo\n"},
+				{Type: token.NEWLINE, Literal: "\n"},
+				{Type: token.EOF, Literal: ""},
+			},
+		},
+		{
+			"<<~EOS\n    foo\\t\n\n      bar\n  EOS\n",
+			[]token.Token{
+				{Type: token.STRING, Literal: "foo\t\n\n  bar\n"},
+				{Type: token.NEWLINE, Literal: "\n"},
+			},
+		},
+		{
+			"<<~EOS.size\n  a #{b}\n  c\nEOS\n",
+			[]token.Token{
+				{Type: token.STRING, Literal: "a "},
+				{Type: token.INTERPBEG, Literal: "#{"},
+				{Type: token.IDENT, Literal: "b"},
+				{Type: token.INTERPEND, Literal: "}"},
+				{Type: token.STRING, Literal: "\nc\n"},
+				{Type: token.DOT, Literal: "."},
+				{Type: token.IDENT, Literal: "size"},
+				{Type: token.NEWLINE, Literal: "\n"},
+			},
+		},
+		{
+			"<<~'EOS'\n  a #{b}\\n\nEOS\n",
+			[]token.Token{
+				{Type: token.STRING, Literal: "a #{b}\\n\n"},
+				{Type: token.NEWLINE, Literal: "\n"},
+			},
+		},
+		{
+			"foo(<<A, <<\"B\")\na\nA\nb\nB\nbar",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "foo"},
+				{Type: token.LPAREN, Literal: "("},
+				{Type: token.STRING, Literal: "a\n"},
+				{Type: token.COMMA, Literal: ","},
+				{Type: token.STRING, Literal: "b\n"},
+				{Type: token.RPAREN, Literal: ")"},
+				{Type: token.NEWLINE, Literal: "\n"},
+				{Type: token.IDENT, Literal: "bar"},
+			},
+		},
+		{
+			"a << b",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "a"},
+				{Type: token.LSHIFT, Literal: "<<"},
+				{Type: token.IDENT, Literal: "b"},
+			},
+		},
+		{
+			"<<EOS\nfoo\n",
+			[]token.Token{
+				{Type: token.ILLEGAL, Literal: `can't find string "EOS" anywhere before EOF`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		lexer := New(tt.input)
+		for pos, expected := range tt.tokens {
+			if !lexer.HasNext() {
+				t.Logf("Unexpected EOF at %d for input %q\n", pos, tt.input)
+				t.FailNow()
+			}
+			actual := lexer.NextToken()
+
+			if actual.Type != expected.Type {
+				t.Logf("Expected token with type %q at position %d, got type %q\n", expected.Type, pos, actual.Type)
+				t.Fail()
+			}
+
+			if actual.Literal != expected.Literal {
+				t.Logf("Expected token with literal %q at position %d, got literal %q\n", expected.Literal, pos, actual.Literal)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestLexerNumbers(t *testing.T) {
+	tests := []struct {
+		input  string
+		tokens []token.Token
+	}{
+		{"1.5", []token.Token{{Type: token.FLOAT, Literal: "1.5"}}},
+		{"1_000.000_1", []token.Token{{Type: token.FLOAT, Literal: "1_000.000_1"}}},
+		{"1e-3", []token.Token{{Type: token.FLOAT, Literal: "1e-3"}}},
+		{"2.5E+10", []token.Token{{Type: token.FLOAT, Literal: "2.5E+10"}}},
+		{"3e5", []token.Token{{Type: token.FLOAT, Literal: "3e5"}}},
+		{"0x1F", []token.Token{{Type: token.INT, Literal: "0x1F"}}},
+		{"0o17", []token.Token{{Type: token.INT, Literal: "0o17"}}},
+		{"0_17", []token.Token{{Type: token.INT, Literal: "0_17"}}},
+		{"0b10_10", []token.Token{{Type: token.INT, Literal: "0b10_10"}}},
+		{"0d170", []token.Token{{Type: token.INT, Literal: "0d170"}}},
+		{"0", []token.Token{{Type: token.INT, Literal: "0"}}},
+		{"1__0", []token.Token{{Type: token.ILLEGAL, Literal: "trailing '_' in number"}}},
+		{"1_", []token.Token{{Type: token.ILLEGAL, Literal: "trailing '_' in number"}}},
+		{"1.0_", []token.Token{{Type: token.ILLEGAL, Literal: "trailing '_' in number"}}},
+		{"0x", []token.Token{{Type: token.ILLEGAL, Literal: "numeric literal without digits"}}},
+		{"0b_1", []token.Token{{Type: token.ILLEGAL, Literal: "numeric literal without digits"}}},
+		{"018", []token.Token{{Type: token.ILLEGAL, Literal: "Invalid octal digit"}}},
+		{
+			"1.to_s",
+			[]token.Token{
+				{Type: token.INT, Literal: "1"},
+				{Type: token.DOT, Literal: "."},
+				{Type: token.IDENT, Literal: "to_s"},
+			},
+		},
+		{
+			"1.5.round",
+			[]token.Token{
+				{Type: token.FLOAT, Literal: "1.5"},
+				{Type: token.DOT, Literal: "."},
+				{Type: token.IDENT, Literal: "round"},
+			},
+		},
+		{
+			"1..2...3.5",
+			[]token.Token{
+				{Type: token.INT, Literal: "1"},
+				{Type: token.DOT2, Literal: ".."},
+				{Type: token.INT, Literal: "2"},
+				{Type: token.DOT3, Literal: "..."},
+				{Type: token.FLOAT, Literal: "3.5"},
+			},
+		},
+		{
+			"2.even?",
+			[]token.Token{
+				{Type: token.INT, Literal: "2"},
+				{Type: token.DOT, Literal: "."},
+				{Type: token.IDENT, Literal: "even?"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		lexer := New(tt.input)
+		for pos, expected := range tt.tokens {
+			if !lexer.HasNext() {
+				t.Logf("Unexpected EOF at %d for input %q\n", pos, tt.input)
+				t.FailNow()
+			}
+			actual := lexer.NextToken()
+
+			if actual.Type != expected.Type {
+				t.Logf("Expected token with type %q at position %d, got type %q\n", expected.Type, pos, actual.Type)
+				t.Fail()
+			}
+
+			if actual.Literal != expected.Literal {
+				t.Logf("Expected token with literal %q at position %d, got literal %q\n", expected.Literal, pos, actual.Literal)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestLexerRegexps(t *testing.T) {
+	tests := []struct {
+		input  string
+		tokens []token.Token
+	}{
+		{
+			"/ab+c/",
+			[]token.Token{
+				{Type: token.REGEXBEG, Literal: "/"},
+				{Type: token.STRING, Literal: "ab+c"},
+				{Type: token.REGEXEND, Literal: "/"},
+			},
+		},
+		{
+			`x =~ /a\/b/im`,
+			[]token.Token{
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.MATCH, Literal: "=~"},
+				{Type: token.REGEXBEG, Literal: "/"},
+				{Type: token.STRING, Literal: "a/b"},
+				{Type: token.REGEXEND, Literal: "/im"},
+			},
+		},
+		{
+			`x !~ /\d#{y}/`,
+			[]token.Token{
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.NMATCH, Literal: "!~"},
+				{Type: token.REGEXBEG, Literal: "/"},
+				{Type: token.STRING, Literal: `\d`},
+				{Type: token.INTERPBEG, Literal: "#{"},
+				{Type: token.IDENT, Literal: "y"},
+				{Type: token.INTERPEND, Literal: "}"},
+				{Type: token.STRING, Literal: ""},
+				{Type: token.REGEXEND, Literal: "/"},
+			},
+		},
+		{
+			"split /,/",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "split"},
+				{Type: token.REGEXBEG, Literal: "/"},
+				{Type: token.STRING, Literal: ","},
+				{Type: token.REGEXEND, Literal: "/"},
+			},
+		},
+		{
+			"x / y / z",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.SLASH, Literal: "/"},
+				{Type: token.IDENT, Literal: "y"},
+				{Type: token.SLASH, Literal: "/"},
+				{Type: token.IDENT, Literal: "z"},
+			},
+		},
+		{
+			"x /= 2; 4/2",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.DIVASSIGN, Literal: "/="},
+				{Type: token.INT, Literal: "2"},
+				{Type: token.SEMICOLON, Literal: ";"},
+				{Type: token.INT, Literal: "4"},
+				{Type: token.SLASH, Literal: "/"},
+				{Type: token.INT, Literal: "2"},
+			},
+		},
+		{
+			"x = 4; x /2",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.ASSIGN, Literal: "="},
+				{Type: token.INT, Literal: "4"},
+				{Type: token.SEMICOLON, Literal: ";"},
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.SLASH, Literal: "/"},
+				{Type: token.INT, Literal: "2"},
+			},
+		},
+		{
+			"def half(n)\nn /2\nend",
+			[]token.Token{
+				{Type: token.DEF, Literal: "def"},
+				{Type: token.IDENT, Literal: "half"},
+				{Type: token.LPAREN, Literal: "("},
+				{Type: token.IDENT, Literal: "n"},
+				{Type: token.RPAREN, Literal: ")"},
+				{Type: token.NEWLINE, Literal: "\n"},
+				{Type: token.IDENT, Literal: "n"},
+				{Type: token.SLASH, Literal: "/"},
+				{Type: token.INT, Literal: "2"},
+			},
+		},
+		{
+			"x.map { |v| v /2 }",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "x"},
+				{Type: token.DOT, Literal: "."},
+				{Type: token.IDENT, Literal: "map"},
+				{Type: token.LBRACE, Literal: "{"},
+				{Type: token.PIPE, Literal: "|"},
+				{Type: token.IDENT, Literal: "v"},
+				{Type: token.PIPE, Literal: "|"},
+				{Type: token.IDENT, Literal: "v"},
+				{Type: token.SLASH, Literal: "/"},
+				{Type: token.INT, Literal: "2"},
+			},
+		},
+		{
+			"re = /\n  a # comment\n/x",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "re"},
+				{Type: token.ASSIGN, Literal: "="},
+				{Type: token.REGEXBEG, Literal: "/"},
+				{Type: token.STRING, Literal: "\n  a # comment\n"},
+				{Type: token.REGEXEND, Literal: "/x"},
+			},
+		},
+		{
+			"$~; $1 + $12",
+			[]token.Token{
+				{Type: token.GLOBAL, Literal: "$~"},
+				{Type: token.SEMICOLON, Literal: ";"},
+				{Type: token.GLOBAL, Literal: "$1"},
+				{Type: token.PLUS, Literal: "+"},
+				{Type: token.GLOBAL, Literal: "$12"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		checkTokens(t, tt.input, tt.tokens)
+	}
+}
+
+func TestLexerCaseExpression(t *testing.T) {
+	input := "case x\nwhen Integer === y then 1\nend"
+	tokens := []token.Token{
+		{Type: token.CASE, Literal: "case"},
+		{Type: token.IDENT, Literal: "x"},
+		{Type: token.NEWLINE, Literal: "\n"},
+		{Type: token.WHEN, Literal: "when"},
+		{Type: token.CONST, Literal: "Integer"},
+		{Type: token.CASEEQ, Literal: "==="},
+		{Type: token.IDENT, Literal: "y"},
+		{Type: token.THEN, Literal: "then"},
+		{Type: token.INT, Literal: "1"},
+		{Type: token.NEWLINE, Literal: "\n"},
+		{Type: token.END, Literal: "end"},
+	}
+
+	checkTokens(t, input, tokens)
+}
+
+func TestLexerJumpKeywords(t *testing.T) {
+	input := "break 3; next; redo\nx.next"
+	tokens := []token.Token{
+		{Type: token.BREAK, Literal: "break"},
+		{Type: token.INT, Literal: "3"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.NEXT, Literal: "next"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.REDO, Literal: "redo"},
+		{Type: token.NEWLINE, Literal: "\n"},
+		{Type: token.IDENT, Literal: "x"},
+		{Type: token.DOT, Literal: "."},
+		{Type: token.IDENT, Literal: "next"},
+	}
+
+	checkTokens(t, input, tokens)
+}
+
+func TestLexerAliasAndUndef(t *testing.T) {
+	input := "alias / div; alias $a $b\nundef %, foo"
+	tokens := []token.Token{
+		{Type: token.ALIAS, Literal: "alias"},
+		{Type: token.SLASH, Literal: "/"},
+		{Type: token.IDENT, Literal: "div"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.ALIAS, Literal: "alias"},
+		{Type: token.GLOBAL, Literal: "$a"},
+		{Type: token.GLOBAL, Literal: "$b"},
+		{Type: token.NEWLINE, Literal: "\n"},
+		{Type: token.UNDEF, Literal: "undef"},
+		{Type: token.MODULO, Literal: "%"},
+		{Type: token.COMMA, Literal: ","},
+		{Type: token.IDENT, Literal: "foo"},
+	}
+
+	checkTokens(t, input, tokens)
+}
+
+func TestLexerProgramSections(t *testing.T) {
+	input := "BEGIN { __LINE__ }\nEND { __dir__ }\nx = __END__\n__END__\nfoo bar\n"
+	tokens := []token.Token{
+		{Type: token.KEYWORD_BEGIN, Literal: "BEGIN"},
+		{Type: token.LBRACE, Literal: "{"},
+		{Type: token.KEYWORD__LINE__, Literal: "__LINE__"},
+		{Type: token.RBRACE, Literal: "}"},
+		{Type: token.NEWLINE, Literal: "\n"},
+		{Type: token.KEYWORD_END, Literal: "END"},
+		{Type: token.LBRACE, Literal: "{"},
+		{Type: token.KEYWORD__DIR__, Literal: "__dir__"},
+		{Type: token.RBRACE, Literal: "}"},
+		{Type: token.NEWLINE, Literal: "\n"},
+		{Type: token.IDENT, Literal: "x"},
+		{Type: token.ASSIGN, Literal: "="},
+		{Type: token.IDENT, Literal: "__END__"},
+		{Type: token.NEWLINE, Literal: "\n"},
+		{Type: token.KEYWORD__END__, Literal: "__END__"},
+		{Type: token.EOF, Literal: ""},
+	}
+
+	checkTokens(t, input, tokens)
+}
+
+func TestLexerLogicalKeywords(t *testing.T) {
+	input := "x = a or b and not c"
+	tokens := []token.Token{
+		{Type: token.IDENT, Literal: "x"},
+		{Type: token.ASSIGN, Literal: "="},
+		{Type: token.IDENT, Literal: "a"},
+		{Type: token.KEYWORD_OR, Literal: "or"},
+		{Type: token.IDENT, Literal: "b"},
+		{Type: token.KEYWORD_AND, Literal: "and"},
+		{Type: token.KEYWORD_NOT, Literal: "not"},
+		{Type: token.IDENT, Literal: "c"},
+	}
+
+	checkTokens(t, input, tokens)
+}
+
+func TestLexerPatternMatching(t *testing.T) {
+	input := "x in [^y, *] => z\n:in"
+	tokens := []token.Token{
+		{Type: token.IDENT, Literal: "x"},
+		{Type: token.IN, Literal: "in"},
+		{Type: token.LBRACKET, Literal: "["},
+		{Type: token.CARET, Literal: "^"},
+		{Type: token.IDENT, Literal: "y"},
+		{Type: token.COMMA, Literal: ","},
+		{Type: token.ASTERISK, Literal: "*"},
+		{Type: token.RBRACKET, Literal: "]"},
+		{Type: token.HASHROCKET, Literal: "=>"},
+		{Type: token.IDENT, Literal: "z"},
+		{Type: token.NEWLINE, Literal: "\n"},
+		{Type: token.SYMBEG, Literal: ":"},
+		{Type: token.IDENT, Literal: "in"},
+	}
+
+	checkTokens(t, input, tokens)
+}
+
+func TestLexerSuper(t *testing.T) {
+	input := "super(a) + super"
+	tokens := []token.Token{
+		{Type: token.SUPER, Literal: "super"},
+		{Type: token.LPAREN, Literal: "("},
+		{Type: token.IDENT, Literal: "a"},
+		{Type: token.RPAREN, Literal: ")"},
+		{Type: token.PLUS, Literal: "+"},
+		{Type: token.SUPER, Literal: "super"},
+	}
+
+	checkTokens(t, input, tokens)
+}
+
+func TestLexerLabelsAndSplats(t *testing.T) {
+	input := "foo(*a, **b, key: 1, A::B, c ? d : e)"
+	tokens := []token.Token{
+		{Type: token.IDENT, Literal: "foo"},
+		{Type: token.LPAREN, Literal: "("},
+		{Type: token.ASTERISK, Literal: "*"},
+		{Type: token.IDENT, Literal: "a"},
+		{Type: token.COMMA, Literal: ","},
+		{Type: token.POW, Literal: "**"},
+		{Type: token.IDENT, Literal: "b"},
+		{Type: token.COMMA, Literal: ","},
+		{Type: token.LABEL, Literal: "key"},
+		{Type: token.INT, Literal: "1"},
+		{Type: token.COMMA, Literal: ","},
+		{Type: token.CONST, Literal: "A"},
+		{Type: token.SCOPE, Literal: "::"},
+		{Type: token.CONST, Literal: "B"},
+		{Type: token.COMMA, Literal: ","},
+		{Type: token.IDENT, Literal: "c"},
+		{Type: token.QMARK, Literal: "?"},
+		{Type: token.IDENT, Literal: "d"},
+		{Type: token.COLON, Literal: ":"},
+		{Type: token.IDENT, Literal: "e"},
+		{Type: token.RPAREN, Literal: ")"},
+	}
+
+	checkTokens(t, input, tokens)
+}
+
+func TestLexerLambda(t *testing.T) {
+	input := "->(x) { x - 1 }"
+	tokens := []token.Token{
+		{Type: token.LAMBDA, Literal: "->"},
+		{Type: token.LPAREN, Literal: "("},
+		{Type: token.IDENT, Literal: "x"},
+		{Type: token.RPAREN, Literal: ")"},
+		{Type: token.LBRACE, Literal: "{"},
+		{Type: token.IDENT, Literal: "x"},
+		{Type: token.MINUS, Literal: "-"},
+		{Type: token.INT, Literal: "1"},
+		{Type: token.RBRACE, Literal: "}"},
+	}
+
+	checkTokens(t, input, tokens)
+}
+
+func TestLexerSafeNavigation(t *testing.T) {
+	input := "a&.b && c & d"
+	tokens := []token.Token{
+		{Type: token.IDENT, Literal: "a"},
+		{Type: token.ANDDOT, Literal: "&."},
+		{Type: token.IDENT, Literal: "b"},
+		{Type: token.LOGICALAND, Literal: "&&"},
+		{Type: token.IDENT, Literal: "c"},
+		{Type: token.AND, Literal: "&"},
+		{Type: token.IDENT, Literal: "d"},
+	}
+
+	checkTokens(t, input, tokens)
+}
+
+func TestLexerBlockPass(t *testing.T) {
+	input := "map(&:upcase, &@a, &$b, &->{}) & A::B"
+	tokens := []token.Token{
+		{Type: token.IDENT, Literal: "map"},
+		{Type: token.LPAREN, Literal: "("},
+		{Type: token.CAPTURE, Literal: "&"},
+		{Type: token.SYMBEG, Literal: ":"},
+		{Type: token.IDENT, Literal: "upcase"},
+		{Type: token.COMMA, Literal: ","},
+		{Type: token.CAPTURE, Literal: "&"},
+		{Type: token.AT, Literal: "@"},
+		{Type: token.IDENT, Literal: "a"},
+		{Type: token.COMMA, Literal: ","},
+		{Type: token.CAPTURE, Literal: "&"},
+		{Type: token.GLOBAL, Literal: "$b"},
+		{Type: token.COMMA, Literal: ","},
+		{Type: token.CAPTURE, Literal: "&"},
+		{Type: token.LAMBDA, Literal: "->"},
+		{Type: token.LBRACE, Literal: "{"},
+		{Type: token.RBRACE, Literal: "}"},
+		{Type: token.RPAREN, Literal: ")"},
+		{Type: token.AND, Literal: "&"},
+		{Type: token.CONST, Literal: "A"},
+		{Type: token.SCOPE, Literal: "::"},
+		{Type: token.CONST, Literal: "B"},
+	}
+
+	checkTokens(t, input, tokens)
+}
+
+func TestLexerOperatorSymbols(t *testing.T) {
+	input := "[:+, :[]=, :<=>, :-@]; inject(&:*); x ? 1 :-1"
+	tokens := []token.Token{
+		{Type: token.LBRACKET, Literal: "["},
+		{Type: token.SYMBEG, Literal: ":"},
+		{Type: token.IDENT, Literal: "+"},
+		{Type: token.COMMA, Literal: ","},
+		{Type: token.SYMBEG, Literal: ":"},
+		{Type: token.IDENT, Literal: "[]="},
+		{Type: token.COMMA, Literal: ","},
+		{Type: token.SYMBEG, Literal: ":"},
+		{Type: token.IDENT, Literal: "<=>"},
+		{Type: token.COMMA, Literal: ","},
+		{Type: token.SYMBEG, Literal: ":"},
+		{Type: token.IDENT, Literal: "-@"},
+		{Type: token.RBRACKET, Literal: "]"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.IDENT, Literal: "inject"},
+		{Type: token.LPAREN, Literal: "("},
+		{Type: token.CAPTURE, Literal: "&"},
+		{Type: token.SYMBEG, Literal: ":"},
+		{Type: token.IDENT, Literal: "*"},
+		{Type: token.RPAREN, Literal: ")"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.IDENT, Literal: "x"},
+		{Type: token.QMARK, Literal: "?"},
+		{Type: token.INT, Literal: "1"},
+		{Type: token.SYMBEG, Literal: ":"},
+		{Type: token.MINUS, Literal: "-"},
+		{Type: token.INT, Literal: "1"},
+	}
+
+	checkTokens(t, input, tokens)
+}
+
+func TestLexerPercentLiterals(t *testing.T) {
+	input := "%w(a (b) c\\ d); %i[x]; %q{a {b} #{c}}; %Q<#{x}>; %|y|; %s(z); a % b; x %= 2; n = 7; n %(3)"
+	tokens := []token.Token{
+		{Type: token.WORDSBEG, Literal: "%w("},
+		{Type: token.STRING, Literal: "a"},
+		{Type: token.STRING, Literal: "(b)"},
+		{Type: token.STRING, Literal: "c d"},
+		{Type: token.WORDSEND, Literal: ")"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.SYMBOLSBEG, Literal: "%i["},
+		{Type: token.STRING, Literal: "x"},
+		{Type: token.WORDSEND, Literal: "]"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.STRING, Literal: "a {b} #{c}"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.STRING, Literal: ""},
+		{Type: token.INTERPBEG, Literal: "#{"},
+		{Type: token.IDENT, Literal: "x"},
+		{Type: token.INTERPEND, Literal: "}"},
+		{Type: token.STRING, Literal: ""},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.STRING, Literal: "y"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.SYMBEG, Literal: ":"},
+		{Type: token.STRING, Literal: "z"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.IDENT, Literal: "a"},
+		{Type: token.MODULO, Literal: "%"},
+		{Type: token.IDENT, Literal: "b"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.IDENT, Literal: "x"},
+		{Type: token.MODASSIGN, Literal: "%="},
+		{Type: token.INT, Literal: "2"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.IDENT, Literal: "n"},
+		{Type: token.ASSIGN, Literal: "="},
+		{Type: token.INT, Literal: "7"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.IDENT, Literal: "n"},
+		{Type: token.MODULO, Literal: "%"},
+		{Type: token.LPAREN, Literal: "("},
+		{Type: token.INT, Literal: "3"},
+		{Type: token.RPAREN, Literal: ")"},
+	}
+
+	checkTokens(t, input, tokens)
+}
+
+func TestLexerAssignOperators(t *testing.T) {
+	input := "a ||= b &&= c **= d <<= e |= f &= g"
+	tokens := []token.Token{
+		{Type: token.IDENT, Literal: "a"},
+		{Type: token.LOGICALORASSIGN, Literal: "||="},
+		{Type: token.IDENT, Literal: "b"},
+		{Type: token.LOGICALANDASSIGN, Literal: "&&="},
+		{Type: token.IDENT, Literal: "c"},
+		{Type: token.POWASSIGN, Literal: "**="},
+		{Type: token.IDENT, Literal: "d"},
+		{Type: token.LSHIFTASSIGN, Literal: "<<="},
+		{Type: token.IDENT, Literal: "e"},
+		{Type: token.ORASSIGN, Literal: "|="},
+		{Type: token.IDENT, Literal: "f"},
+		{Type: token.ANDASSIGN, Literal: "&="},
+		{Type: token.IDENT, Literal: "g"},
+	}
+
+	checkTokens(t, input, tokens)
+}
+
+func TestLexerBitwiseOperators(t *testing.T) {
+	input := "~a ** b ^ c >> d >>= e ^= f"
+	tokens := []token.Token{
+		{Type: token.TILDE, Literal: "~"},
+		{Type: token.IDENT, Literal: "a"},
+		{Type: token.POW, Literal: "**"},
+		{Type: token.IDENT, Literal: "b"},
+		{Type: token.CARET, Literal: "^"},
+		{Type: token.IDENT, Literal: "c"},
+		{Type: token.RSHIFT, Literal: ">>"},
+		{Type: token.IDENT, Literal: "d"},
+		{Type: token.RSHIFTASSIGN, Literal: ">>="},
+		{Type: token.IDENT, Literal: "e"},
+		{Type: token.XORASSIGN, Literal: "^="},
+		{Type: token.IDENT, Literal: "f"},
+	}
+
+	checkTokens(t, input, tokens)
+}
+
+func checkTokens(t *testing.T, input string, tokens []token.Token) {
+	t.Helper()
+	lexer := New(input)
+	for pos, expected := range tokens {
+		if !lexer.HasNext() {
+			t.Logf("Unexpected EOF at %d for input %q\n", pos, input)
+			t.FailNow()
+		}
+		actual := lexer.NextToken()
+
+		if actual.Type != expected.Type {
+			t.Logf("Expected token with type %q at position %d, got type %q\n", expected.Type, pos, actual.Type)
+			t.Fail()
+		}
+
+		if actual.Literal != expected.Literal {
+			t.Logf("Expected token with literal %q at position %d, got literal %q\n", expected.Literal, pos, actual.Literal)
+			t.Fail()
+		}
+	}
+}
@@ -57,8 +57,21 @@ func (a *Array) hashKey() hashKey {
 var arrayClassMethods = map[string]RubyMethod{}
 
 var arrayMethods = map[string]RubyMethod{
-	"push":    publicMethod(arrayPush),
-	"unshift": publicMethod(arrayUnshift),
+	"push":        publicMethod(arrayPush),
+	"unshift":     publicMethod(arrayUnshift),
+	"map":         publicMethod(arrayMap),
+	"to_s":        withArity(0, publicMethod(arrayToS)),
+	"==":          withArity(1, publicMethod(arrayEq)),
+	"deconstruct": withArity(0, publicMethod(arrayDeconstruct)),
+}
+
+func arrayDeconstruct(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver(), nil
+}
+
+func arrayToS(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array, _ := context.Receiver().(*Array)
+	return &String{Value: array.Inspect()}, nil
 }
 
 func arrayPush(context CallContext, args ...RubyObject) (RubyObject, error) {
@@ -72,3 +85,41 @@ func arrayUnshift(context CallContext, args ...RubyObject) (RubyObject, error) {
 	array.Elements = append(args, array.Elements...)
 	return array, nil
 }
+
+func arrayMap(context CallContext, args ...RubyObject) (RubyObject, error) {
+	block, remainingArgs, ok := extractBlockFromArgs(args)
+	if !ok {
+		return nil, NewNoBlockGivenLocalJumpError()
+	}
+	if len(remainingArgs) != 0 {
+		return nil, NewWrongNumberOfArgumentsError(0, len(remainingArgs))
+	}
+	array, _ := context.Receiver().(*Array)
+	result := NewArray()
+	for _, element := range array.Elements {
+		mapped, err := block.Call(context, element)
+		if err != nil {
+			return nil, err
+		}
+		result.Elements = append(result.Elements, mapped)
+	}
+	return result, nil
+}
+
+func arrayEq(context CallContext, args ...RubyObject) (RubyObject, error) {
+	array := context.Receiver().(*Array)
+	other, ok := args[0].(*Array)
+	if !ok || len(array.Elements) != len(other.Elements) {
+		return FALSE, nil
+	}
+	for i, element := range array.Elements {
+		eq, err := Send(NewCallContext(context.Env(), element), "==", other.Elements[i])
+		if err != nil {
+			return nil, err
+		}
+		if eq == NIL || eq == FALSE {
+			return FALSE, nil
+		}
+	}
+	return TRUE, nil
+}
@@ -94,3 +94,42 @@ func TestArrayUnshift(t *testing.T) {
 		}
 	})
 }
+
+func TestArrayMap(t *testing.T) {
+	double := &Proc{
+		native: func(context CallContext, args ...RubyObject) (RubyObject, error) {
+			return &Integer{Value: args[0].(*Integer).Value * 2}, nil
+		},
+	}
+	array := &Array{Elements: []RubyObject{&Integer{Value: 1}, &Integer{Value: 2}}}
+
+	t.Run("with block", func(t *testing.T) {
+		context := &callContext{receiver: array, env: NewEnvironment()}
+
+		result, err := arrayMap(context, NewBlockArgument(double))
+
+		checkError(t, err, nil)
+
+		expected := &Array{Elements: []RubyObject{&Integer{Value: 2}, &Integer{Value: 4}}}
+
+		checkResult(t, result, expected)
+	})
+	t.Run("without block", func(t *testing.T) {
+		context := &callContext{receiver: array, env: NewEnvironment()}
+
+		_, err := arrayMap(context)
+
+		checkError(t, err, NewNoBlockGivenLocalJumpError())
+	})
+}
+
+func TestArrayDeconstruct(t *testing.T) {
+	array := &Array{Elements: []RubyObject{&Integer{Value: 1}}}
+	context := &callContext{receiver: array}
+
+	result, err := arrayDeconstruct(context)
+
+	checkError(t, err, nil)
+
+	checkResult(t, result, array)
+}
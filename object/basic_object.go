@@ -38,6 +38,10 @@ var basicObjectClassMethods = map[string]RubyMethod{}
 var basicObjectMethods = map[string]RubyMethod{
 	"initialize":     privateMethod(basicObjectInitialize),
 	"method_missing": privateMethod(basicObjectMethodMissing),
+	"!":              withArity(0, publicMethod(basicObjectNot)),
+	"==":             withArity(1, publicMethod(basicObjectEq)),
+	"!=":             withArity(1, publicMethod(basicObjectNeq)),
+	"equal?":         withArity(1, publicMethod(basicObjectEq)),
 }
 
 func basicObjectMethodMissing(context CallContext, args ...RubyObject) (RubyObject, error) {
@@ -54,3 +58,28 @@ func basicObjectMethodMissing(context CallContext, args ...RubyObject) (RubyObje
 func basicObjectInitialize(context CallContext, args ...RubyObject) (RubyObject, error) {
 	return context.Receiver(), nil
 }
+
+func basicObjectNot(context CallContext, args ...RubyObject) (RubyObject, error) {
+	receiver := unwrapSelf(context.Receiver())
+	return nativeBoolToBoolean(receiver == NIL || receiver == FALSE), nil
+}
+
+func basicObjectEq(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return nativeBoolToBoolean(unwrapSelf(context.Receiver()) == unwrapSelf(args[0])), nil
+}
+
+func basicObjectNeq(context CallContext, args ...RubyObject) (RubyObject, error) {
+	eq, err := Send(context, "==", args...)
+	if err != nil {
+		return nil, err
+	}
+	return nativeBoolToBoolean(eq == NIL || eq == FALSE), nil
+}
+
+// unwrapSelf returns the object wrapped by obj if it is a *Self
+func unwrapSelf(obj RubyObject) RubyObject {
+	if self, ok := obj.(*Self); ok {
+		return self.RubyObject
+	}
+	return obj
+}
@@ -29,3 +29,73 @@ func TestBasicObjectInitialize(t *testing.T) {
 
 	checkResult(t, result, context.Receiver())
 }
+
+func TestBasicObjectNot(t *testing.T) {
+	tests := []struct {
+		receiver RubyObject
+		result   RubyObject
+	}{
+		{&Object{}, FALSE},
+		{TRUE, FALSE},
+		{NewInteger(0), FALSE},
+		{FALSE, TRUE},
+		{NIL, TRUE},
+		{&Self{RubyObject: NIL}, TRUE},
+	}
+
+	for _, tt := range tests {
+		context := &callContext{receiver: tt.receiver}
+
+		result, err := basicObjectNot(context)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, tt.result)
+	}
+}
+
+func TestBasicObjectEq(t *testing.T) {
+	object := &Object{}
+	tests := []struct {
+		receiver RubyObject
+		argument RubyObject
+		result   RubyObject
+	}{
+		{object, object, TRUE},
+		{object, &Object{}, FALSE},
+		{&Self{RubyObject: object}, object, TRUE},
+		{NIL, NIL, TRUE},
+	}
+
+	for _, tt := range tests {
+		context := &callContext{receiver: tt.receiver}
+
+		result, err := basicObjectEq(context, tt.argument)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, tt.result)
+	}
+}
+
+func TestBasicObjectNeq(t *testing.T) {
+	tests := []struct {
+		receiver RubyObject
+		argument RubyObject
+		result   RubyObject
+	}{
+		{&String{Value: "a"}, &String{Value: "a"}, FALSE},
+		{&String{Value: "a"}, &String{Value: "b"}, TRUE},
+		{&Object{}, &Object{}, TRUE},
+	}
+
+	for _, tt := range tests {
+		context := &callContext{receiver: tt.receiver}
+
+		result, err := basicObjectNeq(context, tt.argument)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, tt.result)
+	}
+}
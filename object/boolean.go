@@ -46,13 +46,20 @@ func (b *Boolean) hashKey() hashKey {
 }
 
 var booleanTrueMethods = map[string]RubyMethod{
-	"==": withArity(1, publicMethod(booleanEq)),
-	"!=": withArity(1, publicMethod(booleanNeq)),
+	"==":   withArity(1, publicMethod(booleanEq)),
+	"!=":   withArity(1, publicMethod(booleanNeq)),
+	"to_s": withArity(0, publicMethod(booleanToS)),
 }
 
 var booleanFalseMethods = map[string]RubyMethod{
-	"==": withArity(1, publicMethod(booleanEq)),
-	"!=": withArity(1, publicMethod(booleanNeq)),
+	"==":   withArity(1, publicMethod(booleanEq)),
+	"!=":   withArity(1, publicMethod(booleanNeq)),
+	"to_s": withArity(0, publicMethod(booleanToS)),
+}
+
+func booleanToS(context CallContext, args ...RubyObject) (RubyObject, error) {
+	b := context.Receiver().(*Boolean)
+	return &String{Value: b.Inspect()}, nil
 }
 
 func booleanEq(context CallContext, args ...RubyObject) (RubyObject, error) {
@@ -78,3 +85,11 @@ func booleanNeq(context CallContext, args ...RubyObject) (RubyObject, error) {
 	}
 	return FALSE, nil
 }
+
+// nativeBoolToBoolean returns TRUE for true and FALSE for false
+func nativeBoolToBoolean(b bool) RubyObject {
+	if b {
+		return TRUE
+	}
+	return FALSE
+}
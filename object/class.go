@@ -16,7 +16,7 @@ var notInstantiatable = func(c RubyClassObject, args ...RubyObject) (RubyObject,
 	return nil, NewNoMethodError(c, "new")
 }
 var defaultBuilder = func(c RubyClassObject, args ...RubyObject) (RubyObject, error) {
-	return &classInstance{class: c}, nil
+	return &classInstance{class: c, Environment: NewEnvironment()}, nil
 }
 
 func init() {
@@ -106,6 +106,7 @@ func (c *class) hashKey() hashKey {
 func (c *class) addMethod(name string, method RubyMethod) {
 	c.instanceMethods.Set(name, method)
 }
+func (c *class) methodClass() RubyClass { return c }
 func (c *class) New(args ...RubyObject) (RubyObject, error) {
 	return c.builder(c)
 }
@@ -120,7 +121,8 @@ var classInstanceMethods = map[string]RubyMethod{
 }
 
 type classInstance struct {
-	class RubyClassObject
+	class       RubyClassObject
+	Environment // holds the instance variables
 }
 
 func (o *classInstance) Inspect() string  { return fmt.Sprintf("#<%s:%p>", o.class.Inspect(), o) }
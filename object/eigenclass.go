@@ -41,3 +41,4 @@ func (e *eigenclass) Name() string { return e.wrappedClass.Name() }
 func (e *eigenclass) addMethod(name string, method RubyMethod) {
 	e.methods.Set(name, method)
 }
+func (e *eigenclass) methodClass() RubyClass { return e }
@@ -63,6 +63,8 @@ type Environment interface {
 	Unset(key string) RubyObject
 	// SetGlobal sets val under name at the root of the environment
 	SetGlobal(name string, val RubyObject) RubyObject
+	// AliasGlobal makes the global newName refer to the global oldName
+	AliasGlobal(newName, oldName string)
 	// Outer returns the parent environment
 	Outer() Environment
 	// Clone returns a copy of the environment. It will shallow copy its values
@@ -154,14 +156,15 @@ func (l *localVariableGuard) Clone() Environment {
 }
 
 type environment struct {
-	store map[string]RubyObject
-	outer Environment
+	store   map[string]RubyObject
+	aliases map[string]string
+	outer   Environment
 }
 
 // Get returns the RubyObject found for this key. If it is not found,
 // ok  will be false
 func (e *environment) Get(name string) (RubyObject, bool) {
-	obj, ok := e.store[name]
+	obj, ok := e.store[e.resolveAlias(name)]
 	if !ok && e.outer != nil {
 		obj, ok = e.outer.Get(name)
 	}
@@ -175,7 +178,7 @@ func (e *environment) GetAll() map[string]RubyObject {
 // Set sets the RubyObject for the given key. If there is already an
 // object with that key it will be overridden by object
 func (e *environment) Set(name string, val RubyObject) RubyObject {
-	e.store[name] = val
+	e.store[e.resolveAlias(name)] = val
 	return val
 }
 
@@ -189,6 +192,26 @@ func (e *environment) SetGlobal(name string, val RubyObject) RubyObject {
 	return val
 }
 
+// AliasGlobal makes the global newName refer to the global oldName
+func (e *environment) AliasGlobal(newName, oldName string) {
+	if e.outer != nil {
+		e.outer.AliasGlobal(newName, oldName)
+		return
+	}
+	if e.aliases == nil {
+		e.aliases = make(map[string]string)
+	}
+	e.aliases[newName] = e.resolveAlias(oldName)
+}
+
+// resolveAlias returns the name aliased by name, or name if it is no alias
+func (e *environment) resolveAlias(name string) string {
+	if target, ok := e.aliases[name]; ok {
+		return target
+	}
+	return name
+}
+
 func (e *environment) Unset(key string) RubyObject {
 	val := e.store[key]
 	delete(e.store, key)
@@ -217,6 +240,12 @@ func (e *environment) clone() *environment {
 	for k, v := range e.store {
 		env.store[k] = v
 	}
+	if e.aliases != nil {
+		env.aliases = make(map[string]string)
+		for k, v := range e.aliases {
+			env.aliases[k] = v
+		}
+	}
 	return env
 }
 
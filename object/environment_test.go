@@ -573,3 +573,34 @@ func Test_localVariableGuardClone(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestEnvironmentAliasGlobal(t *testing.T) {
+	root := &environment{store: map[string]RubyObject{"$foo": TRUE}}
+	env := &environment{store: make(map[string]RubyObject), outer: root}
+
+	env.AliasGlobal("$bar", "$foo")
+	env.AliasGlobal("$qux", "$bar")
+
+	for _, name := range []string{"$foo", "$bar", "$qux"} {
+		val, ok := env.Get(name)
+		if !ok {
+			t.Logf("Expected env to contain %q", name)
+			t.Fail()
+		}
+		if val != TRUE {
+			t.Logf("Expected %q to equal TRUE, got %v", name, val)
+			t.Fail()
+		}
+	}
+
+	env.SetGlobal("$qux", FALSE)
+
+	if val := root.store["$foo"]; val != FALSE {
+		t.Logf("Expected '$foo' to equal FALSE, got %v", val)
+		t.Fail()
+	}
+	if _, ok := root.store["$qux"]; ok {
+		t.Logf("Expected root store to not contain '$qux'")
+		t.Fail()
+	}
+}
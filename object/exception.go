@@ -51,6 +51,42 @@ var (
 			return &ArgumentError{message: c.Name()}, nil
 		},
 	)
+	indexErrorClass RubyClassObject = newClass(
+		"IndexError",
+		standardErrorClass,
+		nil,
+		nil,
+		func(c RubyClassObject, args ...RubyObject) (RubyObject, error) {
+			return &IndexError{message: c.Name()}, nil
+		},
+	)
+	rangeErrorClass RubyClassObject = newClass(
+		"RangeError",
+		standardErrorClass,
+		nil,
+		nil,
+		func(c RubyClassObject, args ...RubyObject) (RubyObject, error) {
+			return &RangeError{message: c.Name()}, nil
+		},
+	)
+	floatDomainErrorClass RubyClassObject = newClass(
+		"FloatDomainError",
+		rangeErrorClass,
+		nil,
+		nil,
+		func(c RubyClassObject, args ...RubyObject) (RubyObject, error) {
+			return &FloatDomainError{message: c.Name()}, nil
+		},
+	)
+	regexpErrorClass RubyClassObject = newClass(
+		"RegexpError",
+		standardErrorClass,
+		nil,
+		nil,
+		func(c RubyClassObject, args ...RubyObject) (RubyObject, error) {
+			return &RegexpError{message: c.Name()}, nil
+		},
+	)
 	nameErrorClass RubyClassObject = newClass(
 		"NameError",
 		standardErrorClass,
@@ -123,13 +159,27 @@ var (
 			return &LocalJumpError{message: c.Name()}, nil
 		},
 	)
+	noMatchingPatternErrorClass RubyClassObject = newClass(
+		"NoMatchingPatternError",
+		standardErrorClass,
+		nil,
+		nil,
+		func(c RubyClassObject, args ...RubyObject) (RubyObject, error) {
+			return &NoMatchingPatternError{message: c.Name()}, nil
+		},
+	)
 )
 
 func init() {
 	classes.Set("Exception", exceptionClass)
 	classes.Set("StandardError", standardErrorClass)
+	classes.Set("RuntimeError", runtimeErrorClass)
 	classes.Set("ZeroDivisionError", zeroDivisionErrorClass)
 	classes.Set("ArgumentError", argumentErrorClass)
+	classes.Set("IndexError", indexErrorClass)
+	classes.Set("RangeError", rangeErrorClass)
+	classes.Set("FloatDomainError", floatDomainErrorClass)
+	classes.Set("RegexpError", regexpErrorClass)
 	classes.Set("NameError", nameErrorClass)
 	classes.Set("NoMethodError", noMethodErrorClass)
 	classes.Set("TypeError", typeErrorClass)
@@ -137,6 +187,8 @@ func init() {
 	classes.Set("LoadError", loadErrorClass)
 	classes.Set("SyntaxError", syntaxErrorClass)
 	classes.Set("NotImplementedError", notImplementedErrorClass)
+	classes.Set("LocalJumpError", localJumpErrorClass)
+	classes.Set("NoMatchingPatternError", noMatchingPatternErrorClass)
 }
 
 func formatException(exception RubyObject, message string) string {
@@ -149,7 +201,7 @@ type exception interface {
 }
 
 // NewException creates a new exception with the given message template and
-//uses fmt.Sprintf to interpolate the args into messageinto message.
+// uses fmt.Sprintf to interpolate the args into messageinto message.
 func NewException(message string, args ...interface{}) *Exception {
 	return &Exception{message: fmt.Sprintf(message, args...)}
 }
@@ -353,6 +405,22 @@ func NewWrongNumberOfArgumentsError(expected, actual int) *ArgumentError {
 	}
 }
 
+// NewWrongNumberOfArgumentsRangeError returns an ArgumentError for methods
+// accepting a range of arguments. If unlimited is true there is no upper bound.
+func NewWrongNumberOfArgumentsRangeError(min, max int, unlimited bool, actual int) *ArgumentError {
+	expected := fmt.Sprintf("%d..%d", min, max)
+	if unlimited {
+		expected = fmt.Sprintf("%d+", min)
+	}
+	return &ArgumentError{
+		message: fmt.Sprintf(
+			"wrong number of arguments (given %d, expected %s)",
+			actual,
+			expected,
+		),
+	}
+}
+
 // NewArgumentError creates an ArgumentError. It has the same API as fmt.Errorf
 func NewArgumentError(format string, args ...interface{}) *ArgumentError {
 	return &ArgumentError{
@@ -379,6 +447,109 @@ func (e *ArgumentError) setErrorMessage(msg string) {
 // Class returns argumentErrorClass
 func (e *ArgumentError) Class() RubyClass { return argumentErrorClass }
 
+// NewIndexError creates an IndexError. It has the same API as fmt.Errorf
+func NewIndexError(format string, args ...interface{}) *IndexError {
+	return &IndexError{
+		message: fmt.Sprintf(format, args...),
+	}
+}
+
+// IndexError represents an error when a given index is invalid
+type IndexError struct {
+	message string
+}
+
+// Type returns EXCEPTION_OBJ
+func (e *IndexError) Type() Type { return EXCEPTION_OBJ }
+
+// Inspect returns a string starting with the exception class name, followed by the message
+func (e *IndexError) Inspect() string { return formatException(e, e.message) }
+func (e *IndexError) Error() string   { return e.message }
+
+func (e *IndexError) setErrorMessage(msg string) {
+	e.message = msg
+}
+
+// Class returns indexErrorClass
+func (e *IndexError) Class() RubyClass { return indexErrorClass }
+
+// NewRangeError creates a RangeError. It has the same API as fmt.Errorf
+func NewRangeError(format string, args ...interface{}) *RangeError {
+	return &RangeError{
+		message: fmt.Sprintf(format, args...),
+	}
+}
+
+// RangeError represents an error when a given value is out of range
+type RangeError struct {
+	message string
+}
+
+// Type returns EXCEPTION_OBJ
+func (e *RangeError) Type() Type { return EXCEPTION_OBJ }
+
+// Inspect returns a string starting with the exception class name, followed by the message
+func (e *RangeError) Inspect() string { return formatException(e, e.message) }
+func (e *RangeError) Error() string   { return e.message }
+
+func (e *RangeError) setErrorMessage(msg string) {
+	e.message = msg
+}
+
+// Class returns rangeErrorClass
+func (e *RangeError) Class() RubyClass { return rangeErrorClass }
+
+// NewFloatDomainError returns a FloatDomainError for the given float value
+func NewFloatDomainError(value string) *FloatDomainError {
+	return &FloatDomainError{message: value}
+}
+
+// FloatDomainError represents an error when converting infinite or NaN
+// floats into integers
+type FloatDomainError struct {
+	message string
+}
+
+// Type returns EXCEPTION_OBJ
+func (e *FloatDomainError) Type() Type { return EXCEPTION_OBJ }
+
+// Inspect returns a string starting with the exception class name, followed by the message
+func (e *FloatDomainError) Inspect() string { return formatException(e, e.message) }
+func (e *FloatDomainError) Error() string   { return e.message }
+
+func (e *FloatDomainError) setErrorMessage(msg string) {
+	e.message = msg
+}
+
+// Class returns floatDomainErrorClass
+func (e *FloatDomainError) Class() RubyClass { return floatDomainErrorClass }
+
+// NewRegexpError creates a RegexpError. It has the same API as fmt.Errorf
+func NewRegexpError(format string, args ...interface{}) *RegexpError {
+	return &RegexpError{
+		message: fmt.Sprintf(format, args...),
+	}
+}
+
+// RegexpError represents an error when compiling an invalid regular expression
+type RegexpError struct {
+	message string
+}
+
+// Type returns EXCEPTION_OBJ
+func (e *RegexpError) Type() Type { return EXCEPTION_OBJ }
+
+// Inspect returns a string starting with the exception class name, followed by the message
+func (e *RegexpError) Inspect() string { return formatException(e, e.message) }
+func (e *RegexpError) Error() string   { return e.message }
+
+func (e *RegexpError) setErrorMessage(msg string) {
+	e.message = msg
+}
+
+// Class returns regexpErrorClass
+func (e *RegexpError) Class() RubyClass { return regexpErrorClass }
+
 // NewUninitializedConstantNameError returns a NameError with the default message for uninitialized constants
 func NewUninitializedConstantNameError(name string) *NameError {
 	return &NameError{
@@ -401,6 +572,45 @@ func NewUndefinedLocalVariableOrMethodNameError(context RubyObject, name string)
 	}
 }
 
+// NewUndefinedMethodNameError returns a NameError with the default message for
+// undefined methods referenced by alias or undef
+func NewUndefinedMethodNameError(owner RubyObject, method string) *NameError {
+	kind, name := describeMethodOwner(owner)
+	return &NameError{
+		message: fmt.Sprintf(
+			"undefined method `%s' for %s `%s'",
+			method,
+			kind,
+			name,
+		),
+	}
+}
+
+// NewMethodNotDefinedNameError returns a NameError with the default message for
+// removing methods not defined in owner
+func NewMethodNotDefinedNameError(owner RubyObject, method string) *NameError {
+	_, name := describeMethodOwner(owner)
+	return &NameError{
+		message: fmt.Sprintf(
+			"method `%s' not defined in %s",
+			method,
+			name,
+		),
+	}
+}
+
+// describeMethodOwner returns whether owner is a class or a module and its
+// name. Objects other than classes and modules are described by their class.
+func describeMethodOwner(owner RubyObject) (string, string) {
+	if owner.Type() == MODULE_OBJ {
+		return "module", owner.Inspect()
+	}
+	if _, ok := owner.(RubyClass); !ok {
+		owner = owner.Class().(RubyObject)
+	}
+	return "class", owner.Inspect()
+}
+
 // A NameError represents an error accessing an identifier unknown to the environment
 type NameError struct {
 	message string
@@ -432,6 +642,19 @@ func NewNoMethodError(context RubyObject, method string) *NoMethodError {
 	}
 }
 
+// NewNoSuperMethodError returns a NoMethodError with the default message for
+// a super call without a method to call in the ancestors
+func NewNoSuperMethodError(context RubyObject, method string) *NoMethodError {
+	return &NoMethodError{
+		message: fmt.Sprintf(
+			"super: no superclass method `%s' for %s:%s",
+			method,
+			context.Inspect(),
+			context.Class().(RubyObject).Inspect(),
+		),
+	}
+}
+
 // NewPrivateNoMethodError returns a NoMethodError with the default message for private methods
 func NewPrivateNoMethodError(context RubyObject, method string) *NoMethodError {
 	return &NoMethodError{
@@ -496,6 +719,21 @@ func NewImplicitConversionTypeError(expected, actual RubyObject) *TypeError {
 	}
 }
 
+// NewConversionResultTypeError returns a TypeError for a conversion method
+// like to_proc returning an object not of the expected class
+func NewConversionResultTypeError(expected string, obj RubyObject, method string, result RubyObject) *TypeError {
+	return &TypeError{
+		message: fmt.Sprintf(
+			"can't convert %s to %s (%s#%s gives %s)",
+			obj.Class().Name(),
+			expected,
+			obj.Class().Name(),
+			method,
+			result.Class().Name(),
+		),
+	}
+}
+
 // NewTypeError returns a TypeError with the provided message
 func NewTypeError(message string) *TypeError {
 	return &TypeError{message: message}
@@ -631,6 +869,13 @@ func (e *NotImplementedError) setErrorMessage(msg string) {
 // Class returns notImplementedErrorClass
 func (e *NotImplementedError) Class() RubyClass { return notImplementedErrorClass }
 
+// NewLocalJumpError returns a LocalJumpError. It has the same API as fmt.Errorf
+func NewLocalJumpError(format string, args ...interface{}) *LocalJumpError {
+	return &LocalJumpError{
+		message: fmt.Sprintf(format, args...),
+	}
+}
+
 // NewNoBlockGivenLocalJumpError returns a LocalJumpError with the default message for missing blocks
 func NewNoBlockGivenLocalJumpError() *LocalJumpError {
 	return &LocalJumpError{message: "no block given (yield)"}
@@ -652,5 +897,31 @@ func (e *LocalJumpError) setErrorMessage(msg string) {
 	e.message = msg
 }
 
-// Class returns notImplementedErrorClass
-func (e *LocalJumpError) Class() RubyClass { return notImplementedErrorClass }
+// Class returns localJumpErrorClass
+func (e *LocalJumpError) Class() RubyClass { return localJumpErrorClass }
+
+// NewNoMatchingPatternError returns a NoMatchingPatternError for value not
+// matching any pattern
+func NewNoMatchingPatternError(value RubyObject) *NoMatchingPatternError {
+	return &NoMatchingPatternError{message: value.Inspect()}
+}
+
+// NoMatchingPatternError represents an error for a value not matching any
+// pattern of a pattern match
+type NoMatchingPatternError struct {
+	message string
+}
+
+// Type returns EXCEPTION_OBJ
+func (e *NoMatchingPatternError) Type() Type { return EXCEPTION_OBJ }
+
+// Inspect returns a string starting with the exception class name, followed by the message
+func (e *NoMatchingPatternError) Inspect() string { return formatException(e, e.message) }
+func (e *NoMatchingPatternError) Error() string   { return e.message }
+
+func (e *NoMatchingPatternError) setErrorMessage(msg string) {
+	e.message = msg
+}
+
+// Class returns noMatchingPatternErrorClass
+func (e *NoMatchingPatternError) Class() RubyClass { return noMatchingPatternErrorClass }
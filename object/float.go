@@ -0,0 +1,375 @@
+package object
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+var floatClass RubyClassObject = newClass(
+	"Float", objectClass, floatMethods, floatClassMethods, notInstantiatable,
+)
+
+func init() {
+	classes.Set("Float", floatClass)
+}
+
+// NewFloat returns a new Float with the given value
+func NewFloat(value float64) *Float {
+	return &Float{Value: value}
+}
+
+// Float represents a double precision floating point number in Ruby
+type Float struct {
+	Value float64
+}
+
+// Inspect returns the value as string, formatted the way Ruby does
+func (f *Float) Inspect() string {
+	switch {
+	case math.IsNaN(f.Value):
+		return "NaN"
+	case math.IsInf(f.Value, 1):
+		return "Infinity"
+	case math.IsInf(f.Value, -1):
+		return "-Infinity"
+	}
+	abs := math.Abs(f.Value)
+	if abs >= 1e16 || (abs < 1e-4 && abs != 0) {
+		s := strconv.FormatFloat(f.Value, 'e', -1, 64)
+		mantissa, exponent := s[:strings.IndexByte(s, 'e')], s[strings.IndexByte(s, 'e'):]
+		if !strings.Contains(mantissa, ".") {
+			mantissa += ".0"
+		}
+		return mantissa + exponent
+	}
+	s := strconv.FormatFloat(f.Value, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
+
+// Type returns FLOAT_OBJ
+func (f *Float) Type() Type { return FLOAT_OBJ }
+
+// Class returns floatClass
+func (f *Float) Class() RubyClass { return floatClass }
+
+func (f *Float) hashKey() hashKey {
+	return hashKey{Type: f.Type(), Value: math.Float64bits(f.Value)}
+}
+
+// floatValue returns the float value of Integers and Floats. It returns false
+// for any other object.
+func floatValue(obj RubyObject) (float64, bool) {
+	switch obj := obj.(type) {
+	case *Float:
+		return obj.Value, true
+	case *Integer:
+		return float64(obj.Value), true
+	default:
+		return 0, false
+	}
+}
+
+var floatClassMethods = map[string]RubyMethod{}
+
+var floatMethods = map[string]RubyMethod{
+	"/":         withArity(1, publicMethod(floatDiv)),
+	"*":         withArity(1, publicMethod(floatMul)),
+	"+":         withArity(1, publicMethod(floatAdd)),
+	"-":         withArity(1, publicMethod(floatSub)),
+	"%":         withArity(1, publicMethod(floatModulo)),
+	"modulo":    withArity(1, publicMethod(floatModulo)),
+	"**":        withArity(1, publicMethod(floatPow)),
+	"div":       withArity(1, publicMethod(floatFloorDiv)),
+	"divmod":    withArity(1, publicMethod(floatDivmod)),
+	"fdiv":      withArity(1, publicMethod(floatDiv)),
+	"-@":        withArity(0, publicMethod(floatNegate)),
+	"+@":        withArity(0, publicMethod(floatToF)),
+	"abs":       withArity(0, publicMethod(floatAbs)),
+	"<":         withArity(1, publicMethod(floatLt)),
+	">":         withArity(1, publicMethod(floatGt)),
+	"==":        withArity(1, publicMethod(floatEq)),
+	"!=":        withArity(1, publicMethod(floatNeq)),
+	">=":        withArity(1, publicMethod(floatGte)),
+	"<=":        withArity(1, publicMethod(floatLte)),
+	"<=>":       withArity(1, publicMethod(floatSpaceship)),
+	"coerce":    withArity(1, publicMethod(floatCoerce)),
+	"to_s":      withArity(0, publicMethod(floatToS)),
+	"to_i":      withArity(0, publicMethod(floatToI)),
+	"to_f":      withArity(0, publicMethod(floatToF)),
+	"round":     publicMethod(floatRounding(math.Round)),
+	"floor":     publicMethod(floatRounding(math.Floor)),
+	"ceil":      publicMethod(floatRounding(math.Ceil)),
+	"nan?":      withArity(0, publicMethod(floatIsNaN)),
+	"infinite?": withArity(0, publicMethod(floatIsInfinite)),
+	"finite?":   withArity(0, publicMethod(floatIsFinite)),
+}
+
+func floatToS(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	return &String{Value: f.Inspect()}, nil
+}
+
+func floatToF(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver(), nil
+}
+
+func floatToI(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	i, err := floatToInteger(f.Value)
+	if err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// floatToInteger converts value to an Integer, truncating its fraction. It
+// returns a FloatDomainError for NaN and Infinity and a RangeError if value
+// exceeds the Integer range.
+func floatToInteger(value float64) (*Integer, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil, NewFloatDomainError(NewFloat(value).Inspect())
+	}
+	if value >= float64(math.MaxInt64) || value < float64(math.MinInt64) {
+		return nil, NewRangeError("float %s out of range of integer", NewFloat(value).Inspect())
+	}
+	return NewInteger(int64(value)), nil
+}
+
+func floatCoerce(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	value, ok := floatValue(args[0])
+	if !ok {
+		return nil, NewCoercionTypeError(f, args[0])
+	}
+	return NewArray(NewFloat(value), f), nil
+}
+
+// floatRounding returns a method rounding the receiver with round to the
+// number of decimal digits given as optional argument. Without digits or with
+// non-positive digits the result is an Integer, otherwise a Float.
+func floatRounding(round func(float64) float64) func(CallContext, ...RubyObject) (RubyObject, error) {
+	return func(context CallContext, args ...RubyObject) (RubyObject, error) {
+		f := context.Receiver().(*Float)
+		if len(args) > 1 {
+			return nil, NewWrongNumberOfArgumentsError(1, len(args))
+		}
+		var digits int64
+		if len(args) == 1 {
+			d, ok := args[0].(*Integer)
+			if !ok {
+				return nil, NewImplicitConversionTypeError(NewInteger(0), args[0])
+			}
+			digits = d.Value
+		}
+		if digits > 0 {
+			if math.IsNaN(f.Value) || math.IsInf(f.Value, 0) {
+				return f, nil
+			}
+			factor := math.Pow(10, float64(digits))
+			return NewFloat(round(f.Value*factor) / factor), nil
+		}
+		factor := math.Pow(10, float64(-digits))
+		i, err := floatToInteger(round(f.Value/factor) * factor)
+		if err != nil {
+			return nil, err
+		}
+		return i, nil
+	}
+}
+
+func floatIsNaN(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	return nativeBoolToBoolean(math.IsNaN(f.Value)), nil
+}
+
+func floatIsInfinite(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	switch {
+	case math.IsInf(f.Value, 1):
+		return NewInteger(1), nil
+	case math.IsInf(f.Value, -1):
+		return NewInteger(-1), nil
+	default:
+		return NIL, nil
+	}
+}
+
+func floatIsFinite(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	return nativeBoolToBoolean(!math.IsNaN(f.Value) && !math.IsInf(f.Value, 0)), nil
+}
+
+// floatArithmetic returns the result of op applied to the receiver and the
+// argument. Integer arguments are converted into floats, any other argument is
+// coerced.
+func floatArithmetic(context CallContext, method string, arg RubyObject, op func(a, b float64) float64) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	right, ok := floatValue(arg)
+	if !ok {
+		result, coerced, err := coerceOperation(context, method, arg)
+		if coerced {
+			return result, err
+		}
+		return nil, NewCoercionTypeError(f, arg)
+	}
+	return NewFloat(op(f.Value, right)), nil
+}
+
+func floatDiv(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return floatArithmetic(context, "/", args[0], func(a, b float64) float64 { return a / b })
+}
+
+func floatMul(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return floatArithmetic(context, "*", args[0], func(a, b float64) float64 { return a * b })
+}
+
+func floatAdd(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return floatArithmetic(context, "+", args[0], func(a, b float64) float64 { return a + b })
+}
+
+func floatSub(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return floatArithmetic(context, "-", args[0], func(a, b float64) float64 { return a - b })
+}
+
+func floatModulo(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return floatArithmetic(context, "%", args[0], floatMod)
+}
+
+// floatMod returns the modulo of a and b, which takes the sign of b
+func floatMod(a, b float64) float64 {
+	mod := math.Mod(a, b)
+	if mod != 0 && (mod < 0) != (b < 0) {
+		mod += b
+	}
+	return mod
+}
+
+func floatPow(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return floatArithmetic(context, "**", args[0], math.Pow)
+}
+
+func floatFloorDiv(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	right, ok := floatValue(args[0])
+	if !ok {
+		result, coerced, err := coerceOperation(context, "div", args[0])
+		if coerced {
+			return result, err
+		}
+		return nil, NewCoercionTypeError(f, args[0])
+	}
+	quotient, err := floorDiv(f.Value, right)
+	if err != nil {
+		return nil, err
+	}
+	return quotient, nil
+}
+
+// floatDivmod returns the floored quotient as Integer and the modulo of the
+// receiver and the argument
+func floatDivmod(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	right, ok := floatValue(args[0])
+	if !ok {
+		result, coerced, err := coerceOperation(context, "divmod", args[0])
+		if coerced {
+			return result, err
+		}
+		return nil, NewCoercionTypeError(f, args[0])
+	}
+	quotient, err := floorDiv(f.Value, right)
+	if err != nil {
+		return nil, err
+	}
+	return NewArray(quotient, NewFloat(floatMod(f.Value, right))), nil
+}
+
+// floorDiv returns the quotient of a and b rounded towards negative infinity
+func floorDiv(a, b float64) (*Integer, error) {
+	if b == 0 {
+		return nil, NewZeroDivisionError()
+	}
+	return floatToInteger(math.Floor(a / b))
+}
+
+func floatNegate(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	return NewFloat(-f.Value), nil
+}
+
+func floatAbs(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	return NewFloat(math.Abs(f.Value)), nil
+}
+
+// floatComparison returns the result of cmp applied to the receiver and the
+// argument. Non numeric arguments are coerced.
+func floatComparison(context CallContext, method string, arg RubyObject, cmp func(a, b float64) bool) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	right, ok := floatValue(arg)
+	if !ok {
+		result, coerced, err := coerceOperation(context, method, arg)
+		if coerced {
+			return result, err
+		}
+		return nil, NewArgumentError(
+			"comparison of Float with %s failed",
+			arg.Class().(RubyObject).Inspect(),
+		)
+	}
+	return nativeBoolToBoolean(cmp(f.Value, right)), nil
+}
+
+func floatLt(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return floatComparison(context, "<", args[0], func(a, b float64) bool { return a < b })
+}
+
+func floatGt(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return floatComparison(context, ">", args[0], func(a, b float64) bool { return a > b })
+}
+
+func floatGte(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return floatComparison(context, ">=", args[0], func(a, b float64) bool { return a >= b })
+}
+
+func floatLte(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return floatComparison(context, "<=", args[0], func(a, b float64) bool { return a <= b })
+}
+
+func floatEq(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	right, ok := floatValue(args[0])
+	if !ok {
+		return FALSE, nil
+	}
+	return nativeBoolToBoolean(f.Value == right), nil
+}
+
+func floatNeq(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	right, ok := floatValue(args[0])
+	if !ok {
+		return TRUE, nil
+	}
+	return nativeBoolToBoolean(f.Value != right), nil
+}
+
+func floatSpaceship(context CallContext, args ...RubyObject) (RubyObject, error) {
+	f := context.Receiver().(*Float)
+	right, ok := floatValue(args[0])
+	if !ok || math.IsNaN(f.Value) || math.IsNaN(right) {
+		return NIL, nil
+	}
+	switch {
+	case f.Value > right:
+		return NewInteger(1), nil
+	case f.Value < right:
+		return NewInteger(-1), nil
+	default:
+		return NewInteger(0), nil
+	}
+}
@@ -0,0 +1,330 @@
+package object
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFloat_hashKey(t *testing.T) {
+	hello1 := NewFloat(1.5)
+	hello2 := NewFloat(1.5)
+	diff := NewFloat(2.5)
+
+	if hello1.hashKey() != hello2.hashKey() {
+		t.Errorf("floats with same content have different hash keys")
+	}
+
+	if hello1.hashKey() == diff.hashKey() {
+		t.Errorf("floats with different content have same hash keys")
+	}
+
+	if NewFloat(1).hashKey() == NewInteger(1).hashKey() {
+		t.Errorf("float and integer with same value have same hash keys")
+	}
+}
+
+func TestFloatInspect(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{1, "1.0"},
+		{1.5, "1.5"},
+		{-0.25, "-0.25"},
+		{0.0001, "0.0001"},
+		{0.00001, "1.0e-05"},
+		{1e15, "1000000000000000.0"},
+		{1e16, "1.0e+16"},
+		{1.5e20, "1.5e+20"},
+		{math.Inf(1), "Infinity"},
+		{math.Inf(-1), "-Infinity"},
+		{math.NaN(), "NaN"},
+	}
+
+	for _, tt := range tests {
+		actual := NewFloat(tt.value).Inspect()
+
+		if actual != tt.expected {
+			t.Logf("Expected %q, got %q\n", tt.expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func TestFloatAdd(t *testing.T) {
+	tests := []struct {
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{
+			[]RubyObject{NewFloat(0.5)},
+			NewFloat(2),
+			nil,
+		},
+		{
+			[]RubyObject{NewInteger(2)},
+			NewFloat(3.5),
+			nil,
+		},
+		{
+			[]RubyObject{&String{""}},
+			nil,
+			NewCoercionTypeError(&Float{}, &String{}),
+		},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(1.5)}
+
+		result, err := floatAdd(context, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatDiv(t *testing.T) {
+	tests := []struct {
+		arguments []RubyObject
+		result    RubyObject
+	}{
+		{
+			[]RubyObject{NewInteger(2)},
+			NewFloat(1.5),
+		},
+		{
+			[]RubyObject{NewFloat(0)},
+			NewFloat(math.Inf(1)),
+		},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(3)}
+
+		result, err := floatDiv(context, testCase.arguments...)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatModulo(t *testing.T) {
+	tests := []struct {
+		receiver float64
+		argument RubyObject
+		result   RubyObject
+	}{
+		{7.5, NewInteger(2), NewFloat(1.5)},
+		{-7.5, NewInteger(2), NewFloat(0.5)},
+		{7.5, NewInteger(-2), NewFloat(-0.5)},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(testCase.receiver)}
+
+		result, err := floatModulo(context, testCase.argument)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatPow(t *testing.T) {
+	tests := []struct {
+		receiver float64
+		argument RubyObject
+		result   RubyObject
+	}{
+		{2, NewInteger(3), NewFloat(8)},
+		{4, NewFloat(0.5), NewFloat(2)},
+		{2, NewInteger(-1), NewFloat(0.5)},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(testCase.receiver)}
+
+		result, err := floatPow(context, testCase.argument)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatDivmod(t *testing.T) {
+	tests := []struct {
+		receiver float64
+		argument RubyObject
+		result   RubyObject
+		err      error
+	}{
+		{7.5, NewInteger(2), NewArray(NewInteger(3), NewFloat(1.5)), nil},
+		{-7.5, NewInteger(2), NewArray(NewInteger(-4), NewFloat(0.5)), nil},
+		{7.5, NewFloat(-2), NewArray(NewInteger(-4), NewFloat(-0.5)), nil},
+		{7.5, NewInteger(0), nil, NewZeroDivisionError()},
+		{math.Inf(1), NewInteger(2), nil, NewFloatDomainError("Infinity")},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(testCase.receiver)}
+
+		result, err := floatDivmod(context, testCase.argument)
+
+		checkError(t, err, testCase.err)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatFloorDiv(t *testing.T) {
+	tests := []struct {
+		receiver float64
+		argument RubyObject
+		result   RubyObject
+		err      error
+	}{
+		{7.5, NewInteger(2), NewInteger(3), nil},
+		{-7.5, NewFloat(2), NewInteger(-4), nil},
+		{7.5, NewFloat(0), nil, NewZeroDivisionError()},
+		{1e300, NewFloat(0.5), nil, NewRangeError("float 2.0e+300 out of range of integer")},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(testCase.receiver)}
+
+		result, err := floatFloorDiv(context, testCase.argument)
+
+		checkError(t, err, testCase.err)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatUnaryOperations(t *testing.T) {
+	tests := []struct {
+		method   func(CallContext, ...RubyObject) (RubyObject, error)
+		receiver float64
+		result   RubyObject
+	}{
+		{floatNegate, 1.5, NewFloat(-1.5)},
+		{floatNegate, -1.5, NewFloat(1.5)},
+		{floatAbs, -1.5, NewFloat(1.5)},
+		{floatAbs, 1.5, NewFloat(1.5)},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(testCase.receiver)}
+
+		result, err := testCase.method(context)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatRounding(t *testing.T) {
+	tests := []struct {
+		method    string
+		receiver  float64
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{"round", 2.5, nil, NewInteger(3), nil},
+		{"round", -2.5, nil, NewInteger(-3), nil},
+		{"round", 3.14159, []RubyObject{NewInteger(3)}, NewFloat(3.142), nil},
+		{"round", 1250, []RubyObject{NewInteger(-2)}, NewInteger(1300), nil},
+		{"floor", -3.2, nil, NewInteger(-4), nil},
+		{"floor", 3.19, []RubyObject{NewInteger(1)}, NewFloat(3.1), nil},
+		{"ceil", 3.2, nil, NewInteger(4), nil},
+		{"ceil", -3.2, nil, NewInteger(-3), nil},
+		{"round", math.NaN(), nil, nil, NewFloatDomainError("NaN")},
+		{"floor", 1e300, nil, nil, NewRangeError("float 1.0e+300 out of range of integer")},
+		{"round", 1.5, []RubyObject{NewInteger(1), NewInteger(1)}, nil, NewWrongNumberOfArgumentsError(1, 2)},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(testCase.receiver)}
+		method := floatMethods[testCase.method]
+
+		result, err := method.Call(context, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatToI(t *testing.T) {
+	tests := []struct {
+		receiver float64
+		result   RubyObject
+		err      error
+	}{
+		{3.9, NewInteger(3), nil},
+		{-3.9, NewInteger(-3), nil},
+		{math.Inf(1), nil, NewFloatDomainError("Infinity")},
+		{math.NaN(), nil, NewFloatDomainError("NaN")},
+		{1e300, nil, NewRangeError("float 1.0e+300 out of range of integer")},
+		{-1e300, nil, NewRangeError("float -1.0e+300 out of range of integer")},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(testCase.receiver)}
+
+		result, err := floatToI(context)
+
+		checkError(t, err, testCase.err)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatIsInfinite(t *testing.T) {
+	tests := []struct {
+		receiver float64
+		result   RubyObject
+	}{
+		{1.5, NIL},
+		{math.Inf(1), NewInteger(1)},
+		{math.Inf(-1), NewInteger(-1)},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(testCase.receiver)}
+
+		result, err := floatIsInfinite(context)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestFloatSpaceship(t *testing.T) {
+	tests := []struct {
+		arguments []RubyObject
+		result    RubyObject
+	}{
+		{[]RubyObject{NewInteger(1)}, NewInteger(1)},
+		{[]RubyObject{NewFloat(1.5)}, NewInteger(0)},
+		{[]RubyObject{NewFloat(2)}, NewInteger(-1)},
+		{[]RubyObject{NewFloat(math.NaN())}, NIL},
+		{[]RubyObject{&String{""}}, NIL},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewFloat(1.5)}
+
+		result, err := floatSpaceship(context, testCase.arguments...)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, testCase.result)
+	}
+}
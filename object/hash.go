@@ -106,4 +106,10 @@ func (h *Hash) hashKey() hashKey {
 
 var hashClassMethods = map[string]RubyMethod{}
 
-var hashMethods = map[string]RubyMethod{}
+var hashMethods = map[string]RubyMethod{
+	"deconstruct_keys": withArity(1, publicMethod(hashDeconstructKeys)),
+}
+
+func hashDeconstructKeys(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver(), nil
+}
@@ -171,6 +171,21 @@ func TestHashMap(t *testing.T) {
 	})
 }
 
+func TestHashDeconstructKeys(t *testing.T) {
+	hash := &Hash{}
+	hash.Set(&Symbol{Value: "a"}, &Integer{Value: 1})
+	context := &callContext{receiver: hash}
+
+	result, err := hashDeconstructKeys(context, NIL)
+
+	checkError(t, err, nil)
+
+	if result != hash {
+		t.Logf("Expected result to be the receiver, got %v", result)
+		t.Fail()
+	}
+}
+
 func Test_hash(t *testing.T) {
 	t.Run("hashable object", func(t *testing.T) {
 		obj := &String{Value: "bar"}
@@ -1,6 +1,11 @@
 package object
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+)
 
 var integerClass RubyClassObject = newClass(
 	"Integer", objectClass, integerMethods, integerClassMethods, notInstantiatable,
@@ -36,25 +41,75 @@ func (i *Integer) hashKey() hashKey {
 var integerClassMethods = map[string]RubyMethod{}
 
 var integerMethods = map[string]RubyMethod{
-	"div": withArity(1, publicMethod(integerDiv)),
-	"/":   withArity(1, publicMethod(integerDiv)),
-	"*":   withArity(1, publicMethod(integerMul)),
-	"+":   withArity(1, publicMethod(integerAdd)),
-	"-":   withArity(1, publicMethod(integerSub)),
-	"%":   withArity(1, publicMethod(integerModulo)),
-	"<":   withArity(1, publicMethod(integerLt)),
-	">":   withArity(1, publicMethod(integerGt)),
-	"==":  withArity(1, publicMethod(integerEq)),
-	"!=":  withArity(1, publicMethod(integerNeq)),
-	">=":  withArity(1, publicMethod(integerGte)),
-	"<=":  withArity(1, publicMethod(integerLte)),
-	"<=>": withArity(1, publicMethod(integerSpaceship)),
+	"div":        withArity(1, publicMethod(integerFloorDiv)),
+	"/":          withArity(1, publicMethod(integerDiv)),
+	"*":          withArity(1, publicMethod(integerMul)),
+	"+":          withArity(1, publicMethod(integerAdd)),
+	"-":          withArity(1, publicMethod(integerSub)),
+	"%":          withArity(1, publicMethod(integerModulo)),
+	"**":         withArity(1, publicMethod(integerPow)),
+	"pow":        publicMethod(integerModPow),
+	"&":          withArity(1, publicMethod(integerBitwise("&", func(a, b int64) int64 { return a & b }))),
+	"|":          withArity(1, publicMethod(integerBitwise("|", func(a, b int64) int64 { return a | b }))),
+	"^":          withArity(1, publicMethod(integerBitwise("^", func(a, b int64) int64 { return a ^ b }))),
+	"~":          withArity(0, publicMethod(integerComplement)),
+	"<<":         withArity(1, publicMethod(integerLeftShift)),
+	">>":         withArity(1, publicMethod(integerRightShift)),
+	"[]":         withArity(1, publicMethod(integerBit)),
+	"bit_length": withArity(0, publicMethod(integerBitLength)),
+	"<":          withArity(1, publicMethod(integerLt)),
+	">":          withArity(1, publicMethod(integerGt)),
+	"==":         withArity(1, publicMethod(integerEq)),
+	"!=":         withArity(1, publicMethod(integerNeq)),
+	"===":        withArity(1, publicMethod(integerCaseEqual)),
+	">=":         withArity(1, publicMethod(integerGte)),
+	"<=":         withArity(1, publicMethod(integerLte)),
+	"<=>":        withArity(1, publicMethod(integerSpaceship)),
+	"to_s":       withArity(0, publicMethod(integerToS)),
+	"to_f":       withArity(0, publicMethod(integerToF)),
+	"fdiv":       withArity(1, publicMethod(integerFdiv)),
+	"coerce":     withArity(1, publicMethod(integerCoerce)),
+}
+
+func integerToS(context CallContext, args ...RubyObject) (RubyObject, error) {
+	i := context.Receiver().(*Integer)
+	return &String{Value: i.Inspect()}, nil
+}
+
+func integerToF(context CallContext, args ...RubyObject) (RubyObject, error) {
+	i := context.Receiver().(*Integer)
+	return NewFloat(float64(i.Value)), nil
+}
+
+func integerFdiv(context CallContext, args ...RubyObject) (RubyObject, error) {
+	i := context.Receiver().(*Integer)
+	divisor, ok := floatValue(args[0])
+	if !ok {
+		return nil, NewCoercionTypeError(args[0], i)
+	}
+	return NewFloat(float64(i.Value) / divisor), nil
+}
+
+func integerCoerce(context CallContext, args ...RubyObject) (RubyObject, error) {
+	i := context.Receiver().(*Integer)
+	switch arg := args[0].(type) {
+	case *Integer:
+		return NewArray(arg, i), nil
+	case *Float:
+		return NewArray(arg, NewFloat(float64(i.Value))), nil
+	default:
+		return nil, NewCoercionTypeError(i, arg)
+	}
 }
 
 func integerDiv(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
 	divisor, ok := args[0].(*Integer)
 	if !ok {
+		result, coerced, err := coerceOperation(context, "/", args[0])
+		if coerced {
+			return result, err
+		}
 		return nil, NewCoercionTypeError(args[0], i)
 	}
 	if divisor.Value == 0 {
@@ -63,10 +118,43 @@ func integerDiv(context CallContext, args ...RubyObject) (RubyObject, error) {
 	return NewInteger(i.Value / divisor.Value), nil
 }
 
+// integerFloorDiv implements Integer#div, which always rounds the quotient
+// towards negative infinity and returns an Integer, even for a Float divisor
+func integerFloorDiv(context CallContext, args ...RubyObject) (RubyObject, error) {
+	i := context.Receiver().(*Integer)
+	switch divisor := args[0].(type) {
+	case *Integer:
+		if divisor.Value == 0 {
+			return nil, NewZeroDivisionError()
+		}
+		quotient := i.Value / divisor.Value
+		if i.Value%divisor.Value != 0 && (i.Value < 0) != (divisor.Value < 0) {
+			quotient--
+		}
+		return NewInteger(quotient), nil
+	case *Float:
+		quotient, err := floorDiv(float64(i.Value), divisor.Value)
+		if err != nil {
+			return nil, err
+		}
+		return quotient, nil
+	default:
+		result, coerced, err := coerceOperation(context, "div", args[0])
+		if coerced {
+			return result, err
+		}
+		return nil, NewCoercionTypeError(args[0], i)
+	}
+}
+
 func integerMul(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
 	factor, ok := args[0].(*Integer)
 	if !ok {
+		result, coerced, err := coerceOperation(context, "*", args[0])
+		if coerced {
+			return result, err
+		}
 		return nil, NewCoercionTypeError(args[0], i)
 	}
 	return NewInteger(i.Value * factor.Value), nil
@@ -76,6 +164,10 @@ func integerAdd(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
 	add, ok := args[0].(*Integer)
 	if !ok {
+		result, coerced, err := coerceOperation(context, "+", args[0])
+		if coerced {
+			return result, err
+		}
 		return nil, NewCoercionTypeError(args[0], i)
 	}
 	return NewInteger(i.Value + add.Value), nil
@@ -85,6 +177,10 @@ func integerSub(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
 	sub, ok := args[0].(*Integer)
 	if !ok {
+		result, coerced, err := coerceOperation(context, "-", args[0])
+		if coerced {
+			return result, err
+		}
 		return nil, NewCoercionTypeError(args[0], i)
 	}
 	return NewInteger(i.Value - sub.Value), nil
@@ -94,6 +190,10 @@ func integerModulo(context CallContext, args ...RubyObject) (RubyObject, error)
 	i := context.Receiver().(*Integer)
 	mod, ok := args[0].(*Integer)
 	if !ok {
+		result, coerced, err := coerceOperation(context, "%", args[0])
+		if coerced {
+			return result, err
+		}
 		return nil, NewCoercionTypeError(args[0], i)
 	}
 	return NewInteger(i.Value % mod.Value), nil
@@ -103,6 +203,10 @@ func integerLt(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
 	right, ok := args[0].(*Integer)
 	if !ok {
+		result, coerced, err := coerceOperation(context, "<", args[0])
+		if coerced {
+			return result, err
+		}
 		return nil, NewArgumentError(
 			"comparison of Integer with %s failed",
 			args[0].Class().(RubyObject).Inspect(),
@@ -118,6 +222,10 @@ func integerGt(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
 	right, ok := args[0].(*Integer)
 	if !ok {
+		result, coerced, err := coerceOperation(context, ">", args[0])
+		if coerced {
+			return result, err
+		}
 		return nil, NewArgumentError(
 			"comparison of Integer with %s failed",
 			args[0].Class().(RubyObject).Inspect(),
@@ -133,6 +241,10 @@ func integerEq(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
 	right, ok := args[0].(*Integer)
 	if !ok {
+		result, coerced, err := coerceOperation(context, "==", args[0])
+		if coerced {
+			return result, err
+		}
 		return nil, NewArgumentError(
 			"comparison of Integer with %s failed",
 			args[0].Class().(RubyObject).Inspect(),
@@ -144,10 +256,27 @@ func integerEq(context CallContext, args ...RubyObject) (RubyObject, error) {
 	return FALSE, nil
 }
 
+// integerCaseEqual behaves like integerEq but is false for objects which
+// can't be compared, so that it can be used in case and pattern matching
+func integerCaseEqual(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if _, ok := args[0].(*Integer); ok {
+		return integerEq(context, args...)
+	}
+	result, coerced, err := coerceOperation(context, "==", args[0])
+	if !coerced {
+		return FALSE, nil
+	}
+	return result, err
+}
+
 func integerNeq(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
 	right, ok := args[0].(*Integer)
 	if !ok {
+		result, coerced, err := coerceOperation(context, "!=", args[0])
+		if coerced {
+			return result, err
+		}
 		return nil, NewArgumentError(
 			"comparison of Integer with %s failed",
 			args[0].Class().(RubyObject).Inspect(),
@@ -163,6 +292,10 @@ func integerSpaceship(context CallContext, args ...RubyObject) (RubyObject, erro
 	i := context.Receiver().(*Integer)
 	right, ok := args[0].(*Integer)
 	if !ok {
+		result, coerced, err := coerceOperation(context, "<=>", args[0])
+		if coerced {
+			return result, err
+		}
 		return NIL, nil
 	}
 	switch {
@@ -181,6 +314,10 @@ func integerGte(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
 	right, ok := args[0].(*Integer)
 	if !ok {
+		result, coerced, err := coerceOperation(context, ">=", args[0])
+		if coerced {
+			return result, err
+		}
 		return nil, NewArgumentError(
 			"comparison of Integer with %s failed",
 			args[0].Class().(RubyObject).Inspect(),
@@ -196,6 +333,10 @@ func integerLte(context CallContext, args ...RubyObject) (RubyObject, error) {
 	i := context.Receiver().(*Integer)
 	right, ok := args[0].(*Integer)
 	if !ok {
+		result, coerced, err := coerceOperation(context, "<=", args[0])
+		if coerced {
+			return result, err
+		}
 		return nil, NewArgumentError(
 			"comparison of Integer with %s failed",
 			args[0].Class().(RubyObject).Inspect(),
@@ -206,3 +347,185 @@ func integerLte(context CallContext, args ...RubyObject) (RubyObject, error) {
 	}
 	return FALSE, nil
 }
+
+func integerPow(context CallContext, args ...RubyObject) (RubyObject, error) {
+	i := context.Receiver().(*Integer)
+	switch exponent := args[0].(type) {
+	case *Integer:
+		if exponent.Value < 0 {
+			if i.Value == 0 {
+				return nil, NewZeroDivisionError()
+			}
+			// there are no Rationals, so negative exponents result in a Float
+			return NewFloat(math.Pow(float64(i.Value), float64(exponent.Value))), nil
+		}
+		result, ok := intPow(i.Value, exponent.Value)
+		if !ok {
+			return nil, NewRangeError("%d ** %d exceeds the Integer range", i.Value, exponent.Value)
+		}
+		return NewInteger(result), nil
+	case *Float:
+		return NewFloat(math.Pow(float64(i.Value), exponent.Value)), nil
+	default:
+		result, coerced, err := coerceOperation(context, "**", args[0])
+		if coerced {
+			return result, err
+		}
+		return nil, NewCoercionTypeError(args[0], i)
+	}
+}
+
+func integerModPow(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, NewWrongNumberOfArgumentsRangeError(1, 2, false, len(args))
+	}
+	if len(args) == 1 {
+		return integerPow(context, args...)
+	}
+	i := context.Receiver().(*Integer)
+	exponent, ok := args[0].(*Integer)
+	if !ok {
+		return nil, NewTypeError("Integer#pow() 2nd argument not allowed unless a 1st argument is integer")
+	}
+	if exponent.Value < 0 {
+		return nil, NewRangeError("Integer#pow() 1st argument cannot be negative when 2nd argument specified")
+	}
+	modulus, ok := args[1].(*Integer)
+	if !ok {
+		return nil, NewTypeError("Integer#pow() 2nd argument not allowed unless all arguments are integers")
+	}
+	if modulus.Value == 0 {
+		return nil, NewZeroDivisionError()
+	}
+	m := big.NewInt(modulus.Value)
+	result := new(big.Int).Exp(big.NewInt(i.Value), big.NewInt(exponent.Value), new(big.Int).Abs(m))
+	// like Integer#%, the result takes the sign of the modulus
+	if result.Sign() != 0 && m.Sign() < 0 {
+		result.Add(result, m)
+	}
+	return NewInteger(result.Int64()), nil
+}
+
+// intPow returns base raised to the non-negative exponent. It returns false
+// if the result overflows an int64.
+func intPow(base, exponent int64) (int64, bool) {
+	result := int64(1)
+	var ok bool
+	for {
+		if exponent&1 == 1 {
+			if result, ok = mulInt64(result, base); !ok {
+				return 0, false
+			}
+		}
+		exponent >>= 1
+		if exponent == 0 {
+			return result, true
+		}
+		if base, ok = mulInt64(base, base); !ok {
+			return 0, false
+		}
+	}
+}
+
+// mulInt64 returns the product of a and b. It returns false if the product
+// overflows an int64.
+func mulInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	product := a * b
+	if (product < 0) != ((a < 0) != (b < 0)) || product/b != a {
+		return 0, false
+	}
+	return product, true
+}
+
+func integerBitwise(operator string, fn func(a, b int64) int64) func(CallContext, ...RubyObject) (RubyObject, error) {
+	return func(context CallContext, args ...RubyObject) (RubyObject, error) {
+		i := context.Receiver().(*Integer)
+		right, ok := args[0].(*Integer)
+		if !ok {
+			result, coerced, err := coerceOperation(context, operator, args[0])
+			if coerced {
+				return result, err
+			}
+			return nil, NewCoercionTypeError(args[0], i)
+		}
+		return NewInteger(fn(i.Value, right.Value)), nil
+	}
+}
+
+func integerComplement(context CallContext, args ...RubyObject) (RubyObject, error) {
+	i := context.Receiver().(*Integer)
+	return NewInteger(^i.Value), nil
+}
+
+func integerLeftShift(context CallContext, args ...RubyObject) (RubyObject, error) {
+	i := context.Receiver().(*Integer)
+	count, ok := args[0].(*Integer)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(i, args[0])
+	}
+	result, ok := shiftLeft(i.Value, count.Value)
+	if !ok {
+		return nil, NewRangeError("%d << %d exceeds the Integer range", i.Value, count.Value)
+	}
+	return NewInteger(result), nil
+}
+
+func integerRightShift(context CallContext, args ...RubyObject) (RubyObject, error) {
+	i := context.Receiver().(*Integer)
+	count, ok := args[0].(*Integer)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(i, args[0])
+	}
+	result, ok := shiftLeft(i.Value, -count.Value)
+	if !ok {
+		return nil, NewRangeError("%d >> %d exceeds the Integer range", i.Value, count.Value)
+	}
+	return NewInteger(result), nil
+}
+
+// shiftLeft shifts value by count bits to the left, or to the right if count
+// is negative. It returns false if the result overflows an int64.
+func shiftLeft(value, count int64) (int64, bool) {
+	if count < 0 {
+		if count < -63 {
+			count = -63
+		}
+		return value >> uint64(-count), true
+	}
+	if value == 0 {
+		return 0, true
+	}
+	if count > 63 {
+		return 0, false
+	}
+	result := value << uint64(count)
+	if result>>uint64(count) != value {
+		return 0, false
+	}
+	return result, true
+}
+
+func integerBit(context CallContext, args ...RubyObject) (RubyObject, error) {
+	i := context.Receiver().(*Integer)
+	n, ok := args[0].(*Integer)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(i, args[0])
+	}
+	if n.Value < 0 {
+		return NewInteger(0), nil
+	}
+	bit, _ := shiftLeft(i.Value, -n.Value)
+	return NewInteger(bit & 1), nil
+}
+
+func integerBitLength(context CallContext, args ...RubyObject) (RubyObject, error) {
+	i := context.Receiver().(*Integer)
+	value := i.Value
+	if value < 0 {
+		value = ^value
+	}
+	return NewInteger(int64(bits.Len64(uint64(value)))), nil
+}
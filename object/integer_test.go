@@ -1,6 +1,7 @@
 package object
 
 import (
+	"math"
 	"reflect"
 	"testing"
 )
@@ -58,6 +59,35 @@ func TestIntegerDiv(t *testing.T) {
 	}
 }
 
+func TestIntegerFloorDiv(t *testing.T) {
+	tests := []struct {
+		receiver int64
+		argument RubyObject
+		result   RubyObject
+		err      error
+	}{
+		{7, NewInteger(2), NewInteger(3), nil},
+		{-7, NewInteger(2), NewInteger(-4), nil},
+		{7, NewInteger(-2), NewInteger(-4), nil},
+		{-8, NewInteger(2), NewInteger(-4), nil},
+		{7, NewFloat(2.0), NewInteger(3), nil},
+		{-7, NewFloat(2.0), NewInteger(-4), nil},
+		{7, NewInteger(0), nil, NewZeroDivisionError()},
+		{7, NewFloat(0), nil, NewZeroDivisionError()},
+		{7, &String{""}, nil, NewCoercionTypeError(&String{}, &Integer{})},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewInteger(testCase.receiver)}
+
+		result, err := integerFloorDiv(context, testCase.argument)
+
+		checkError(t, err, testCase.err)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
 func TestIntegerMul(t *testing.T) {
 	tests := []struct {
 		arguments []RubyObject
@@ -98,6 +128,11 @@ func TestIntegerAdd(t *testing.T) {
 			NewInteger(4),
 			nil,
 		},
+		{
+			[]RubyObject{NewFloat(0.5)},
+			NewFloat(2.5),
+			nil,
+		},
 		{
 			[]RubyObject{&String{""}},
 			nil,
@@ -276,6 +311,45 @@ func TestIntegerEq(t *testing.T) {
 	}
 }
 
+func TestIntegerCaseEqual(t *testing.T) {
+	tests := []struct {
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{
+			[]RubyObject{NewInteger(6)},
+			FALSE,
+			nil,
+		},
+		{
+			[]RubyObject{NewInteger(4)},
+			TRUE,
+			nil,
+		},
+		{
+			[]RubyObject{NewFloat(4)},
+			TRUE,
+			nil,
+		},
+		{
+			[]RubyObject{&String{""}},
+			FALSE,
+			nil,
+		},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewInteger(4), env: NewEnvironment()}
+
+		result, err := integerCaseEqual(context, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
 func TestIntegerNeq(t *testing.T) {
 	tests := []struct {
 		arguments []RubyObject
@@ -427,6 +501,40 @@ func TestIntegerSpaceship(t *testing.T) {
 	}
 }
 
+func TestIntegerFdiv(t *testing.T) {
+	tests := []struct {
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{
+			[]RubyObject{NewInteger(2)},
+			NewFloat(1.5),
+			nil,
+		},
+		{
+			[]RubyObject{NewFloat(0.5)},
+			NewFloat(6),
+			nil,
+		},
+		{
+			[]RubyObject{&String{""}},
+			nil,
+			NewCoercionTypeError(&String{}, &Integer{}),
+		},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewInteger(3)}
+
+		result, err := integerFdiv(context, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
 func checkError(t *testing.T, actual, expected error) {
 	t.Helper()
 	if !reflect.DeepEqual(expected, actual) {
@@ -449,3 +557,84 @@ func toString(obj RubyObject) string {
 	}
 	return obj.Inspect()
 }
+
+func TestIntegerPow(t *testing.T) {
+	tests := []struct {
+		receiver  int64
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{2, []RubyObject{NewInteger(10)}, NewInteger(1024), nil},
+		{-3, []RubyObject{NewInteger(3)}, NewInteger(-27), nil},
+		{2, []RubyObject{NewInteger(-1)}, NewFloat(0.5), nil},
+		{0, []RubyObject{NewInteger(-1)}, nil, NewZeroDivisionError()},
+		{4, []RubyObject{NewFloat(0.5)}, NewFloat(2), nil},
+		{2, []RubyObject{NewInteger(10), NewInteger(1000)}, NewInteger(24), nil},
+		{2, []RubyObject{NewInteger(10), NewInteger(-7)}, NewInteger(-5), nil},
+		{-2, []RubyObject{NewInteger(3), NewInteger(5)}, NewInteger(2), nil},
+		{2, []RubyObject{NewInteger(3), NewInteger(0)}, nil, NewZeroDivisionError()},
+		{2, []RubyObject{NewInteger(-3), NewInteger(5)}, nil, NewRangeError("Integer#pow() 1st argument cannot be negative when 2nd argument specified")},
+		{2, []RubyObject{}, nil, NewWrongNumberOfArgumentsRangeError(1, 2, false, 0)},
+		{2, []RubyObject{NewInteger(62)}, NewInteger(1 << 62), nil},
+		{-2, []RubyObject{NewInteger(63)}, NewInteger(math.MinInt64), nil},
+		{2, []RubyObject{NewInteger(63)}, nil, NewRangeError("2 ** 63 exceeds the Integer range")},
+		{2, []RubyObject{NewInteger(64)}, nil, NewRangeError("2 ** 64 exceeds the Integer range")},
+		{-3, []RubyObject{NewInteger(41)}, nil, NewRangeError("-3 ** 41 exceeds the Integer range")},
+		{1, []RubyObject{NewInteger(1 << 40)}, NewInteger(1), nil},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewInteger(testCase.receiver)}
+
+		result, err := integerModPow(context, testCase.arguments...)
+
+		checkError(t, err, testCase.err)
+
+		checkResult(t, result, testCase.result)
+	}
+}
+
+func TestIntegerBitOperations(t *testing.T) {
+	tests := []struct {
+		method   func(CallContext, ...RubyObject) (RubyObject, error)
+		receiver int64
+		argument RubyObject
+		result   RubyObject
+		err      error
+	}{
+		{integerBitwise("&", func(a, b int64) int64 { return a & b }), 6, NewInteger(3), NewInteger(2), nil},
+		{integerComplement, 5, nil, NewInteger(-6), nil},
+		{integerLeftShift, 1, NewInteger(4), NewInteger(16), nil},
+		{integerLeftShift, 16, NewInteger(-2), NewInteger(4), nil},
+		{integerLeftShift, 1, NewInteger(64), nil, NewRangeError("1 << 64 exceeds the Integer range")},
+		{integerLeftShift, 1, NewInteger(63), nil, NewRangeError("1 << 63 exceeds the Integer range")},
+		{integerLeftShift, -1, NewInteger(63), NewInteger(math.MinInt64), nil},
+		{integerLeftShift, 0, NewInteger(100), NewInteger(0), nil},
+		{integerRightShift, 1, NewInteger(-64), nil, NewRangeError("1 >> -64 exceeds the Integer range")},
+		{integerRightShift, -256, NewInteger(4), NewInteger(-16), nil},
+		{integerRightShift, -1, NewInteger(100), NewInteger(-1), nil},
+		{integerRightShift, 1, &String{}, nil, NewImplicitConversionTypeError(&Integer{}, &String{})},
+		{integerBit, 5, NewInteger(0), NewInteger(1), nil},
+		{integerBit, 5, NewInteger(1), NewInteger(0), nil},
+		{integerBit, -1, NewInteger(100), NewInteger(1), nil},
+		{integerBit, 5, NewInteger(-1), NewInteger(0), nil},
+		{integerBitLength, 255, nil, NewInteger(8), nil},
+		{integerBitLength, -256, nil, NewInteger(8), nil},
+		{integerBitLength, 0, nil, NewInteger(0), nil},
+	}
+
+	for _, testCase := range tests {
+		context := &callContext{receiver: NewInteger(testCase.receiver)}
+
+		var args []RubyObject
+		if testCase.argument != nil {
+			args = append(args, testCase.argument)
+		}
+		result, err := testCase.method(context, args...)
+
+		checkError(t, err, testCase.err)
+
+		checkResult(t, result, testCase.result)
+	}
+}
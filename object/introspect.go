@@ -2,9 +2,17 @@ package object
 
 func getMethods(class RubyClass, visibility MethodVisibility, addSuperMethods bool) *Array {
 	var methodSymbols []RubyObject
+	undefined := make(map[string]bool)
 	for class != nil {
 		methods := class.Methods().GetAll()
 		for meth, fn := range methods {
+			if isUndefined(fn) {
+				undefined[meth] = true
+				continue
+			}
+			if undefined[meth] {
+				continue
+			}
 			if fn.Visibility() == visibility {
 				methodSymbols = append(methodSymbols, &Symbol{meth})
 			}
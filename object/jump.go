@@ -0,0 +1,89 @@
+package object
+
+import (
+	"github.com/pkg/errors"
+)
+
+// A BreakError is the signal emitted by a break. It is no real Ruby error and
+// unwinds the evaluation up to the enclosing loop, or, if issued within a
+// block, up to the method call the block was passed to.
+type BreakError struct {
+	Value RubyObject
+	// Block is the block the break was issued in, or nil if it was not
+	// issued within a block
+	Block *Proc
+}
+
+func (e *BreakError) Error() string { return "break from proc-closure" }
+
+// A NextError is the signal emitted by a next. It unwinds the evaluation up
+// to the enclosing loop or block, which uses Value as result of the current
+// iteration.
+type NextError struct {
+	Value RubyObject
+}
+
+func (e *NextError) Error() string { return "unexpected next" }
+
+// A RedoError is the signal emitted by a redo. It unwinds the evaluation up
+// to the enclosing loop or block, which restarts the current iteration.
+type RedoError struct{}
+
+func (e *RedoError) Error() string { return "unexpected redo" }
+
+// A RetryError is the signal emitted by a retry. It unwinds the evaluation up
+// to the enclosing begin block, which reevaluates its body.
+type RetryError struct{}
+
+func (e *RetryError) Error() string { return "unexpected retry" }
+
+// A ReturnError is the signal emitted by a return within a proc. It unwinds
+// the evaluation up to the method call the proc was created in.
+type ReturnError struct {
+	Value RubyObject
+	// Frame is self of the method call the proc was created in
+	Frame *Self
+}
+
+func (e *ReturnError) Error() string { return "unexpected return" }
+
+// IsJump reports whether the cause of err is a break, next, redo, retry or
+// return signal
+func IsJump(err error) bool {
+	switch errors.Cause(err).(type) {
+	case *BreakError, *NextError, *RedoError, *RetryError, *ReturnError:
+		return true
+	default:
+		return false
+	}
+}
+
+// LocalJump converts a break, next, redo, retry or return signal which escaped
+// its valid context into a LocalJumpError. Breaks and returns issued within a
+// block are left untouched unless escaped is true, as they are still on their
+// way to the method call they belong to. Any other error is returned unchanged.
+func LocalJump(err error, escaped bool) error {
+	switch jump := errors.Cause(err).(type) {
+	case *BreakError:
+		if jump.Block != nil && !escaped {
+			return err
+		}
+		if jump.Block == nil {
+			return errors.WithStack(NewLocalJumpError("Invalid break"))
+		}
+		return errors.WithStack(NewLocalJumpError("break from proc-closure"))
+	case *NextError:
+		return errors.WithStack(NewLocalJumpError("Invalid next"))
+	case *RedoError:
+		return errors.WithStack(NewLocalJumpError("Invalid redo"))
+	case *RetryError:
+		return errors.WithStack(NewLocalJumpError("Invalid retry"))
+	case *ReturnError:
+		if !escaped {
+			return err
+		}
+		return errors.WithStack(NewLocalJumpError("unexpected return"))
+	default:
+		return err
+	}
+}
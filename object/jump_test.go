@@ -0,0 +1,34 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestLocalJump(t *testing.T) {
+	block := &Proc{}
+	tests := []struct {
+		err      error
+		escaped  bool
+		expected error
+	}{
+		{&BreakError{Value: NIL}, false, NewLocalJumpError("Invalid break")},
+		{&BreakError{Value: NIL, Block: block}, true, NewLocalJumpError("break from proc-closure")},
+		{&NextError{Value: NIL}, false, NewLocalJumpError("Invalid next")},
+		{&RedoError{}, true, NewLocalJumpError("Invalid redo")},
+		{NewNoMethodError(NIL, "foo"), true, NewNoMethodError(NIL, "foo")},
+	}
+
+	for _, tt := range tests {
+		err := LocalJump(errors.WithStack(tt.err), tt.escaped)
+
+		checkError(t, errors.Cause(err), tt.expected)
+	}
+
+	tagged := &BreakError{Value: NIL, Block: block}
+	if err := LocalJump(tagged, false); err != tagged {
+		t.Logf("Expected tagged break to pass unchanged, got %v\n", err)
+		t.Fail()
+	}
+}
@@ -22,6 +22,7 @@ func init() {
 var kernelMethodSet = map[string]RubyMethod{
 	"to_s":              withArity(0, publicMethod(kernelToS)),
 	"nil?":              withArity(0, publicMethod(kernelIsNil)),
+	"method":            withArity(1, publicMethod(kernelMethod)),
 	"methods":           publicMethod(kernelMethods),
 	"public_methods":    publicMethod(kernelPublicMethods),
 	"protected_methods": publicMethod(kernelProtectedMethods),
@@ -31,8 +32,35 @@ var kernelMethodSet = map[string]RubyMethod{
 	"require":           withArity(1, privateMethod(kernelRequire)),
 	"extend":            publicMethod(kernelExtend),
 	"block_given?":      withArity(0, privateMethod(kernelBlockGiven)),
+	"__method__":        withArity(0, privateMethod(kernelMethodName)),
 	"tap":               publicMethod(kernelTap),
+	"proc":              privateMethod(kernelProc),
+	"lambda":            privateMethod(kernelLambda),
 	"raise":             privateMethod(kernelRaise),
+	"!~":                withArity(1, publicMethod(kernelNotMatch)),
+	"===":               withArity(1, publicMethod(kernelCaseEqual)),
+}
+
+func kernelCaseEqual(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if unwrapSelf(context.Receiver()) == unwrapSelf(args[0]) {
+		return TRUE, nil
+	}
+	eq, err := Send(context, "==", args...)
+	if err != nil {
+		return nil, err
+	}
+	return nativeBoolToBoolean(eq != NIL && eq != FALSE), nil
+}
+
+func kernelNotMatch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	result, err := Send(context, "=~", args...)
+	if err != nil {
+		return nil, err
+	}
+	if result == NIL || result == FALSE {
+		return TRUE, nil
+	}
+	return FALSE, nil
 }
 
 func kernelToS(context CallContext, args ...RubyObject) (RubyObject, error) {
@@ -53,6 +81,19 @@ func kernelPuts(context CallContext, args ...RubyObject) (RubyObject, error) {
 	return NIL, nil
 }
 
+func kernelMethod(context CallContext, args ...RubyObject) (RubyObject, error) {
+	name, err := methodName(args[0])
+	if err != nil {
+		return nil, err
+	}
+	receiver := context.Receiver()
+	fn, ok := findMethod(receiver.Class(), name)
+	if !ok {
+		return nil, NewUndefinedMethodNameError(unwrapSelf(receiver), name)
+	}
+	return &Method{Name: name, Receiver: receiver, fn: fn}, nil
+}
+
 func kernelMethods(context CallContext, args ...RubyObject) (RubyObject, error) {
 	showInstanceMethods := true
 	if len(args) == 1 {
@@ -227,6 +268,14 @@ func kernelBlockGiven(context CallContext, args ...RubyObject) (RubyObject, erro
 	return TRUE, nil
 }
 
+func kernelMethodName(context CallContext, args ...RubyObject) (RubyObject, error) {
+	self, _ := context.Receiver().(*Self)
+	if self == nil || self.Method == nil {
+		return NIL, nil
+	}
+	return &Symbol{Value: self.Method.Name}, nil
+}
+
 func kernelTap(context CallContext, args ...RubyObject) (RubyObject, error) {
 	block, remainingArgs, ok := extractBlockFromArgs(args)
 	if !ok {
@@ -242,6 +291,30 @@ func kernelTap(context CallContext, args ...RubyObject) (RubyObject, error) {
 	return context.Receiver(), nil
 }
 
+func kernelProc(context CallContext, args ...RubyObject) (RubyObject, error) {
+	block, remainingArgs, ok := extractBlockFromArgs(args)
+	if len(remainingArgs) != 0 {
+		return nil, NewWrongNumberOfArgumentsError(0, len(remainingArgs))
+	}
+	if !ok {
+		return nil, NewArgumentError("tried to create Proc object without a block")
+	}
+	return block, nil
+}
+
+func kernelLambda(context CallContext, args ...RubyObject) (RubyObject, error) {
+	block, remainingArgs, ok := extractBlockFromArgs(args)
+	if len(remainingArgs) != 0 {
+		return nil, NewWrongNumberOfArgumentsError(0, len(remainingArgs))
+	}
+	if !ok {
+		return nil, NewArgumentError("tried to create lambda object without a block")
+	}
+	lambda := *block
+	lambda.ArgumentCountMandatory = true
+	return &lambda, nil
+}
+
 func kernelRaise(context CallContext, args ...RubyObject) (RubyObject, error) {
 	switch len(args) {
 	case 1:
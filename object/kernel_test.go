@@ -913,6 +913,26 @@ func TestKernelBlockGiven(t *testing.T) {
 	})
 }
 
+func TestKernelMethodName(t *testing.T) {
+	tests := []struct {
+		receiver RubyObject
+		result   RubyObject
+	}{
+		{&Self{RubyObject: &Object{}, Method: &Function{Name: "foo"}, Name: "foo"}, &Symbol{Value: "foo"}},
+		{&Self{RubyObject: &Object{}, Name: "main"}, NIL},
+	}
+
+	for _, tt := range tests {
+		context := &callContext{receiver: tt.receiver, env: NewEnvironment()}
+
+		result, err := kernelMethodName(context)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, tt.result)
+	}
+}
+
 func TestKernelTap(t *testing.T) {
 	t.Run("with block", func(t *testing.T) {
 		object := &Object{}
@@ -932,7 +952,7 @@ func TestKernelTap(t *testing.T) {
 			Env:        NewEnvironment(),
 		}
 
-		result, err := kernelTap(context, block)
+		result, err := kernelTap(context, NewBlockArgument(block))
 
 		checkError(t, err, nil)
 
@@ -956,7 +976,7 @@ func TestKernelTap(t *testing.T) {
 			Env:        NewEnvironment(),
 		}
 
-		_, err := kernelTap(context, NIL, block)
+		_, err := kernelTap(context, NIL, NewBlockArgument(block))
 
 		expected := NewWrongNumberOfArgumentsError(0, 1)
 
@@ -998,7 +1018,7 @@ func TestKernelTap(t *testing.T) {
 			Env:        NewEnvironment(),
 		}
 
-		_, err := kernelTap(context, block)
+		_, err := kernelTap(context, NewBlockArgument(block))
 
 		expected := NewException("An error")
 
@@ -1096,3 +1116,62 @@ func TestKernelRaise(t *testing.T) {
 		})
 	})
 }
+
+func TestKernelCaseEqual(t *testing.T) {
+	object := &Object{}
+	tests := []struct {
+		receiver RubyObject
+		argument RubyObject
+		result   RubyObject
+	}{
+		{object, object, TRUE},
+		{object, &Object{}, FALSE},
+		{&String{Value: "a"}, &String{Value: "a"}, TRUE},
+		{NewInteger(1), NewFloat(1), TRUE},
+		{&Symbol{Value: "a"}, &String{Value: "a"}, FALSE},
+	}
+
+	for _, tt := range tests {
+		context := &callContext{receiver: tt.receiver}
+
+		result, err := kernelCaseEqual(context, tt.argument)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, tt.result)
+	}
+}
+
+func TestKernelMethod(t *testing.T) {
+	t.Run("existing method", func(t *testing.T) {
+		context := &callContext{receiver: NewInteger(5)}
+
+		result, err := kernelMethod(context, &Symbol{Value: "to_s"})
+
+		checkError(t, err, nil)
+
+		method, ok := result.(*Method)
+		if !ok {
+			t.Logf("Expected result to be a Method, got %T", result)
+			t.FailNow()
+		}
+		if method.Name != "to_s" || method.Receiver != context.receiver {
+			t.Logf("Expected method to_s bound to 5, got %s bound to %s", method.Name, method.Receiver.Inspect())
+			t.Fail()
+		}
+	})
+	t.Run("unknown method", func(t *testing.T) {
+		context := &callContext{receiver: NewInteger(5)}
+
+		_, err := kernelMethod(context, &String{Value: "foo"})
+
+		checkError(t, err, NewUndefinedMethodNameError(NewInteger(5), "foo"))
+	})
+	t.Run("no symbol nor string", func(t *testing.T) {
+		context := &callContext{receiver: NewInteger(5)}
+
+		_, err := kernelMethod(context, NewInteger(1))
+
+		checkError(t, err, NewTypeError("1 is not a symbol nor a string"))
+	})
+}
@@ -0,0 +1,227 @@
+package object
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+var matchDataClass RubyClassObject = newClass(
+	"MatchData", objectClass, matchDataMethods, matchDataClassMethods, notInstantiatable,
+)
+
+func init() {
+	classes.Set("MatchData", matchDataClass)
+}
+
+// MatchData represents the result of matching a Regexp against a String
+type MatchData struct {
+	Regexp  *Regexp
+	Subject string
+	// Offsets holds the byte offsets of the match and its groups as pairs
+	// of start and end, with -1 for groups which did not participate
+	Offsets []int
+}
+
+// Inspect returns the match and its groups
+func (m *MatchData) Inspect() string {
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("#<MatchData %q", m.group(0)))
+	names := m.Regexp.Regexp.SubexpNames()
+	for i := 1; i < m.size(); i++ {
+		name := names[i]
+		if name == "" {
+			name = fmt.Sprintf("%d", i)
+		}
+		value := "nil"
+		if m.Offsets[2*i] >= 0 {
+			value = fmt.Sprintf("%q", m.group(i))
+		}
+		out.WriteString(fmt.Sprintf(" %s:%s", name, value))
+	}
+	out.WriteString(">")
+	return out.String()
+}
+
+// Type returns MATCH_DATA_OBJ
+func (m *MatchData) Type() Type { return MATCH_DATA_OBJ }
+
+// Class returns matchDataClass
+func (m *MatchData) Class() RubyClass { return matchDataClass }
+
+// size returns the number of groups including the whole match
+func (m *MatchData) size() int { return len(m.Offsets) / 2 }
+
+// group returns the text of the group i. It returns an empty string if the
+// group did not participate in the match.
+func (m *MatchData) group(i int) string {
+	if m.Offsets[2*i] < 0 {
+		return ""
+	}
+	return m.Subject[m.Offsets[2*i]:m.Offsets[2*i+1]]
+}
+
+// Group returns the group i as String, or NIL if it is out of range or did
+// not participate in the match
+func (m *MatchData) Group(i int) RubyObject {
+	if i < 0 {
+		i += m.size()
+	}
+	if i < 0 || i >= m.size() || m.Offsets[2*i] < 0 {
+		return NIL
+	}
+	return &String{Value: m.group(i)}
+}
+
+// begin returns the character offset of the start of group i
+func (m *MatchData) begin(i int) int {
+	return utf8.RuneCountInString(m.Subject[:m.Offsets[2*i]])
+}
+
+// end returns the character offset of the end of group i
+func (m *MatchData) end(i int) int {
+	return utf8.RuneCountInString(m.Subject[:m.Offsets[2*i+1]])
+}
+
+// groupIndex returns the index of the group identified by obj, which is either
+// an Integer or the name of a named group
+func (m *MatchData) groupIndex(obj RubyObject) (int, error) {
+	var name string
+	switch obj := obj.(type) {
+	case *Integer:
+		return int(obj.Value), nil
+	case *String:
+		name = obj.Value
+	case *Symbol:
+		name = obj.Value
+	default:
+		return 0, NewImplicitConversionTypeError(&Integer{}, obj)
+	}
+	index := m.Regexp.Regexp.SubexpIndex(name)
+	if index < 0 {
+		return 0, NewIndexError("undefined group name reference: %s", name)
+	}
+	return index, nil
+}
+
+var matchDataClassMethods = map[string]RubyMethod{}
+
+var matchDataMethods = map[string]RubyMethod{
+	"[]":             withArity(1, publicMethod(matchDataIndex)),
+	"to_a":           withArity(0, publicMethod(matchDataToA)),
+	"captures":       withArity(0, publicMethod(matchDataCaptures)),
+	"named_captures": withArity(0, publicMethod(matchDataNamedCaptures)),
+	"names":          withArity(0, publicMethod(matchDataNames)),
+	"pre_match":      withArity(0, publicMethod(matchDataPreMatch)),
+	"post_match":     withArity(0, publicMethod(matchDataPostMatch)),
+	"begin":          withArity(1, publicMethod(matchDataBegin)),
+	"end":            withArity(1, publicMethod(matchDataEnd)),
+	"size":           withArity(0, publicMethod(matchDataSize)),
+	"length":         withArity(0, publicMethod(matchDataSize)),
+	"string":         withArity(0, publicMethod(matchDataString)),
+	"regexp":         withArity(0, publicMethod(matchDataRegexp)),
+	"to_s":           withArity(0, publicMethod(matchDataToS)),
+}
+
+func matchDataIndex(context CallContext, args ...RubyObject) (RubyObject, error) {
+	m := context.Receiver().(*MatchData)
+	index, err := m.groupIndex(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return m.Group(index), nil
+}
+
+func matchDataToA(context CallContext, args ...RubyObject) (RubyObject, error) {
+	m := context.Receiver().(*MatchData)
+	groups := make([]RubyObject, m.size())
+	for i := range groups {
+		groups[i] = m.Group(i)
+	}
+	return NewArray(groups...), nil
+}
+
+func matchDataCaptures(context CallContext, args ...RubyObject) (RubyObject, error) {
+	m := context.Receiver().(*MatchData)
+	groups := make([]RubyObject, m.size()-1)
+	for i := range groups {
+		groups[i] = m.Group(i + 1)
+	}
+	return NewArray(groups...), nil
+}
+
+func matchDataNamedCaptures(context CallContext, args ...RubyObject) (RubyObject, error) {
+	m := context.Receiver().(*MatchData)
+	captures := &Hash{}
+	for i, name := range m.Regexp.Regexp.SubexpNames() {
+		if name != "" {
+			captures.Set(&String{Value: name}, m.Group(i))
+		}
+	}
+	return captures, nil
+}
+
+func matchDataNames(context CallContext, args ...RubyObject) (RubyObject, error) {
+	m := context.Receiver().(*MatchData)
+	return regexpNames(NewCallContext(context.Env(), m.Regexp))
+}
+
+func matchDataPreMatch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	m := context.Receiver().(*MatchData)
+	return &String{Value: m.Subject[:m.Offsets[0]]}, nil
+}
+
+func matchDataPostMatch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	m := context.Receiver().(*MatchData)
+	return &String{Value: m.Subject[m.Offsets[1]:]}, nil
+}
+
+func matchDataBegin(context CallContext, args ...RubyObject) (RubyObject, error) {
+	m := context.Receiver().(*MatchData)
+	index, err := m.groupIndex(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= m.size() {
+		return nil, NewIndexError("index %d out of matches", index)
+	}
+	if m.Offsets[2*index] < 0 {
+		return NIL, nil
+	}
+	return NewInteger(int64(m.begin(index))), nil
+}
+
+func matchDataEnd(context CallContext, args ...RubyObject) (RubyObject, error) {
+	m := context.Receiver().(*MatchData)
+	index, err := m.groupIndex(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= m.size() {
+		return nil, NewIndexError("index %d out of matches", index)
+	}
+	if m.Offsets[2*index] < 0 {
+		return NIL, nil
+	}
+	return NewInteger(int64(m.end(index))), nil
+}
+
+func matchDataSize(context CallContext, args ...RubyObject) (RubyObject, error) {
+	m := context.Receiver().(*MatchData)
+	return NewInteger(int64(m.size())), nil
+}
+
+func matchDataString(context CallContext, args ...RubyObject) (RubyObject, error) {
+	m := context.Receiver().(*MatchData)
+	return &String{Value: m.Subject}, nil
+}
+
+func matchDataRegexp(context CallContext, args ...RubyObject) (RubyObject, error) {
+	m := context.Receiver().(*MatchData)
+	return m.Regexp, nil
+}
+
+func matchDataToS(context CallContext, args ...RubyObject) (RubyObject, error) {
+	m := context.Receiver().(*MatchData)
+	return &String{Value: m.group(0)}, nil
+}
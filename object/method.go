@@ -0,0 +1,70 @@
+package object
+
+import "fmt"
+
+var methodClass RubyClassObject = newClass(
+	"Method", objectClass, methodMethods, methodClassMethods, notInstantiatable,
+)
+
+func init() {
+	classes.Set("Method", methodClass)
+}
+
+// A Method represents a method bound to its receiver, as returned by
+// Kernel#method
+type Method struct {
+	Name     string
+	Receiver RubyObject
+	fn       RubyMethod
+}
+
+// Inspect returns the class and the name of the method
+func (m *Method) Inspect() string {
+	return fmt.Sprintf("#<Method: %s#%s>", unwrapSelf(m.Receiver).Class().Name(), m.Name)
+}
+
+// Type returns METHOD_OBJ
+func (m *Method) Type() Type { return METHOD_OBJ }
+
+// Class returns methodClass
+func (m *Method) Class() RubyClass { return methodClass }
+
+// Call calls the method on its receiver with args
+func (m *Method) Call(context CallContext, args ...RubyObject) (RubyObject, error) {
+	receiverContext := &callContext{
+		receiver: m.Receiver,
+		env:      context.Env(),
+		eval:     context.Eval,
+	}
+	return m.fn.Call(receiverContext, args...)
+}
+
+var methodClassMethods = map[string]RubyMethod{}
+
+var methodMethods = map[string]RubyMethod{
+	"call":     publicMethod(methodCall),
+	"[]":       publicMethod(methodCall),
+	"===":      publicMethod(methodCall),
+	"to_proc":  withArity(0, publicMethod(methodToProc)),
+	"name":     withArity(0, publicMethod(methodNameSymbol)),
+	"receiver": withArity(0, publicMethod(methodReceiver)),
+}
+
+func methodCall(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver().(*Method).Call(context, args...)
+}
+
+func methodToProc(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return &Proc{
+		ArgumentCountMandatory: true,
+		native:                 context.Receiver().(*Method).Call,
+	}, nil
+}
+
+func methodNameSymbol(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return &Symbol{Value: context.Receiver().(*Method).Name}, nil
+}
+
+func methodReceiver(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return unwrapSelf(context.Receiver().(*Method).Receiver), nil
+}
@@ -72,6 +72,28 @@ type SettableMethodSet interface {
 	// Set will set method to key name. If there was a method prior defined
 	// under name it will be overridden.
 	Set(name string, method RubyMethod)
+	// Undefine marks name as undefined. Get will return a tombstone for name,
+	// which stops the method lookup from searching the ancestors.
+	Undefine(name string)
+	// Remove deletes the method defined under name. It returns false if there
+	// was no method defined under name.
+	Remove(name string) bool
+}
+
+// tombstone is the method stored for undefined method names
+type tombstone struct {
+	name string
+}
+
+func (t *tombstone) Call(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return nil, NewNoMethodError(context.Receiver(), t.name)
+}
+func (t *tombstone) Visibility() MethodVisibility { return PUBLIC_METHOD }
+
+// isUndefined reports whether method marks an undefined method name
+func isUndefined(method RubyMethod) bool {
+	_, ok := method.(*tombstone)
+	return ok
 }
 
 // NewMethodSet returns a new method set populated with the given methods
@@ -99,3 +121,16 @@ func (m *methodSet) Get(name string) (RubyMethod, bool) {
 func (m *methodSet) Set(name string, method RubyMethod) {
 	m.methods[name] = method
 }
+
+func (m *methodSet) Undefine(name string) {
+	m.methods[name] = &tombstone{name: name}
+}
+
+func (m *methodSet) Remove(name string) bool {
+	method, ok := m.methods[name]
+	if !ok || isUndefined(method) {
+		return false
+	}
+	delete(m.methods, name)
+	return true
+}
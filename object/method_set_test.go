@@ -46,3 +46,64 @@ func TestWithArity(t *testing.T) {
 		checkError(t, err, testCase.err)
 	}
 }
+
+func TestMethodSetUndefine(t *testing.T) {
+	set := NewMethodSet(map[string]RubyMethod{
+		"foo": publicMethod(func(context CallContext, args ...RubyObject) (RubyObject, error) {
+			return TRUE, nil
+		}),
+	})
+
+	set.Undefine("foo")
+	set.Undefine("bar")
+
+	for _, name := range []string{"foo", "bar"} {
+		method, ok := set.Get(name)
+		if !ok {
+			t.Logf("Expected method set to contain %q", name)
+			t.Fail()
+			continue
+		}
+		if !isUndefined(method) {
+			t.Logf("Expected %q to be undefined, got %T", name, method)
+			t.Fail()
+		}
+	}
+}
+
+func TestMethodSetRemove(t *testing.T) {
+	set := NewMethodSet(map[string]RubyMethod{
+		"foo": publicMethod(func(context CallContext, args ...RubyObject) (RubyObject, error) {
+			return TRUE, nil
+		}),
+		"bar": &tombstone{name: "bar"},
+	})
+
+	tests := []struct {
+		name    string
+		removed bool
+	}{
+		{"foo", true},
+		{"foo", false},
+		{"bar", false},
+		{"qux", false},
+	}
+
+	for _, tt := range tests {
+		removed := set.Remove(tt.name)
+
+		if removed != tt.removed {
+			t.Logf("Expected Remove(%q) to return %t, got %t", tt.name, tt.removed, removed)
+			t.Fail()
+		}
+	}
+
+	if _, ok := set.Get("foo"); ok {
+		t.Logf("Expected foo to be removed")
+		t.Fail()
+	}
+	if _, ok := set.Get("bar"); !ok {
+		t.Logf("Expected tombstone of bar to be kept")
+		t.Fail()
+	}
+}
@@ -0,0 +1,49 @@
+package object
+
+import "testing"
+
+func TestMethodCall(t *testing.T) {
+	method := &Method{Name: "+", Receiver: NewInteger(5), fn: integerMethods["+"]}
+	context := &callContext{receiver: method, env: NewEnvironment()}
+
+	result, err := methodCall(context, NewInteger(3))
+
+	checkError(t, err, nil)
+
+	checkResult(t, result, NewInteger(8))
+}
+
+func TestMethodToProc(t *testing.T) {
+	method := &Method{Name: "-", Receiver: NewInteger(5), fn: integerMethods["-"]}
+	context := &callContext{receiver: method, env: NewEnvironment()}
+
+	result, err := methodToProc(context)
+
+	checkError(t, err, nil)
+
+	proc, ok := result.(*Proc)
+	if !ok {
+		t.Logf("Expected result to be a Proc, got %T", result)
+		t.FailNow()
+	}
+
+	if !proc.ArgumentCountMandatory {
+		t.Logf("Expected proc to be a lambda")
+		t.Fail()
+	}
+
+	result, err = proc.Call(context, NewInteger(2))
+
+	checkError(t, err, nil)
+
+	checkResult(t, result, NewInteger(3))
+}
+
+func TestMethodInspect(t *testing.T) {
+	method := &Method{Name: "to_s", Receiver: NewInteger(5)}
+
+	if method.Inspect() != "#<Method: Integer#to_s>" {
+		t.Logf("Expected inspect to equal %q, got %q", "#<Method: Integer#to_s>", method.Inspect())
+		t.Fail()
+	}
+}
@@ -67,6 +67,7 @@ func (m *Module) hashKey() hashKey {
 func (m *Module) addMethod(name string, method RubyMethod) {
 	m.class.addMethod(name, method)
 }
+func (m *Module) methodClass() RubyClass { return m.class }
 
 var moduleMethods = map[string]RubyMethod{
 	"ancestors":                  withArity(0, publicMethod(moduleAncestors)),
@@ -79,6 +80,10 @@ var moduleMethods = map[string]RubyMethod{
 	"append_features":            withArity(1, privateMethod(moduleAppendFeatures)),
 	"to_s":                       withArity(0, publicMethod(moduleToS)),
 	"inspect":                    withArity(0, publicMethod(moduleToS)),
+	"===":                        withArity(1, publicMethod(moduleCaseEqual)),
+	"alias_method":               withArity(2, publicMethod(moduleAliasMethod)),
+	"undef_method":               publicMethod(moduleUndefMethod),
+	"remove_method":              publicMethod(moduleRemoveMethod),
 }
 
 func moduleToS(context CallContext, args ...RubyObject) (RubyObject, error) {
@@ -93,6 +98,84 @@ func moduleToS(context CallContext, args ...RubyObject) (RubyObject, error) {
 	return &String{Value: val}, nil
 }
 
+func moduleCaseEqual(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return nativeBoolToBoolean(isKindOf(unwrapSelf(args[0]), unwrapSelf(context.Receiver()))), nil
+}
+
+// isKindOf reports whether module is the class of obj, one of its
+// superclasses or a module included in one of them
+func isKindOf(obj, module RubyObject) bool {
+	for class := obj.Class(); class != nil; class = class.SuperClass() {
+		if c, ok := class.(RubyClassObject); ok && RubyObject(c) == module {
+			return true
+		}
+		if mixin, ok := class.(*mixin); ok {
+			if RubyObject(mixin.RubyClassObject) == module {
+				return true
+			}
+			for _, m := range mixin.modules {
+				if RubyObject(m) == module {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func moduleAliasMethod(context CallContext, args ...RubyObject) (RubyObject, error) {
+	newName, err := methodName(args[0])
+	if err != nil {
+		return nil, err
+	}
+	oldName, err := methodName(args[1])
+	if err != nil {
+		return nil, err
+	}
+	if _, err := AliasMethod(context.Receiver(), newName, oldName); err != nil {
+		return nil, err
+	}
+	return &Symbol{Value: newName}, nil
+}
+
+func moduleUndefMethod(context CallContext, args ...RubyObject) (RubyObject, error) {
+	for _, arg := range args {
+		name, err := methodName(arg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := UndefineMethod(context.Receiver(), name); err != nil {
+			return nil, err
+		}
+	}
+	return context.Receiver(), nil
+}
+
+func moduleRemoveMethod(context CallContext, args ...RubyObject) (RubyObject, error) {
+	for _, arg := range args {
+		name, err := methodName(arg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := RemoveMethod(context.Receiver(), name); err != nil {
+			return nil, err
+		}
+	}
+	return context.Receiver(), nil
+}
+
+// methodName returns the method name represented by the symbol or string arg
+func methodName(arg RubyObject) (string, error) {
+	switch arg := arg.(type) {
+	case *Symbol:
+		return arg.Value, nil
+	case *String:
+		return arg.Value, nil
+	default:
+		return "", NewTypeError(fmt.Sprintf("%s is not a symbol nor a string", arg.Inspect()))
+	}
+}
+
 func moduleAncestors(context CallContext, args ...RubyObject) (RubyObject, error) {
 	class := context.Receiver().(RubyClassObject)
 	var ancestors []RubyObject
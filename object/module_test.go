@@ -6,6 +6,8 @@ import (
 	"sort"
 	"strings"
 	"testing"
+
+	"github.com/pkg/errors"
 )
 
 func TestModule_hashKey(t *testing.T) {
@@ -703,3 +705,139 @@ func TestModuleInclude(t *testing.T) {
 		}
 	})
 }
+
+func TestModuleCaseEqual(t *testing.T) {
+	tests := []struct {
+		receiver RubyObject
+		argument RubyObject
+		result   RubyObject
+	}{
+		{integerClass, NewInteger(1), TRUE},
+		{objectClass, NewInteger(1), TRUE},
+		{basicObjectClass, &String{}, TRUE},
+		{stringClass, NewInteger(1), FALSE},
+		{standardErrorClass, NewArgumentError("x"), TRUE},
+		{argumentErrorClass, NewTypeError("x"), FALSE},
+		{objectClass, &Self{RubyObject: &Object{}}, TRUE},
+	}
+
+	for _, tt := range tests {
+		context := &callContext{receiver: tt.receiver}
+
+		result, err := moduleCaseEqual(context, tt.argument)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, tt.result)
+	}
+}
+
+func TestModuleAliasMethod(t *testing.T) {
+	receiver := &class{
+		name: "Foo",
+		instanceMethods: NewMethodSet(map[string]RubyMethod{
+			"foo": publicMethod(func(context CallContext, args ...RubyObject) (RubyObject, error) {
+				return TRUE, nil
+			}),
+		}),
+		superClass: objectClass,
+	}
+
+	tests := []struct {
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{
+			[]RubyObject{&Symbol{Value: "bar"}, &Symbol{Value: "foo"}},
+			&Symbol{Value: "bar"},
+			nil,
+		},
+		{
+			[]RubyObject{&String{Value: "qux"}, &String{Value: "foo"}},
+			&Symbol{Value: "qux"},
+			nil,
+		},
+		{
+			[]RubyObject{&Symbol{Value: "bar"}, &Symbol{Value: "baz"}},
+			nil,
+			NewUndefinedMethodNameError(receiver, "baz"),
+		},
+		{
+			[]RubyObject{NewInteger(1), &Symbol{Value: "foo"}},
+			nil,
+			NewTypeError("1 is not a symbol nor a string"),
+		},
+	}
+
+	for _, tt := range tests {
+		context := &callContext{receiver: receiver}
+
+		result, err := moduleAliasMethod(context, tt.arguments...)
+
+		checkError(t, errors.Cause(err), tt.err)
+
+		checkResult(t, result, tt.result)
+	}
+
+	for _, name := range []string{"bar", "qux"} {
+		if _, ok := receiver.Methods().Get(name); !ok {
+			t.Logf("Expected %s to be defined", name)
+			t.Fail()
+		}
+	}
+}
+
+func TestModuleUndefMethod(t *testing.T) {
+	receiver := &class{
+		name:            "Foo",
+		instanceMethods: NewMethodSet(map[string]RubyMethod{}),
+		superClass:      objectClass,
+	}
+	context := &callContext{receiver: receiver}
+
+	result, err := moduleUndefMethod(context, &Symbol{Value: "to_s"}, &String{Value: "nil?"})
+
+	checkError(t, err, nil)
+
+	checkResult(t, result, receiver)
+
+	for _, name := range []string{"to_s", "nil?"} {
+		if _, ok := findMethod(receiver, name); ok {
+			t.Logf("Expected %s to be undefined", name)
+			t.Fail()
+		}
+	}
+
+	_, err = moduleUndefMethod(context, &Symbol{Value: "to_s"})
+
+	checkError(t, errors.Cause(err), NewUndefinedMethodNameError(receiver, "to_s"))
+}
+
+func TestModuleRemoveMethod(t *testing.T) {
+	receiver := &class{
+		name: "Foo",
+		instanceMethods: NewMethodSet(map[string]RubyMethod{
+			"to_s": publicMethod(func(context CallContext, args ...RubyObject) (RubyObject, error) {
+				return &String{Value: "foo"}, nil
+			}),
+		}),
+		superClass: objectClass,
+	}
+	context := &callContext{receiver: receiver}
+
+	result, err := moduleRemoveMethod(context, &Symbol{Value: "to_s"})
+
+	checkError(t, err, nil)
+
+	checkResult(t, result, receiver)
+
+	if _, ok := findMethod(receiver, "to_s"); !ok {
+		t.Logf("Expected to_s of Object to be found")
+		t.Fail()
+	}
+
+	_, err = moduleRemoveMethod(context, &Symbol{Value: "to_s"})
+
+	checkError(t, errors.Cause(err), NewMethodNotDefinedNameError(receiver, "to_s"))
+}
@@ -0,0 +1,24 @@
+package object
+
+import (
+	"github.com/pkg/errors"
+)
+
+// coerceOperation sends coerce to other with the receiver of context and
+// sends method to the first element of the resulting pair with the second one
+// as argument. The boolean is false if other does not respond to coerce.
+func coerceOperation(context CallContext, method string, other RubyObject) (RubyObject, bool, error) {
+	pair, err := Send(NewCallContext(context.Env(), other), "coerce", context.Receiver())
+	if err != nil {
+		if _, ok := errors.Cause(err).(*NoMethodError); ok {
+			return nil, false, nil
+		}
+		return nil, true, err
+	}
+	coerced, ok := pair.(*Array)
+	if !ok || len(coerced.Elements) != 2 {
+		return nil, true, NewTypeError("coerce must return [x, y]")
+	}
+	result, err := Send(NewCallContext(context.Env(), coerced.Elements[0]), method, coerced.Elements[1])
+	return result, true, err
+}
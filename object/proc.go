@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/goruby/goruby/ast"
+	"github.com/pkg/errors"
 )
 
 var procClass RubyClassObject = newClass(
@@ -22,27 +23,58 @@ func extractBlockFromArgs(args []RubyObject) (*Proc, []RubyObject, bool) {
 	if len(args) == 0 {
 		return nil, args, false
 	}
-	block, ok := args[len(args)-1].(*Proc)
+	block, ok := args[len(args)-1].(*BlockArgument)
 	if !ok {
 		return nil, args, false
 	}
 	args = args[:len(args)-1]
-	return block, args, true
+	return block.Proc, args, true
+}
+
+// A BlockArgument marks the proc passed as block to a method call, e.g.
+// `foo { }` or `foo(&b)`, as opposed to a proc passed as regular argument
+// like in `foo(b)`. It is always the last argument of a call.
+type BlockArgument struct {
+	*Proc
+}
+
+// NewBlockArgument returns a BlockArgument passing block to a method call
+func NewBlockArgument(block *Proc) *BlockArgument {
+	return &BlockArgument{Proc: block}
 }
 
 // A Proc represents a user defined block of code.
 type Proc struct {
-	Parameters             []*ast.FunctionParameter
-	Body                   *ast.BlockStatement
-	Env                    Environment
+	Parameters []*ast.FunctionParameter
+	// Locals are the block-local variables, which shadow the variables of
+	// the enclosing scope
+	Locals []*ast.Identifier
+	Body   *ast.BlockStatement
+	Env    Environment
+	// ArgumentCountMandatory marks a lambda. Lambdas check their arguments
+	// strictly and a return within them returns from the lambda itself
+	// instead of the method the proc was created in.
 	ArgumentCountMandatory bool
+	// native is set for procs implemented in Go, like those returned by
+	// Proc#curry or Symbol#to_proc, which call it instead of evaluating Body
+	native func(CallContext, ...RubyObject) (RubyObject, error)
+	// orphaned is set once the method call the proc was attached to as
+	// block literal returned. A break has nothing to return from then.
+	orphaned bool
 }
 
+// Orphan marks the method call p was attached to as block literal as
+// returned. A break within p raises a LocalJumpError from then on.
+func (p *Proc) Orphan() { p.orphaned = true }
+
 // Type returns proc_OBJ
 func (p *Proc) Type() Type { return "" }
 
 // Inspect returns the proc body
 func (p *Proc) Inspect() string {
+	if p.native != nil {
+		return "#<Proc (lambda)>"
+	}
 	var out bytes.Buffer
 	params := []string{}
 	for _, p := range p.Parameters {
@@ -59,33 +91,326 @@ func (p *Proc) Inspect() string {
 // Class returns procClass
 func (p *Proc) Class() RubyClass { return procClass }
 
-// Call implements the RubyMethod interface. It evaluates p.Body and returns its result
+// Call implements the RubyMethod interface. It evaluates p.Body and returns its result.
+// A next within the body ends the evaluation with its value, a redo restarts it
+// and a break is tagged with p to be caught at the method call p belongs to.
+// A break within an orphaned proc raises a LocalJumpError.
+// A return ends a lambda, whereas it returns from the method a proc was created in.
 func (p *Proc) Call(context CallContext, args ...RubyObject) (RubyObject, error) {
-	if p.ArgumentCountMandatory && len(args) != len(p.Parameters) {
-		return nil, NewWrongNumberOfArgumentsError(len(p.Parameters), len(args))
+	if p.native != nil {
+		return p.native(context, args...)
 	}
-	extendedEnv := p.extendProcEnv(args)
-	evaluated, err := context.Eval(p.Body, extendedEnv)
+	// procs don't take blocks
+	_, args, _ = extractBlockFromArgs(args)
+	extendedEnv, err := p.extendProcEnv(context, args)
 	if err != nil {
 		return nil, err
 	}
-	return evaluated, nil
+	for {
+		evaluated, err := context.Eval(p.Body, extendedEnv)
+		if returnValue, ok := evaluated.(*ReturnValue); ok && err == nil {
+			if p.ArgumentCountMandatory {
+				return returnValue.Value, nil
+			}
+			frame, _ := p.Env.Get("self")
+			self, _ := frame.(*Self)
+			if self == nil || self.Method == nil || self.returned {
+				return nil, errors.WithStack(NewLocalJumpError("unexpected return"))
+			}
+			return nil, errors.WithStack(&ReturnError{Value: returnValue.Value, Frame: self})
+		}
+		if err == nil {
+			return evaluated, nil
+		}
+		switch jump := errors.Cause(err).(type) {
+		case *NextError:
+			return jump.Value, nil
+		case *RedoError:
+			continue
+		case *BreakError:
+			if p.ArgumentCountMandatory && (jump.Block == nil || jump.Block == p) {
+				return jump.Value, nil
+			}
+			if jump.Block == nil && p.orphaned {
+				return nil, errors.WithStack(NewLocalJumpError("break from proc-closure"))
+			}
+			if jump.Block == nil {
+				jump.Block = p
+			}
+		}
+		return nil, err
+	}
+}
+
+// positionalArity returns the number of required and optional positional
+// parameters and whether there is a rest parameter
+func (p *Proc) positionalArity() (required, optional int, rest bool) {
+	for _, param := range p.Parameters {
+		switch {
+		case param.Kind == ast.SplatParameter:
+			rest = true
+		case param.Kind == ast.DestructuringParameter:
+			required++
+		case param.Kind != ast.PositionalParameter:
+		case param.Default != nil:
+			optional++
+		default:
+			required++
+		}
+	}
+	return required, optional, rest
 }
 
-func (p *Proc) extendProcEnv(args []RubyObject) Environment {
+// arity returns the number of mandatory arguments. If p takes optional
+// arguments, it returns -n-1, where n is the number of mandatory arguments.
+func (p *Proc) arity() int {
+	required, optional, rest := p.positionalArity()
+	if optional != 0 || rest {
+		return -required - 1
+	}
+	return required
+}
+
+func (p *Proc) extendProcEnv(context CallContext, args []RubyObject) (Environment, error) {
 	env := NewEnclosedEnvironment(p.Env)
-	arguments := args
-	if len(args) < len(p.Parameters) {
-		for i := 0; i < (len(p.Parameters) - len(args)); i++ {
-			arguments = append(arguments, NIL)
+	for _, local := range p.Locals {
+		env.Set(local.Value, NIL)
+	}
+	required, optional, rest := p.positionalArity()
+	// a trailing hash provides the keyword arguments if the proc accepts
+	// keywords and the hash is not needed as positional argument
+	keywords := &Hash{}
+	if p.acceptsKeywords() && len(args) > required {
+		if hash, ok := args[len(args)-1].(*Hash); ok {
+			keywords = hash
+			args = args[:len(args)-1]
+		}
+	}
+	remaining := make(map[string]RubyObject)
+	for key, value := range keywords.Map() {
+		remaining[key.Inspect()] = value
+	}
+	var missing []string
+	// a proc taking several parameters spreads a single array argument
+	// over them, i.e. `|a, b|` called with `[1, 2]`
+	if !p.ArgumentCountMandatory && len(args) == 1 && (required+optional > 1 || rest && required+optional > 0) {
+		if arr, ok := args[0].(*Array); ok {
+			args = arr.Elements
+		}
+	}
+	if p.ArgumentCountMandatory {
+		if err := checkArity(required, optional, rest, len(args)); err != nil {
+			return nil, err
+		}
+	}
+	optionalArgs := len(args) - required
+	if optionalArgs > optional {
+		optionalArgs = optional
+	}
+	restArgs := len(args) - required - optionalArgs
+	if restArgs < 0 {
+		restArgs = 0
+	}
+
+	argIdx := 0
+	for _, param := range p.Parameters {
+		var value RubyObject = NIL
+		switch {
+		case param.Kind == ast.SplatParameter:
+			value = NewArray()
+			if argIdx < len(args) {
+				value = NewArray(args[argIdx : argIdx+restArgs]...)
+			}
+			argIdx += restArgs
+		case param.Kind == ast.KeywordParameter:
+			key := (&Symbol{Value: param.Name.Value}).Inspect()
+			given, ok := remaining[key]
+			switch {
+			case ok:
+				value = given
+				delete(remaining, key)
+			case param.Default != nil:
+				evaluated, err := context.Eval(param.Default, env)
+				if err != nil {
+					return nil, err
+				}
+				value = evaluated
+			default:
+				missing = append(missing, key)
+			}
+		case param.Kind == ast.DoubleSplatParameter:
+			value = remainingKeywords(keywords, remaining)
+			remaining = nil
+		case param.Default != nil && (param.Kind != ast.PositionalParameter || optionalArgs == 0):
+			evaluated, err := context.Eval(param.Default, env)
+			if err != nil {
+				return nil, err
+			}
+			value = evaluated
+		case param.Kind != ast.PositionalParameter && param.Kind != ast.DestructuringParameter:
+		default:
+			if param.Default != nil {
+				optionalArgs--
+			}
+			if argIdx < len(args) {
+				value = args[argIdx]
+			}
+			argIdx++
 		}
+		setProcParameter(env, param, value)
 	}
-	for paramIdx, param := range p.Parameters {
-		env.Set(param.Name.Value, arguments[paramIdx])
+	if len(missing) != 0 {
+		return nil, newMissingKeywordsError(missing)
 	}
-	return env
+	if len(remaining) != 0 {
+		return nil, newUnknownKeywordsError(remaining)
+	}
+	return env, nil
 }
 
-var procClassMethods = map[string]RubyMethod{}
+// acceptsKeywords reports whether p has keyword or double splat parameters
+func (p *Proc) acceptsKeywords() bool {
+	for _, param := range p.Parameters {
+		if param.Kind == ast.KeywordParameter || param.Kind == ast.DoubleSplatParameter {
+			return true
+		}
+	}
+	return false
+}
 
-var procMethods = map[string]RubyMethod{}
+// setProcParameter binds value to param within env. A destructuring
+// parameter spreads value over its nested parameters.
+func setProcParameter(env Environment, param *ast.FunctionParameter, value RubyObject) {
+	if param.Kind != ast.DestructuringParameter {
+		env.Set(param.Name.Value, value)
+		return
+	}
+	values := []RubyObject{value}
+	if arr, ok := value.(*Array); ok {
+		values = arr.Elements
+	}
+	restArgs := len(values) - len(param.Parameters) + 1
+	idx := 0
+	for _, nested := range param.Parameters {
+		var nestedValue RubyObject = NIL
+		switch {
+		case nested.Kind == ast.SplatParameter:
+			nestedValue = NewArray()
+			if restArgs > 0 {
+				nestedValue = NewArray(values[idx : idx+restArgs]...)
+				idx += restArgs
+			}
+		case idx < len(values):
+			nestedValue = values[idx]
+			idx++
+		}
+		setProcParameter(env, nested, nestedValue)
+	}
+}
+
+var procClassMethods = map[string]RubyMethod{
+	"new": publicMethod(procNew),
+}
+
+var procMethods = map[string]RubyMethod{
+	"call":       publicMethod(procCall),
+	"[]":         publicMethod(procCall),
+	"yield":      publicMethod(procCall),
+	"===":        publicMethod(procCall),
+	"arity":      withArity(0, publicMethod(procArity)),
+	"lambda?":    withArity(0, publicMethod(procIsLambda)),
+	"curry":      publicMethod(procCurry),
+	"parameters": withArity(0, publicMethod(procParameters)),
+}
+
+func procNew(context CallContext, args ...RubyObject) (RubyObject, error) {
+	block, _, ok := extractBlockFromArgs(args)
+	if !ok {
+		return nil, NewArgumentError("tried to create Proc object without a block")
+	}
+	return block, nil
+}
+
+func procCall(context CallContext, args ...RubyObject) (RubyObject, error) {
+	proc := context.Receiver().(*Proc)
+	return proc.Call(context, args...)
+}
+
+func procArity(context CallContext, args ...RubyObject) (RubyObject, error) {
+	proc := context.Receiver().(*Proc)
+	return NewInteger(int64(proc.arity())), nil
+}
+
+func procIsLambda(context CallContext, args ...RubyObject) (RubyObject, error) {
+	proc := context.Receiver().(*Proc)
+	return nativeBoolToBoolean(proc.ArgumentCountMandatory), nil
+}
+
+func procParameters(context CallContext, args ...RubyObject) (RubyObject, error) {
+	proc := context.Receiver().(*Proc)
+	parameters := NewArray()
+	for _, param := range proc.Parameters {
+		var kind string
+		switch {
+		case param.Kind == ast.SplatParameter:
+			kind = "rest"
+		case param.Kind == ast.DoubleSplatParameter:
+			kind = "keyrest"
+		case param.Kind == ast.KeywordParameter && param.Default == nil:
+			kind = "keyreq"
+		case param.Kind == ast.KeywordParameter:
+			kind = "key"
+		case param.Default == nil && proc.ArgumentCountMandatory:
+			kind = "req"
+		default:
+			kind = "opt"
+		}
+		if param.Kind == ast.DestructuringParameter {
+			parameters.Elements = append(parameters.Elements, NewArray(&Symbol{Value: kind}))
+			continue
+		}
+		parameters.Elements = append(
+			parameters.Elements,
+			NewArray(&Symbol{Value: kind}, &Symbol{Value: param.Name.Value}),
+		)
+	}
+	return parameters, nil
+}
+
+func procCurry(context CallContext, args ...RubyObject) (RubyObject, error) {
+	proc := context.Receiver().(*Proc)
+	required, optional, rest := proc.positionalArity()
+	arity := required
+	switch len(args) {
+	case 0:
+	case 1:
+		n, ok := args[0].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(n, args[0])
+		}
+		arity = int(n.Value)
+		if proc.ArgumentCountMandatory {
+			if err := checkArity(required, optional, rest, arity); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, NewWrongNumberOfArgumentsRangeError(0, 1, false, len(args))
+	}
+	return curry(proc, arity, nil), nil
+}
+
+// curry returns a lambda collecting arguments until there are arity of them
+// and calls proc with them
+func curry(proc *Proc, arity int, collected []RubyObject) *Proc {
+	return &Proc{
+		ArgumentCountMandatory: true,
+		native: func(context CallContext, args ...RubyObject) (RubyObject, error) {
+			arguments := append(append([]RubyObject{}, collected...), args...)
+			if len(arguments) < arity {
+				return curry(proc, arity, arguments), nil
+			}
+			return proc.Call(context, arguments...)
+		},
+	}
+}
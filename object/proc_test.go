@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/goruby/goruby/ast"
+	"github.com/pkg/errors"
 )
 
 func TestExtractBlockFromArgs(t *testing.T) {
@@ -29,7 +30,7 @@ func TestExtractBlockFromArgs(t *testing.T) {
 		}
 	})
 	t.Run("args with block only", func(t *testing.T) {
-		args := []RubyObject{&Proc{}}
+		args := []RubyObject{NewBlockArgument(&Proc{})}
 
 		block, remaining, ok := extractBlockFromArgs(args)
 
@@ -49,7 +50,7 @@ func TestExtractBlockFromArgs(t *testing.T) {
 		}
 	})
 	t.Run("args with nil and block", func(t *testing.T) {
-		args := []RubyObject{NIL, &Proc{}}
+		args := []RubyObject{NIL, NewBlockArgument(&Proc{})}
 
 		block, remaining, ok := extractBlockFromArgs(args)
 
@@ -75,8 +76,28 @@ func TestExtractBlockFromArgs(t *testing.T) {
 			t.Fail()
 		}
 	})
+	t.Run("args with proc as regular argument", func(t *testing.T) {
+		args := []RubyObject{NIL, &Proc{}}
+
+		block, remaining, ok := extractBlockFromArgs(args)
+
+		if ok {
+			t.Logf("Expected no block found")
+			t.Fail()
+		}
+
+		if block != nil {
+			t.Logf("Expected block to be nil, got %+#v\n", block)
+			t.Fail()
+		}
+
+		if len(remaining) != 2 {
+			t.Logf("Expected remaining args to have length %d, got %d", 2, len(remaining))
+			t.Fail()
+		}
+	})
 	t.Run("args with nil and block but block not at the end", func(t *testing.T) {
-		args := []RubyObject{&Proc{}, NIL}
+		args := []RubyObject{NewBlockArgument(&Proc{}), NIL}
 
 		block, remaining, ok := extractBlockFromArgs(args)
 
@@ -95,7 +116,7 @@ func TestExtractBlockFromArgs(t *testing.T) {
 			t.Fail()
 		}
 
-		expected := []RubyObject{&Proc{}, NIL}
+		expected := []RubyObject{NewBlockArgument(&Proc{}), NIL}
 
 		if !reflect.DeepEqual(expected, remaining) {
 			t.Logf("Expected remaining args to equal\n%+#v\n\tgot\n%+#v\n", expected, remaining)
@@ -107,9 +128,9 @@ func TestExtractBlockFromArgs(t *testing.T) {
 func TestProcCall(t *testing.T) {
 	t.Run("argument count not mandatory", func(t *testing.T) {
 		proc := &Proc{
-			Parameters: []*ast.FunctionParameter{&ast.FunctionParameter{Name: &ast.Identifier{Value: "a"}}},
-			Body:       &ast.BlockStatement{Statements: []ast.Statement{}},
-			Env:        NewEnvironment(),
+			Parameters:             []*ast.FunctionParameter{&ast.FunctionParameter{Name: &ast.Identifier{Value: "a"}}},
+			Body:                   &ast.BlockStatement{Statements: []ast.Statement{}},
+			Env:                    NewEnvironment(),
 			ArgumentCountMandatory: false,
 		}
 		eval := func(node ast.Node, env Environment) (RubyObject, error) {
@@ -127,9 +148,9 @@ func TestProcCall(t *testing.T) {
 	})
 	t.Run("argument count mandatory", func(t *testing.T) {
 		proc := &Proc{
-			Parameters: []*ast.FunctionParameter{&ast.FunctionParameter{Name: &ast.Identifier{Value: "a"}}},
-			Body:       &ast.BlockStatement{Statements: []ast.Statement{}},
-			Env:        NewEnvironment(),
+			Parameters:             []*ast.FunctionParameter{&ast.FunctionParameter{Name: &ast.Identifier{Value: "a"}}},
+			Body:                   &ast.BlockStatement{Statements: []ast.Statement{}},
+			Env:                    NewEnvironment(),
 			ArgumentCountMandatory: true,
 		}
 		eval := func(node ast.Node, env Environment) (RubyObject, error) {
@@ -147,4 +168,61 @@ func TestProcCall(t *testing.T) {
 
 		checkError(t, err, expected)
 	})
+	t.Run("break", func(t *testing.T) {
+		eval := func(node ast.Node, env Environment) (RubyObject, error) {
+			return nil, &BreakError{Value: NIL}
+		}
+		context := &callContext{
+			receiver: NIL,
+			env:      NewEnvironment(),
+			eval:     eval,
+		}
+
+		t.Run("tagged with the proc", func(t *testing.T) {
+			proc := &Proc{Body: &ast.BlockStatement{}, Env: NewEnvironment()}
+
+			_, err := proc.Call(context)
+
+			brk, ok := errors.Cause(err).(*BreakError)
+			if !ok || brk.Block != proc {
+				t.Logf("Expected break tagged with the proc, got %T:%v\n", err, err)
+				t.Fail()
+			}
+		})
+		t.Run("within orphaned proc", func(t *testing.T) {
+			proc := &Proc{Body: &ast.BlockStatement{}, Env: NewEnvironment()}
+			proc.Orphan()
+
+			_, err := proc.Call(context)
+
+			checkError(t, errors.Cause(err), NewLocalJumpError("break from proc-closure"))
+		})
+	})
+}
+
+func TestProcArity(t *testing.T) {
+	param := func(name string, kind ast.ParameterKind, def ast.Expression) *ast.FunctionParameter {
+		return &ast.FunctionParameter{Name: &ast.Identifier{Value: name}, Kind: kind, Default: def}
+	}
+	tests := []struct {
+		params []*ast.FunctionParameter
+		lambda bool
+		result RubyObject
+	}{
+		{nil, true, NewInteger(0)},
+		{[]*ast.FunctionParameter{param("a", ast.PositionalParameter, nil)}, true, NewInteger(1)},
+		{[]*ast.FunctionParameter{param("a", ast.PositionalParameter, nil), param("b", ast.PositionalParameter, &ast.IntegerLiteral{Value: 1})}, true, NewInteger(-2)},
+		{[]*ast.FunctionParameter{param("a", ast.SplatParameter, nil)}, false, NewInteger(-1)},
+	}
+
+	for _, tt := range tests {
+		proc := &Proc{Parameters: tt.params, ArgumentCountMandatory: tt.lambda}
+		context := &callContext{receiver: proc}
+
+		result, err := procArity(context)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, tt.result)
+	}
 }
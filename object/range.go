@@ -0,0 +1,413 @@
+package object
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+var rangeClass RubyClassObject = newClass(
+	"Range", objectClass, rangeMethods, rangeClassMethods, notInstantiatable,
+)
+
+func init() {
+	classes.Set("Range", rangeClass)
+}
+
+// NewRange returns a new Range from left to right. A nil left or right
+// represents a beginless or endless range.
+func NewRange(left, right RubyObject, exclusive bool) *Range {
+	if left == nil {
+		left = NIL
+	}
+	if right == nil {
+		right = NIL
+	}
+	return &Range{Left: left, Right: right, Exclusive: exclusive}
+}
+
+// A Range represents an interval between a beginning and an end in Ruby
+type Range struct {
+	Left      RubyObject
+	Right     RubyObject
+	Exclusive bool
+}
+
+// Inspect returns the range in its literal form
+func (r *Range) Inspect() string {
+	op := ".."
+	if r.Exclusive {
+		op = "..."
+	}
+	left, right := r.Left.Inspect(), r.Right.Inspect()
+	if r.Left == NIL && r.Right != NIL {
+		left = ""
+	}
+	if r.Right == NIL && r.Left != NIL {
+		right = ""
+	}
+	if r.Left == NIL && r.Right == NIL {
+		left, right = "nil", "nil"
+	}
+	return left + op + right
+}
+
+// Type returns RANGE_OBJ
+func (r *Range) Type() Type { return RANGE_OBJ }
+
+// Class returns rangeClass
+func (r *Range) Class() RubyClass { return rangeClass }
+
+func (r *Range) hashKey() hashKey {
+	var exclusive uint64
+	if r.Exclusive {
+		exclusive = 1
+	}
+	return hashKey{
+		Type:  r.Type(),
+		Value: hash(r.Left).Value*31 + hash(r.Right).Value*17 + exclusive,
+	}
+}
+
+// IntegerBounds returns the first and the last integer within the range.
+// The range must begin with an Integer. If the range is endless, last is
+// math.MaxInt64. Other ranges, e.g. of Strings, can't be iterated and return
+// a TypeError.
+func (r *Range) IntegerBounds() (first, last int64, err error) {
+	left, ok := r.Left.(*Integer)
+	if !ok {
+		return 0, 0, NewTypeError(
+			fmt.Sprintf("can't iterate from %s", r.Left.Class().Name()),
+		)
+	}
+	first = left.Value
+	switch right := r.Right.(type) {
+	case *Integer:
+		last = right.Value
+		if r.Exclusive {
+			last--
+		}
+	case *Float:
+		last = int64(math.Floor(right.Value))
+		if r.Exclusive && float64(last) == right.Value {
+			last--
+		}
+	default:
+		if r.Right != NIL {
+			return 0, 0, NewTypeError(
+				fmt.Sprintf("can't iterate from %s", r.Left.Class().Name()),
+			)
+		}
+		last = math.MaxInt64
+	}
+	return first, last, nil
+}
+
+// covers returns true if obj lies between the beginning and the end of the
+// range
+func (r *Range) covers(context CallContext, obj RubyObject) (bool, error) {
+	if r.Left != NIL {
+		cmp, err := compare(context, r.Left, obj)
+		if err != nil || cmp > 0 {
+			return false, err
+		}
+	}
+	if r.Right != NIL {
+		cmp, err := compare(context, obj, r.Right)
+		if err != nil {
+			return false, err
+		}
+		if cmp > 0 || (r.Exclusive && cmp == 0) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// compare sends <=> to left with right and returns the result as int. It
+// returns an ArgumentError if the objects are not comparable.
+func compare(context CallContext, left, right RubyObject) (int, error) {
+	result, err := Send(NewCallContext(context.Env(), left), "<=>", right)
+	if err != nil {
+		return 0, err
+	}
+	cmp, ok := result.(*Integer)
+	if !ok {
+		return 0, NewArgumentError(
+			"comparison of %s with %s failed",
+			left.Class().Name(),
+			right.Inspect(),
+		)
+	}
+	return int(cmp.Value), nil
+}
+
+var rangeClassMethods = map[string]RubyMethod{}
+
+var rangeMethods = map[string]RubyMethod{
+	"begin":        withArity(0, publicMethod(rangeBegin)),
+	"end":          withArity(0, publicMethod(rangeEnd)),
+	"first":        publicMethod(rangeFirst),
+	"last":         publicMethod(rangeLast),
+	"exclude_end?": withArity(0, publicMethod(rangeExcludeEnd)),
+	"each":         publicMethod(rangeEach),
+	"step":         publicMethod(rangeStep),
+	"to_a":         withArity(0, publicMethod(rangeToA)),
+	"size":         withArity(0, publicMethod(rangeSize)),
+	"include?":     withArity(1, publicMethod(rangeCover)),
+	"member?":      withArity(1, publicMethod(rangeCover)),
+	"cover?":       withArity(1, publicMethod(rangeCover)),
+	"==":           withArity(1, publicMethod(rangeEq)),
+	"===":          withArity(1, publicMethod(rangeCover)),
+	"to_s":         withArity(0, publicMethod(rangeToS)),
+}
+
+func rangeBegin(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver().(*Range).Left, nil
+}
+
+func rangeEnd(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return context.Receiver().(*Range).Right, nil
+}
+
+func rangeExcludeEnd(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return nativeBoolToBoolean(context.Receiver().(*Range).Exclusive), nil
+}
+
+func rangeToS(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return &String{Value: context.Receiver().Inspect()}, nil
+}
+
+func rangeFirst(context CallContext, args ...RubyObject) (RubyObject, error) {
+	r := context.Receiver().(*Range)
+	if len(args) > 1 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	if len(args) == 0 {
+		if r.Left == NIL {
+			return nil, NewRangeError("cannot get the first element of beginless range")
+		}
+		return r.Left, nil
+	}
+	n, ok := args[0].(*Integer)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(&Integer{}, args[0])
+	}
+	if n.Value < 0 {
+		return nil, NewArgumentError("negative array size (or size too big)")
+	}
+	first, last, err := r.IntegerBounds()
+	if err != nil {
+		return nil, err
+	}
+	elements := []RubyObject{}
+	for i := first; i <= last && int64(len(elements)) < n.Value; i++ {
+		elements = append(elements, NewInteger(i))
+	}
+	return NewArray(elements...), nil
+}
+
+func rangeLast(context CallContext, args ...RubyObject) (RubyObject, error) {
+	r := context.Receiver().(*Range)
+	if len(args) > 1 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	if r.Right == NIL {
+		return nil, NewRangeError("cannot get the last element of endless range")
+	}
+	if len(args) == 0 {
+		return r.Right, nil
+	}
+	n, ok := args[0].(*Integer)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(&Integer{}, args[0])
+	}
+	if n.Value < 0 {
+		return nil, NewArgumentError("negative array size")
+	}
+	first, last, err := r.IntegerBounds()
+	if err != nil {
+		return nil, err
+	}
+	if last-first+1 > n.Value {
+		first = last - n.Value + 1
+	}
+	elements := []RubyObject{}
+	for i := first; i <= last; i++ {
+		elements = append(elements, NewInteger(i))
+	}
+	return NewArray(elements...), nil
+}
+
+func rangeEach(context CallContext, args ...RubyObject) (RubyObject, error) {
+	r := context.Receiver().(*Range)
+	block, remainingArgs, ok := extractBlockFromArgs(args)
+	if !ok {
+		return nil, NewNoBlockGivenLocalJumpError()
+	}
+	if len(remainingArgs) != 0 {
+		return nil, NewWrongNumberOfArgumentsError(0, len(remainingArgs))
+	}
+	first, last, err := r.IntegerBounds()
+	if err != nil {
+		return nil, err
+	}
+	for i := first; i <= last; i++ {
+		_, err := block.Call(context, NewInteger(i))
+		if err != nil {
+			return nil, err
+		}
+		if i == math.MaxInt64 {
+			break
+		}
+	}
+	return r, nil
+}
+
+func rangeToA(context CallContext, args ...RubyObject) (RubyObject, error) {
+	r := context.Receiver().(*Range)
+	if r.Right == NIL {
+		return nil, NewRangeError("cannot convert endless range to an array")
+	}
+	first, last, err := r.IntegerBounds()
+	if err != nil {
+		return nil, err
+	}
+	elements := []RubyObject{}
+	for i := first; i <= last; i++ {
+		elements = append(elements, NewInteger(i))
+	}
+	return NewArray(elements...), nil
+}
+
+func rangeSize(context CallContext, args ...RubyObject) (RubyObject, error) {
+	r := context.Receiver().(*Range)
+	if _, ok := floatValue(r.Left); !ok {
+		return NIL, nil
+	}
+	if r.Right == NIL {
+		return NewFloat(math.Inf(1)), nil
+	}
+	if _, ok := r.Left.(*Integer); !ok {
+		return NIL, nil
+	}
+	first, last, err := r.IntegerBounds()
+	if err != nil {
+		return NIL, nil
+	}
+	if last < first {
+		return NewInteger(0), nil
+	}
+	return NewInteger(last - first + 1), nil
+}
+
+func rangeCover(context CallContext, args ...RubyObject) (RubyObject, error) {
+	r := context.Receiver().(*Range)
+	covers, err := r.covers(context, args[0])
+	if err != nil {
+		if _, ok := errors.Cause(err).(*ArgumentError); ok {
+			return FALSE, nil
+		}
+		return nil, err
+	}
+	return nativeBoolToBoolean(covers), nil
+}
+
+func rangeEq(context CallContext, args ...RubyObject) (RubyObject, error) {
+	r := context.Receiver().(*Range)
+	other, ok := args[0].(*Range)
+	if !ok || r.Exclusive != other.Exclusive {
+		return FALSE, nil
+	}
+	for _, pair := range [][2]RubyObject{{r.Left, other.Left}, {r.Right, other.Right}} {
+		eq, err := Send(NewCallContext(context.Env(), pair[0]), "==", pair[1])
+		if err != nil {
+			return nil, err
+		}
+		if eq != TRUE {
+			return FALSE, nil
+		}
+	}
+	return TRUE, nil
+}
+
+// rangeStep steps through a numeric range. The elements are Integers when the
+// beginning, the end and the step are Integers and Floats otherwise. Ranges of
+// other objects, e.g. Strings, can't be iterated and raise a TypeError.
+func rangeStep(context CallContext, args ...RubyObject) (RubyObject, error) {
+	r := context.Receiver().(*Range)
+	block, remainingArgs, blockGiven := extractBlockFromArgs(args)
+	if len(remainingArgs) != 1 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(remainingArgs))
+	}
+	step, ok := floatValue(remainingArgs[0])
+	if !ok {
+		return nil, NewCoercionTypeError(NewInteger(0), remainingArgs[0])
+	}
+	if step < 0 {
+		return nil, NewArgumentError("step can't be negative")
+	}
+	if step == 0 {
+		return nil, NewArgumentError("step can't be 0")
+	}
+	start, ok := floatValue(r.Left)
+	if !ok {
+		return nil, NewTypeError(
+			fmt.Sprintf("can't iterate from %s", r.Left.Class().Name()),
+		)
+	}
+	end := math.Inf(1)
+	if r.Right != NIL {
+		end, ok = floatValue(r.Right)
+		if !ok {
+			return nil, NewTypeError(
+				fmt.Sprintf("can't iterate from %s", r.Left.Class().Name()),
+			)
+		}
+	}
+	if !blockGiven && math.IsInf(end, 1) {
+		return nil, NewRangeError("cannot convert endless range to an array")
+	}
+
+	elements := []RubyObject{}
+	yield := func(element RubyObject) error {
+		if !blockGiven {
+			elements = append(elements, element)
+			return nil
+		}
+		_, err := block.Call(context, element)
+		return err
+	}
+	_, leftIsInt := r.Left.(*Integer)
+	_, rightIsInt := r.Right.(*Integer)
+	intStep, stepIsInt := remainingArgs[0].(*Integer)
+	if leftIsInt && stepIsInt && (rightIsInt || r.Right == NIL) {
+		first, last, err := r.IntegerBounds()
+		if err != nil {
+			return nil, err
+		}
+		for i := first; i <= last; i += intStep.Value {
+			if err := yield(NewInteger(i)); err != nil {
+				return nil, err
+			}
+			if i > last-intStep.Value {
+				break
+			}
+		}
+	} else {
+		for i := 0; ; i++ {
+			value := start + float64(i)*step
+			if value > end || (r.Exclusive && value == end) {
+				break
+			}
+			if err := yield(NewFloat(value)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !blockGiven {
+		return NewArray(elements...), nil
+	}
+	return r, nil
+}
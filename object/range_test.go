@@ -0,0 +1,211 @@
+package object
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRangeInspect(t *testing.T) {
+	tests := []struct {
+		rng      *Range
+		expected string
+	}{
+		{NewRange(NewInteger(1), NewInteger(5), false), "1..5"},
+		{NewRange(NewInteger(1), NewInteger(5), true), "1...5"},
+		{NewRange(NewInteger(1), nil, false), "1.."},
+		{NewRange(nil, NewInteger(5), true), "...5"},
+		{NewRange(nil, nil, false), "nil..nil"},
+	}
+
+	for _, tt := range tests {
+		actual := tt.rng.Inspect()
+
+		if actual != tt.expected {
+			t.Logf("Expected %q, got %q\n", tt.expected, actual)
+			t.Fail()
+		}
+	}
+}
+
+func TestRangeIntegerBounds(t *testing.T) {
+	tests := []struct {
+		rng   *Range
+		first int64
+		last  int64
+		err   error
+	}{
+		{NewRange(NewInteger(1), NewInteger(5), false), 1, 5, nil},
+		{NewRange(NewInteger(1), NewInteger(5), true), 1, 4, nil},
+		{NewRange(NewInteger(1), NewFloat(4.5), false), 1, 4, nil},
+		{NewRange(NewInteger(1), NewFloat(4), true), 1, 3, nil},
+		{NewRange(NewInteger(1), nil, false), 1, math.MaxInt64, nil},
+		{NewRange(NewFloat(1), NewInteger(5), false), 0, 0, NewTypeError("can't iterate from Float")},
+		{NewRange(nil, NewInteger(5), false), 0, 0, NewTypeError("can't iterate from NilClass")},
+	}
+
+	for _, tt := range tests {
+		first, last, err := tt.rng.IntegerBounds()
+
+		checkError(t, err, tt.err)
+
+		if first != tt.first || last != tt.last {
+			t.Logf("Expected bounds %d, %d, got %d, %d\n", tt.first, tt.last, first, last)
+			t.Fail()
+		}
+	}
+}
+
+func TestRangeToA(t *testing.T) {
+	tests := []struct {
+		rng    *Range
+		result RubyObject
+		err    error
+	}{
+		{
+			NewRange(NewInteger(1), NewInteger(3), false),
+			NewArray(NewInteger(1), NewInteger(2), NewInteger(3)),
+			nil,
+		},
+		{
+			NewRange(NewInteger(3), NewInteger(1), false),
+			NewArray(),
+			nil,
+		},
+		{
+			NewRange(NewInteger(1), nil, false),
+			nil,
+			NewRangeError("cannot convert endless range to an array"),
+		},
+	}
+
+	for _, tt := range tests {
+		context := &callContext{receiver: tt.rng}
+
+		result, err := rangeToA(context)
+
+		checkError(t, err, tt.err)
+
+		checkResult(t, result, tt.result)
+	}
+}
+
+func TestRangeCover(t *testing.T) {
+	tests := []struct {
+		rng      *Range
+		argument RubyObject
+		result   RubyObject
+	}{
+		{NewRange(NewInteger(1), NewInteger(5), false), NewInteger(5), TRUE},
+		{NewRange(NewInteger(1), NewInteger(5), true), NewInteger(5), FALSE},
+		{NewRange(NewInteger(1), NewInteger(5), false), NewFloat(2.5), TRUE},
+		{NewRange(NewInteger(1), NewInteger(5), false), NewInteger(0), FALSE},
+		{NewRange(NewInteger(1), nil, false), NewInteger(1000), TRUE},
+		{NewRange(nil, NewInteger(5), false), NewInteger(-1000), TRUE},
+		{NewRange(NewInteger(1), NewInteger(5), false), &Symbol{"a"}, FALSE},
+	}
+
+	for _, tt := range tests {
+		context := &callContext{receiver: tt.rng}
+
+		result, err := rangeCover(context, tt.argument)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, tt.result)
+	}
+}
+
+func TestRangeSize(t *testing.T) {
+	tests := []struct {
+		rng    *Range
+		result RubyObject
+	}{
+		{NewRange(NewInteger(1), NewInteger(5), false), NewInteger(5)},
+		{NewRange(NewInteger(1), NewInteger(5), true), NewInteger(4)},
+		{NewRange(NewInteger(5), NewInteger(1), false), NewInteger(0)},
+		{NewRange(NewInteger(1), nil, false), NewFloat(math.Inf(1))},
+		{NewRange(nil, NewInteger(1), false), NIL},
+	}
+
+	for _, tt := range tests {
+		context := &callContext{receiver: tt.rng}
+
+		result, err := rangeSize(context)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, tt.result)
+	}
+}
+
+func TestRangeStep(t *testing.T) {
+	tests := []struct {
+		rng       *Range
+		arguments []RubyObject
+		result    RubyObject
+		err       error
+	}{
+		{
+			NewRange(NewInteger(1), NewInteger(7), false),
+			[]RubyObject{NewInteger(3)},
+			NewArray(NewInteger(1), NewInteger(4), NewInteger(7)),
+			nil,
+		},
+		{
+			NewRange(NewInteger(1), NewInteger(7), true),
+			[]RubyObject{NewInteger(3)},
+			NewArray(NewInteger(1), NewInteger(4)),
+			nil,
+		},
+		{
+			NewRange(NewInteger(1), NewInteger(2), false),
+			[]RubyObject{NewFloat(0.5)},
+			NewArray(NewFloat(1), NewFloat(1.5), NewFloat(2)),
+			nil,
+		},
+		{
+			NewRange(NewInteger(1), NewInteger(2), false),
+			[]RubyObject{NewInteger(0)},
+			nil,
+			NewArgumentError("step can't be 0"),
+		},
+		{
+			NewRange(NewInteger(math.MaxInt64-4), NewInteger(math.MaxInt64), false),
+			[]RubyObject{NewInteger(2)},
+			NewArray(
+				NewInteger(math.MaxInt64-4),
+				NewInteger(math.MaxInt64-2),
+				NewInteger(math.MaxInt64),
+			),
+			nil,
+		},
+		{
+			NewRange(NewInteger(1<<53), NewInteger(1<<53+2), false),
+			[]RubyObject{NewInteger(1)},
+			NewArray(NewInteger(1<<53), NewInteger(1<<53+1), NewInteger(1<<53+2)),
+			nil,
+		},
+		{
+			NewRange(NewInteger(1), NewFloat(2.5), false),
+			[]RubyObject{NewInteger(1)},
+			NewArray(NewFloat(1), NewFloat(2)),
+			nil,
+		},
+		{
+			NewRange(&String{Value: "a"}, &String{Value: "e"}, false),
+			[]RubyObject{NewInteger(2)},
+			nil,
+			NewTypeError("can't iterate from String"),
+		},
+	}
+
+	for _, tt := range tests {
+		context := &callContext{receiver: tt.rng}
+
+		result, err := rangeStep(context, tt.arguments...)
+
+		checkError(t, err, tt.err)
+
+		checkResult(t, result, tt.result)
+	}
+}
@@ -0,0 +1,371 @@
+package object
+
+import (
+	"bytes"
+	"hash/fnv"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+)
+
+var regexpClass RubyClassObject = newClass(
+	"Regexp", objectClass, regexpMethods, regexpClassMethods, notInstantiatable,
+)
+
+func init() {
+	classes.Set("Regexp", regexpClass)
+}
+
+// NewRegexp compiles source with the given Ruby options into a Regexp. It
+// returns a RegexpError if source is not a valid regular expression.
+func NewRegexp(source, options string) (*Regexp, error) {
+	var opts string
+	for _, o := range "mix" {
+		if strings.ContainsRune(options, o) {
+			opts += string(o)
+		}
+	}
+	re, err := regexp.Compile(translateRegexp(source, opts))
+	if err != nil {
+		reason := err.Error()
+		if syntaxErr, ok := err.(*syntax.Error); ok {
+			// the expression in syntaxErr is the translated one, report the
+			// source as written by the user instead
+			reason = string(syntaxErr.Code)
+		}
+		return nil, NewRegexpError("%s: /%s/", reason, source)
+	}
+	return &Regexp{Source: source, Options: opts, Regexp: re}, nil
+}
+
+// Regexp represents a regular expression in Ruby
+type Regexp struct {
+	Source  string
+	Options string
+	Regexp  *regexp.Regexp
+}
+
+// Inspect returns the regexp in its literal form
+func (r *Regexp) Inspect() string { return "/" + r.Source + "/" + r.Options }
+
+// Type returns REGEXP_OBJ
+func (r *Regexp) Type() Type { return REGEXP_OBJ }
+
+// Class returns regexpClass
+func (r *Regexp) Class() RubyClass { return regexpClass }
+
+func (r *Regexp) hashKey() hashKey {
+	h := fnv.New64a()
+	h.Write([]byte(r.Inspect()))
+	return hashKey{Type: r.Type(), Value: h.Sum64()}
+}
+
+// Match matches the regexp against str, starting at the character index pos.
+// It returns nil if the regexp does not match.
+func (r *Regexp) Match(str string, pos int) *MatchData {
+	offset := byteOffset(str, pos)
+	if offset < 0 {
+		return nil
+	}
+	loc := r.Regexp.FindStringSubmatchIndex(str[offset:])
+	if loc == nil {
+		return nil
+	}
+	for i := range loc {
+		if loc[i] >= 0 {
+			loc[i] += offset
+		}
+	}
+	return &MatchData{Regexp: r, Subject: str, Offsets: loc}
+}
+
+// translateRegexp converts the Ruby regexp source into the syntax of Go's
+// regexp package. Ruby anchors ^ and $ always match at line boundaries, the
+// Ruby m option corresponds to Go's s flag.
+func translateRegexp(source, options string) string {
+	var out bytes.Buffer
+	out.WriteString("(?m")
+	if strings.Contains(options, "i") {
+		out.WriteString("i")
+	}
+	if strings.Contains(options, "m") {
+		out.WriteString("s")
+	}
+	out.WriteString(")")
+	extended := strings.Contains(options, "x")
+	inClass := false
+	for i := 0; i < len(source); i++ {
+		c := source[i]
+		switch {
+		case c == '\\' && i+1 < len(source):
+			i++
+			switch source[i] {
+			case 'h':
+				if inClass {
+					out.WriteString("0-9a-fA-F")
+				} else {
+					out.WriteString("[0-9a-fA-F]")
+				}
+			case 'H':
+				out.WriteString("[^0-9a-fA-F]")
+			case 'Z':
+				out.WriteString(`(?:\n?\z)`)
+			default:
+				out.WriteByte('\\')
+				out.WriteByte(source[i])
+			}
+		case c == '[':
+			inClass = true
+			out.WriteByte(c)
+		case c == ']':
+			inClass = false
+			out.WriteByte(c)
+		case extended && !inClass && (c == ' ' || c == '\t' || c == '\n'):
+		case extended && !inClass && c == '#':
+			for i < len(source) && source[i] != '\n' {
+				i++
+			}
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String()
+}
+
+// byteOffset returns the byte offset of the character index pos within str.
+// Negative positions count from the end. It returns -1 if pos is out of
+// range.
+func byteOffset(str string, pos int) int {
+	if pos < 0 {
+		pos += utf8.RuneCountInString(str)
+		if pos < 0 {
+			return -1
+		}
+	}
+	offset := 0
+	for i := 0; i < pos; i++ {
+		if offset >= len(str) {
+			return -1
+		}
+		_, width := utf8.DecodeRuneInString(str[offset:])
+		offset += width
+	}
+	return offset
+}
+
+// regexpArgument converts the pattern argument of String methods into a
+// Regexp. Strings are matched literally.
+func regexpArgument(pattern RubyObject) (*Regexp, error) {
+	switch pattern := pattern.(type) {
+	case *Regexp:
+		return pattern, nil
+	case *String:
+		return NewRegexp(regexp.QuoteMeta(pattern.Value), "")
+	default:
+		return nil, NewWrongArgumentTypeError(&Regexp{}, pattern)
+	}
+}
+
+// setLastMatch stores match as the last match data in $~ of the current
+// frame. Blocks share the frame of their enclosing method, outside of any
+// method the last match lives at the root of the environment.
+func setLastMatch(context CallContext, match RubyObject) {
+	env := context.Env()
+	if env == nil {
+		return
+	}
+	for frame := env; frame != nil; frame = frame.Outer() {
+		if _, ok := frame.GetAll()["$~"]; ok {
+			frame.Set("$~", match)
+			return
+		}
+	}
+	env.SetGlobal("$~", match)
+}
+
+// matchResult returns match as RubyObject, converting a nil *MatchData into NIL
+func matchResult(match *MatchData) RubyObject {
+	if match == nil {
+		return NIL
+	}
+	return match
+}
+
+var regexpClassMethods = map[string]RubyMethod{
+	"new":    publicMethod(regexpNew),
+	"escape": withArity(1, publicMethod(regexpEscape)),
+}
+
+var regexpMethods = map[string]RubyMethod{
+	"match":   publicMethod(regexpMatch),
+	"match?":  withArity(1, publicMethod(regexpIsMatch)),
+	"=~":      withArity(1, publicMethod(regexpMatchOperator)),
+	"source":  withArity(0, publicMethod(regexpSource)),
+	"options": withArity(0, publicMethod(regexpOptions)),
+	"names":   withArity(0, publicMethod(regexpNames)),
+	"to_s":    withArity(0, publicMethod(regexpToS)),
+	"==":      withArity(1, publicMethod(regexpEq)),
+	"===":     withArity(1, publicMethod(regexpCaseEqual)),
+}
+
+func regexpNew(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	if re, ok := args[0].(*Regexp); ok {
+		return re, nil
+	}
+	source, ok := args[0].(*String)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(&String{}, args[0])
+	}
+	var options string
+	if len(args) == 2 {
+		switch opts := args[1].(type) {
+		case *String:
+			options = opts.Value
+		case *Integer:
+			for i, o := range "ixm" {
+				if opts.Value&(1<<uint(i)) != 0 {
+					options += string(o)
+				}
+			}
+		default:
+			if opts != NIL && opts != FALSE {
+				options = "i"
+			}
+		}
+	}
+	return NewRegexp(source.Value, options)
+}
+
+func regexpEscape(context CallContext, args ...RubyObject) (RubyObject, error) {
+	str, ok := args[0].(*String)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(&String{}, args[0])
+	}
+	return &String{Value: regexp.QuoteMeta(str.Value)}, nil
+}
+
+func regexpMatch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	re := context.Receiver().(*Regexp)
+	if len(args) < 1 || len(args) > 2 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	if args[0] == NIL {
+		setLastMatch(context, NIL)
+		return NIL, nil
+	}
+	str, ok := args[0].(*String)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(&String{}, args[0])
+	}
+	var pos int64
+	if len(args) == 2 {
+		p, ok := args[1].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(&Integer{}, args[1])
+		}
+		pos = p.Value
+	}
+	match := matchResult(re.Match(str.Value, int(pos)))
+	setLastMatch(context, match)
+	return match, nil
+}
+
+func regexpIsMatch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	re := context.Receiver().(*Regexp)
+	if args[0] == NIL {
+		return FALSE, nil
+	}
+	str, ok := args[0].(*String)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(&String{}, args[0])
+	}
+	return nativeBoolToBoolean(re.Regexp.MatchString(str.Value)), nil
+}
+
+func regexpMatchOperator(context CallContext, args ...RubyObject) (RubyObject, error) {
+	re := context.Receiver().(*Regexp)
+	if args[0] == NIL {
+		setLastMatch(context, NIL)
+		return NIL, nil
+	}
+	str, ok := args[0].(*String)
+	if !ok {
+		return nil, NewImplicitConversionTypeError(&String{}, args[0])
+	}
+	match := re.Match(str.Value, 0)
+	setLastMatch(context, matchResult(match))
+	if match == nil {
+		return NIL, nil
+	}
+	return NewInteger(int64(match.begin(0))), nil
+}
+
+func regexpCaseEqual(context CallContext, args ...RubyObject) (RubyObject, error) {
+	re := context.Receiver().(*Regexp)
+	var str string
+	switch arg := args[0].(type) {
+	case *String:
+		str = arg.Value
+	case *Symbol:
+		str = arg.Value
+	default:
+		return FALSE, nil
+	}
+	match := re.Match(str, 0)
+	setLastMatch(context, matchResult(match))
+	return nativeBoolToBoolean(match != nil), nil
+}
+
+func regexpSource(context CallContext, args ...RubyObject) (RubyObject, error) {
+	re := context.Receiver().(*Regexp)
+	return &String{Value: re.Source}, nil
+}
+
+func regexpOptions(context CallContext, args ...RubyObject) (RubyObject, error) {
+	re := context.Receiver().(*Regexp)
+	var options int64
+	for i, o := range "ixm" {
+		if strings.ContainsRune(re.Options, o) {
+			options |= 1 << uint(i)
+		}
+	}
+	return NewInteger(options), nil
+}
+
+func regexpNames(context CallContext, args ...RubyObject) (RubyObject, error) {
+	re := context.Receiver().(*Regexp)
+	names := []RubyObject{}
+	for _, name := range re.Regexp.SubexpNames() {
+		if name != "" {
+			names = append(names, &String{Value: name})
+		}
+	}
+	return NewArray(names...), nil
+}
+
+func regexpToS(context CallContext, args ...RubyObject) (RubyObject, error) {
+	re := context.Receiver().(*Regexp)
+	disabled := ""
+	for _, o := range "mix" {
+		if !strings.ContainsRune(re.Options, o) {
+			disabled += string(o)
+		}
+	}
+	if disabled != "" {
+		disabled = "-" + disabled
+	}
+	return &String{Value: "(?" + re.Options + disabled + ":" + re.Source + ")"}, nil
+}
+
+func regexpEq(context CallContext, args ...RubyObject) (RubyObject, error) {
+	re := context.Receiver().(*Regexp)
+	other, ok := args[0].(*Regexp)
+	if !ok {
+		return FALSE, nil
+	}
+	return nativeBoolToBoolean(re.Source == other.Source && re.Options == other.Options), nil
+}
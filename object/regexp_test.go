@@ -0,0 +1,164 @@
+package object
+
+import (
+	"testing"
+)
+
+func mustRegexp(t *testing.T, source, options string) *Regexp {
+	t.Helper()
+	re, err := NewRegexp(source, options)
+	if err != nil {
+		t.Fatalf("Unexpected error compiling /%s/%s: %v", source, options, err)
+	}
+	return re
+}
+
+func TestNewRegexp(t *testing.T) {
+	tests := []struct {
+		source  string
+		options string
+		inspect string
+		err     error
+	}{
+		{`ab+c`, "", "/ab+c/", nil},
+		{`abc`, "xim", "/abc/mix", nil},
+		{`a(b`, "", "", NewRegexpError("missing closing ): /a(b/")},
+		{`a[b`, "x", "", NewRegexpError("missing closing ]: /a[b/")},
+	}
+
+	for _, tt := range tests {
+		re, err := NewRegexp(tt.source, tt.options)
+
+		checkError(t, err, tt.err)
+
+		if err == nil && re.Inspect() != tt.inspect {
+			t.Logf("Expected %q, got %q\n", tt.inspect, re.Inspect())
+			t.Fail()
+		}
+	}
+}
+
+func TestRegexpMatchOperator(t *testing.T) {
+	tests := []struct {
+		regexp   *Regexp
+		argument RubyObject
+		result   RubyObject
+		err      error
+	}{
+		{mustRegexp(t, `l+`, ""), &String{Value: "hello"}, NewInteger(2), nil},
+		{mustRegexp(t, `ö`, ""), &String{Value: "äö"}, NewInteger(1), nil},
+		{mustRegexp(t, `x`, ""), &String{Value: "hello"}, NIL, nil},
+		{mustRegexp(t, `^b`, ""), &String{Value: "a\nb"}, NewInteger(2), nil},
+		{mustRegexp(t, `a.b`, ""), &String{Value: "a\nb"}, NIL, nil},
+		{mustRegexp(t, `a.b`, "m"), &String{Value: "a\nb"}, NewInteger(0), nil},
+		{mustRegexp(t, `A B # comment`, "xi"), &String{Value: "ab"}, NewInteger(0), nil},
+		{mustRegexp(t, `\h+`, ""), &String{Value: "xyzBEEF"}, NewInteger(3), nil},
+		{mustRegexp(t, `x`, ""), NIL, NIL, nil},
+		{mustRegexp(t, `x`, ""), NewInteger(1), nil, NewImplicitConversionTypeError(&String{}, NewInteger(1))},
+	}
+
+	for _, tt := range tests {
+		context := &callContext{receiver: tt.regexp}
+
+		result, err := regexpMatchOperator(context, tt.argument)
+
+		checkError(t, err, tt.err)
+
+		checkResult(t, result, tt.result)
+	}
+}
+
+func TestRegexpToS(t *testing.T) {
+	tests := []struct {
+		regexp *Regexp
+		result RubyObject
+	}{
+		{mustRegexp(t, `ab`, ""), &String{Value: "(?-mix:ab)"}},
+		{mustRegexp(t, `ab`, "i"), &String{Value: "(?i-mx:ab)"}},
+		{mustRegexp(t, `ab`, "mix"), &String{Value: "(?mix:ab)"}},
+	}
+
+	for _, tt := range tests {
+		context := &callContext{receiver: tt.regexp}
+
+		result, err := regexpToS(context)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, tt.result)
+	}
+}
+
+func TestMatchData(t *testing.T) {
+	re := mustRegexp(t, `(?<year>\d+)-(?<month>\d+)(-(?<day>\d+))?`, "")
+	match := re.Match("on 2020-05 at", 0)
+	if match == nil {
+		t.Fatalf("Expected a match, got nil")
+	}
+
+	inspect := `#<MatchData "2020-05" year:"2020" month:"05" 3:nil day:nil>`
+	if match.Inspect() != inspect {
+		t.Logf("Expected %q, got %q\n", inspect, match.Inspect())
+		t.Fail()
+	}
+
+	tests := []struct {
+		method   func(CallContext, ...RubyObject) (RubyObject, error)
+		argument RubyObject
+		result   RubyObject
+		err      error
+	}{
+		{matchDataIndex, NewInteger(0), &String{Value: "2020-05"}, nil},
+		{matchDataIndex, NewInteger(-3), &String{Value: "05"}, nil},
+		{matchDataIndex, NewInteger(4), NIL, nil},
+		{matchDataIndex, &Symbol{Value: "year"}, &String{Value: "2020"}, nil},
+		{matchDataIndex, &String{Value: "foo"}, nil, NewIndexError("undefined group name reference: foo")},
+		{matchDataBegin, NewInteger(2), NewInteger(8), nil},
+		{matchDataEnd, NewInteger(0), NewInteger(10), nil},
+		{matchDataEnd, NewInteger(4), NIL, nil},
+		{matchDataBegin, NewInteger(5), nil, NewIndexError("index 5 out of matches")},
+		{matchDataPreMatch, nil, &String{Value: "on "}, nil},
+		{matchDataPostMatch, nil, &String{Value: " at"}, nil},
+	}
+
+	for _, tt := range tests {
+		context := &callContext{receiver: match}
+
+		var args []RubyObject
+		if tt.argument != nil {
+			args = append(args, tt.argument)
+		}
+		result, err := tt.method(context, args...)
+
+		checkError(t, err, tt.err)
+
+		checkResult(t, result, tt.result)
+	}
+}
+
+func TestStringSubstitute(t *testing.T) {
+	tests := []struct {
+		method  func(CallContext, ...RubyObject) (RubyObject, error)
+		subject string
+		args    []RubyObject
+		result  RubyObject
+	}{
+		{stringSub, "hello", []RubyObject{mustRegexp(t, `l`, ""), &String{Value: "L"}}, &String{Value: "heLlo"}},
+		{stringGsub, "hello", []RubyObject{mustRegexp(t, `l`, ""), &String{Value: "L"}}, &String{Value: "heLLo"}},
+		{stringGsub, "a.b", []RubyObject{&String{Value: "."}, &String{Value: "-"}}, &String{Value: "a-b"}},
+		{stringSub, "hello", []RubyObject{mustRegexp(t, `(e)(l)`, ""), &String{Value: `\2\1`}}, &String{Value: "hlelo"}},
+		{stringSub, "hello", []RubyObject{mustRegexp(t, `ll`, ""), &String{Value: `[\&|\0|\\]`}}, &String{Value: `he[ll|ll|\]o`}},
+		{stringSub, "hello", []RubyObject{mustRegexp(t, `ll`, ""), &String{Value: "<\\`\\'>"}}, &String{Value: "he<heo>o"}},
+		{stringGsub, "ab", []RubyObject{mustRegexp(t, `(?<x>\w)`, ""), &String{Value: `\k<x>\k<x>`}}, &String{Value: "aabb"}},
+	}
+
+	for _, tt := range tests {
+		context := &callContext{receiver: &String{Value: tt.subject}}
+
+		result, err := tt.method(context, tt.args...)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, tt.result)
+	}
+}
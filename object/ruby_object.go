@@ -3,9 +3,11 @@ package object
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/goruby/goruby/ast"
+	"github.com/pkg/errors"
 )
 
 // Type represents a type of an object
@@ -21,7 +23,13 @@ const (
 	CLASS_INSTANCE_OBJ Type = "CLASS"
 	ARRAY_OBJ          Type = "ARRAY"
 	HASH_OBJ           Type = "HASH"
+	RANGE_OBJ          Type = "RANGE"
+	REGEXP_OBJ         Type = "REGEXP"
+	MATCH_DATA_OBJ     Type = "MATCH_DATA"
+	METHOD_OBJ         Type = "METHOD"
+	STRING_IO_OBJ      Type = "STRING_IO"
 	INTEGER_OBJ        Type = "INTEGER"
+	FLOAT_OBJ          Type = "FLOAT"
 	STRING_OBJ         Type = "STRING"
 	SYMBOL_OBJ         Type = "SYMBOL"
 	BOOLEAN_OBJ        Type = "BOOLEAN"
@@ -63,6 +71,8 @@ type hashable interface {
 
 type extendable interface {
 	addMethod(name string, method RubyMethod)
+	// methodClass returns the class holding the methods added via addMethod
+	methodClass() RubyClass
 }
 
 type extendableRubyObject interface {
@@ -97,54 +107,57 @@ func (rv *ReturnValue) Class() RubyClass { return rv.Value.Class() }
 
 type functionParameters []*FunctionParameter
 
-func (f functionParameters) defaultParamCount() int {
-	count := 0
+// positionalArity returns the number of required and optional positional
+// parameters and whether there is a rest parameter
+func (f functionParameters) positionalArity() (required, optional int, rest bool) {
 	for _, p := range f {
-		if p.Default != nil {
-			count++
+		switch {
+		case p.Kind == ast.SplatParameter:
+			rest = true
+		case p.Kind != ast.PositionalParameter:
+		case p.Default != nil:
+			optional++
+		default:
+			required++
 		}
 	}
-	return count
+	return required, optional, rest
 }
-func (f functionParameters) mandatoryParams() []*FunctionParameter {
-	params := make([]*FunctionParameter, 0)
-	for _, p := range f {
-		if p.Default == nil {
-			params = append(params, p)
-		}
-	}
-	return params
-}
-func (f functionParameters) optionalParams() []*FunctionParameter {
-	params := make([]*FunctionParameter, 0)
-	for _, p := range f {
-		if p.Default != nil {
-			params = append(params, p)
-		}
-	}
-	return params
-}
-func (f functionParameters) separateDefaultParams() ([]*FunctionParameter, []*FunctionParameter) {
-	mandatory, defaults := make([]*FunctionParameter, 0), make([]*FunctionParameter, 0)
+
+// acceptsKeywords reports whether there are keyword or double splat parameters
+func (f functionParameters) acceptsKeywords() bool {
 	for _, p := range f {
-		if p.Default != nil {
-			defaults = append(defaults, p)
-		} else {
-			mandatory = append(mandatory, p)
+		if p.Kind == ast.KeywordParameter || p.Kind == ast.DoubleSplatParameter {
+			return true
 		}
 	}
-	return mandatory, defaults
+	return false
 }
 
 // FunctionParameter represents a parameter within a function
 type FunctionParameter struct {
 	Name    string
 	Default RubyObject
+	Kind    ast.ParameterKind
 }
 
 func (f *FunctionParameter) String() string {
 	var out bytes.Buffer
+	switch f.Kind {
+	case ast.SplatParameter:
+		out.WriteString("*")
+	case ast.DoubleSplatParameter:
+		out.WriteString("**")
+	}
 	out.WriteString(f.Name)
+	if f.Kind == ast.KeywordParameter {
+		out.WriteString(":")
+		if f.Default != nil {
+			out.WriteString(" ")
+			out.WriteString(f.Default.Inspect())
+		}
+		return out.String()
+	}
 	if f.Default != nil {
 		out.WriteString(" = ")
 		out.WriteString(f.Default.Inspect())
@@ -154,7 +167,9 @@ func (f *FunctionParameter) String() string {
 
 // A Function represents a user defined function. It is no real Ruby object.
 type Function struct {
+	Name             string
 	Parameters       []*FunctionParameter
+	BlockParameter   string // the name of the explicit block parameter, if any
 	Body             *ast.BlockStatement
 	Env              Environment
 	MethodVisibility MethodVisibility
@@ -167,6 +182,9 @@ func (f *Function) String() string {
 	for _, p := range f.Parameters {
 		params = append(params, p.String())
 	}
+	if f.BlockParameter != "" {
+		params = append(params, "&"+f.BlockParameter)
+	}
 	out.WriteString("fn")
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
@@ -179,20 +197,30 @@ func (f *Function) String() string {
 // Call implements the RubyMethod interface. It evaluates f.Body and returns its result
 func (f *Function) Call(context CallContext, args ...RubyObject) (RubyObject, error) {
 	block, arguments, _ := extractBlockFromArgs(args)
-	defaultParams := functionParameters(f.Parameters).defaultParamCount()
-	if len(arguments) < len(f.Parameters)-defaultParams || len(arguments) > len(f.Parameters) {
-		return nil, NewWrongNumberOfArgumentsError(len(f.Parameters), len(arguments))
-	}
 	params, err := f.populateParameters(arguments)
 	if err != nil {
 		return nil, err
 	}
-	contextSelf, _ := context.Env().Get("self")
-	contextSelfObject := contextSelf.(*Self)
-	extendedEnv := f.extendFunctionEnv(contextSelfObject, params, block)
+	if f.BlockParameter != "" {
+		params[f.BlockParameter] = NIL
+		if block != nil {
+			params[f.BlockParameter] = block
+		}
+	}
+	receiver, ok := context.Receiver().(*Self)
+	if !ok {
+		receiver = &Self{RubyObject: context.Receiver(), Name: context.Receiver().Inspect()}
+	}
+	extendedEnv := f.extendFunctionEnv(receiver, params, block)
+	frame, _ := extendedEnv.Get("self")
 	evaluated, err := context.Eval(f.Body, extendedEnv)
+	frame.(*Self).returned = true
+	if ret, ok := errors.Cause(err).(*ReturnError); ok && ret.Frame == frame {
+		// return issued within a proc created in this method call
+		return ret.Value, nil
+	}
 	if err != nil {
-		return nil, err
+		return nil, LocalJump(err, false)
 	}
 	return f.unwrapReturnValue(evaluated), nil
 }
@@ -203,41 +231,139 @@ func (f *Function) Visibility() MethodVisibility {
 }
 
 func (f *Function) populateParameters(args []RubyObject) (map[string]RubyObject, error) {
-	if len(args) > len(f.Parameters) {
-		return nil, NewWrongNumberOfArgumentsError(len(f.Parameters), len(args))
-	}
 	params := make(map[string]RubyObject)
+	required, optional, rest := functionParameters(f.Parameters).positionalArity()
+
+	// a trailing hash provides the keyword arguments if the function
+	// accepts keywords and the hash is not needed as positional argument
+	keywords := &Hash{}
+	if functionParameters(f.Parameters).acceptsKeywords() && len(args) > required {
+		if hash, ok := args[len(args)-1].(*Hash); ok {
+			keywords = hash
+			args = args[:len(args)-1]
+		}
+	}
 
-	mandatory, defaults := functionParameters(f.Parameters).separateDefaultParams()
-
-	if len(args) < len(mandatory)-len(defaults) || len(args) > len(f.Parameters) {
-		return nil, NewWrongNumberOfArgumentsError(len(f.Parameters), len(args))
+	if err := checkArity(required, optional, rest, len(args)); err != nil {
+		return nil, err
 	}
 
-	if len(args) == len(f.Parameters) {
-		for paramIdx, param := range f.Parameters {
-			params[param.Name] = args[paramIdx]
+	optionalArgs := len(args) - required
+	if optionalArgs > optional {
+		optionalArgs = optional
+	}
+	restArgs := len(args) - required - optionalArgs
+
+	argIdx := 0
+	for _, param := range f.Parameters {
+		switch {
+		case param.Kind == ast.SplatParameter:
+			params[param.Name] = NewArray(args[argIdx : argIdx+restArgs]...)
+			argIdx += restArgs
+		case param.Kind != ast.PositionalParameter:
+		case param.Default != nil && optionalArgs == 0:
+			params[param.Name] = param.Default
+		default:
+			if param.Default != nil {
+				optionalArgs--
+			}
+			params[param.Name] = args[argIdx]
+			argIdx++
 		}
-		return params, nil
 	}
 
-	parameters := append(mandatory, defaults...)
+	return params, f.populateKeywordParameters(params, keywords)
+}
 
-	for paramIdx, param := range parameters {
-		if paramIdx >= len(args) {
-			params[param.Name] = param.Default
-			continue
+func (f *Function) populateKeywordParameters(params map[string]RubyObject, keywords *Hash) error {
+	remaining := make(map[string]RubyObject)
+	for key, value := range keywords.Map() {
+		remaining[key.Inspect()] = value
+	}
+	var missing []string
+	var doubleSplat *FunctionParameter
+	for _, param := range f.Parameters {
+		switch param.Kind {
+		case ast.KeywordParameter:
+			key := (&Symbol{Value: param.Name}).Inspect()
+			value, ok := remaining[key]
+			switch {
+			case ok:
+				params[param.Name] = value
+				delete(remaining, key)
+			case param.Default != nil:
+				params[param.Name] = param.Default
+			default:
+				missing = append(missing, key)
+			}
+		case ast.DoubleSplatParameter:
+			doubleSplat = param
+		}
+	}
+	if len(missing) != 0 {
+		return newMissingKeywordsError(missing)
+	}
+	if doubleSplat != nil {
+		params[doubleSplat.Name] = remainingKeywords(keywords, remaining)
+		return nil
+	}
+	if len(remaining) != 0 {
+		return newUnknownKeywordsError(remaining)
+	}
+	return nil
+}
+
+// remainingKeywords returns a hash of the keyword arguments whose inspected
+// keys are left within remaining
+func remainingKeywords(keywords *Hash, remaining map[string]RubyObject) *Hash {
+	options := &Hash{}
+	for key, value := range keywords.Map() {
+		if _, ok := remaining[key.Inspect()]; ok {
+			options.Set(key, value)
 		}
-		params[param.Name] = args[paramIdx]
 	}
-	return params, nil
+	return options
+}
+
+func newMissingKeywordsError(missing []string) error {
+	return NewArgumentError("missing %s: %s", pluralize("keyword", len(missing)), strings.Join(missing, ", "))
+}
+
+func newUnknownKeywordsError(remaining map[string]RubyObject) error {
+	unknown := make([]string, 0, len(remaining))
+	for key := range remaining {
+		unknown = append(unknown, key)
+	}
+	sort.Strings(unknown)
+	return NewArgumentError("unknown %s: %s", pluralize("keyword", len(unknown)), strings.Join(unknown, ", "))
+}
+
+// checkArity returns an ArgumentError if the given number of arguments does
+// not fit to the number of required and optional parameters
+func checkArity(required, optional int, rest bool, given int) error {
+	if given >= required && (rest || given <= required+optional) {
+		return nil
+	}
+	if optional == 0 && !rest {
+		return NewWrongNumberOfArgumentsError(required, given)
+	}
+	return NewWrongNumberOfArgumentsRangeError(required, required+optional, rest, given)
+}
+
+func pluralize(word string, count int) string {
+	if count == 1 {
+		return word
+	}
+	return word + "s"
 }
 
 func (f *Function) extendFunctionEnv(context *Self, params map[string]RubyObject, block *Proc) Environment {
 	// encapsulate the block within a new self, but with the same object
-	funcSelf := &Self{RubyObject: context.RubyObject, Name: context.Name, Block: block}
+	funcSelf := &Self{RubyObject: context.RubyObject, Name: context.Name, Block: block, Method: f}
 	env := NewEnclosedEnvironment(f.Env)
 	env.Set("self", funcSelf)
+	// every method call starts with its own last match
+	env.Set("$~", NIL)
 	for k, v := range params {
 		env.Set(k, v)
 	}
@@ -255,9 +381,11 @@ func (f *Function) unwrapReturnValue(obj RubyObject) RubyObject {
 // the RubyObject and is just meant to indicate that the given object is
 // self in the given context.
 type Self struct {
-	RubyObject        // The encapsuled object acting as self
-	Block      *Proc  // the block given to the current execution binding
-	Name       string // The name of self in this context
+	RubyObject           // The encapsuled object acting as self
+	Block      *Proc     // the block given to the current execution binding
+	Method     *Function // the method of the current execution binding, if any
+	Name       string    // The name of self in this context
+	returned   bool      // whether the method call of this binding has returned
 }
 
 // Type returns SELF
@@ -277,3 +405,4 @@ func (e *extendedObject) Class() RubyClass { return e.class }
 func (e *extendedObject) addMethod(name string, method RubyMethod) {
 	e.class.addMethod(name, method)
 }
+func (e *extendedObject) methodClass() RubyClass { return e.class }
@@ -31,7 +31,8 @@ func TestFunctionCall(t *testing.T) {
 
 		var actualEvalNode ast.Node
 		context := &callContext{
-			env: NewMainEnvironment(),
+			env:      NewMainEnvironment(),
+			receiver: &Self{RubyObject: mainObject, Name: "main"},
 			eval: func(node ast.Node, env Environment) (RubyObject, error) {
 				actualEvalNode = node
 				return nil, nil
@@ -50,8 +51,9 @@ func TestFunctionCall(t *testing.T) {
 		evalErr := fmt.Errorf("An error")
 
 		context := &callContext{
-			env:  NewMainEnvironment(),
-			eval: func(ast.Node, Environment) (RubyObject, error) { return nil, evalErr },
+			env:      NewMainEnvironment(),
+			receiver: &Self{RubyObject: mainObject, Name: "main"},
+			eval:     func(ast.Node, Environment) (RubyObject, error) { return nil, evalErr },
 		}
 
 		function := &Function{
@@ -71,7 +73,8 @@ func TestFunctionCall(t *testing.T) {
 		contextEnv.Set("bar", &String{Value: "not reachable in Eval"})
 		var evalEnv Environment
 		context := &callContext{
-			env: contextEnv,
+			env:      contextEnv,
+			receiver: &Self{RubyObject: &Integer{Value: 42}, Name: "context self"},
 			eval: func(node ast.Node, env Environment) (RubyObject, error) {
 				evalEnv = env
 				return nil, nil
@@ -109,7 +112,7 @@ func TestFunctionCall(t *testing.T) {
 		}
 
 		{
-			expected := &Self{RubyObject: &Integer{Value: 42}, Name: "context self"}
+			expected := &Self{RubyObject: &Integer{Value: 42}, Name: "context self", Method: function, returned: true}
 			actual, _ := evalEnv.Get("self")
 			if !reflect.DeepEqual(expected, actual) {
 				t.Logf("Expected Eval env self to equal\n%+v\n\tgot\n%+v\n", expected, actual)
@@ -126,7 +129,8 @@ func TestFunctionCall(t *testing.T) {
 		contextEnv.Set("self", &Self{RubyObject: &Integer{Value: 42}, Name: "context self"})
 		var evalEnv Environment
 		context := &callContext{
-			env: contextEnv,
+			env:      contextEnv,
+			receiver: &Self{RubyObject: &Integer{Value: 42}, Name: "context self"},
 			eval: func(node ast.Node, env Environment) (RubyObject, error) {
 				evalEnv = env
 				return nil, nil
@@ -173,7 +177,6 @@ func TestFunctionCall(t *testing.T) {
 			}
 		})
 		t.Run("with default params", func(t *testing.T) {
-			t.Skip()
 			function := &Function{
 				Parameters: []*FunctionParameter{
 					&FunctionParameter{Name: "foo", Default: &Integer{Value: 12}},
@@ -231,8 +234,9 @@ func TestFunctionCall(t *testing.T) {
 	t.Run("returns the object returned by CallContext#Eval", func(t *testing.T) {
 		t.Run("vanilla object", func(t *testing.T) {
 			context := &callContext{
-				env:  NewMainEnvironment(),
-				eval: func(ast.Node, Environment) (RubyObject, error) { return &Integer{Value: 8}, nil },
+				env:      NewMainEnvironment(),
+				receiver: &Self{RubyObject: mainObject, Name: "main"},
+				eval:     func(ast.Node, Environment) (RubyObject, error) { return &Integer{Value: 8}, nil },
 			}
 
 			function := &Function{}
@@ -248,8 +252,9 @@ func TestFunctionCall(t *testing.T) {
 		})
 		t.Run("wrapped into a return value", func(t *testing.T) {
 			context := &callContext{
-				env:  NewMainEnvironment(),
-				eval: func(ast.Node, Environment) (RubyObject, error) { return &ReturnValue{Value: &Integer{Value: 8}}, nil },
+				env:      NewMainEnvironment(),
+				receiver: &Self{RubyObject: mainObject, Name: "main"},
+				eval:     func(ast.Node, Environment) (RubyObject, error) { return &ReturnValue{Value: &Integer{Value: 8}}, nil },
 			}
 
 			function := &Function{}
@@ -270,7 +275,8 @@ func TestFunctionCall(t *testing.T) {
 
 		var evalEnv Environment
 		context := &callContext{
-			env: contextEnv,
+			env:      contextEnv,
+			receiver: &Self{RubyObject: &Integer{Value: 42}, Name: "context self"},
 			eval: func(node ast.Node, env Environment) (RubyObject, error) {
 				evalEnv = env
 				return nil, nil
@@ -283,10 +289,10 @@ func TestFunctionCall(t *testing.T) {
 			},
 		}
 
-		mustCall(function.Call(context, &String{Value: "the x value"}, &Proc{}))
+		mustCall(function.Call(context, &String{Value: "the x value"}, NewBlockArgument(&Proc{})))
 
 		actual := len(evalEnv.GetAll())
-		expected := 2 // `self` and `x`
+		expected := 3 // `self`, `$~` and `x`
 
 		if !reflect.DeepEqual(expected, actual) {
 			t.Logf("Expected Eval env to have %d items, got %d\n", expected, actual)
@@ -299,7 +305,8 @@ func TestFunctionCall(t *testing.T) {
 
 		var evalEnv Environment
 		context := &callContext{
-			env: contextEnv,
+			env:      contextEnv,
+			receiver: &Self{RubyObject: &Integer{Value: 42}, Name: "context self"},
 			eval: func(node ast.Node, env Environment) (RubyObject, error) {
 				evalEnv = env
 				return nil, nil
@@ -310,7 +317,7 @@ func TestFunctionCall(t *testing.T) {
 			Parameters: []*FunctionParameter{},
 		}
 
-		mustCall(function.Call(context, &Proc{ArgumentCountMandatory: true}))
+		mustCall(function.Call(context, NewBlockArgument(&Proc{ArgumentCountMandatory: true})))
 
 		expected := &Proc{ArgumentCountMandatory: true}
 		envSelf, _ := evalEnv.Get("self")
@@ -322,8 +329,9 @@ func TestFunctionCall(t *testing.T) {
 	})
 	t.Run("validates that the arguments match the function parameters", func(t *testing.T) {
 		context := &callContext{
-			env:  NewMainEnvironment(),
-			eval: func(ast.Node, Environment) (RubyObject, error) { return nil, nil },
+			env:      NewMainEnvironment(),
+			receiver: &Self{RubyObject: mainObject, Name: "main"},
+			eval:     func(ast.Node, Environment) (RubyObject, error) { return nil, nil },
 		}
 
 		function := &Function{
@@ -342,7 +350,7 @@ func TestFunctionCall(t *testing.T) {
 		})
 
 		t.Run("with block argument", func(t *testing.T) {
-			_, err := function.Call(context, &Proc{})
+			_, err := function.Call(context, NewBlockArgument(&Proc{}))
 
 			if err != nil {
 				t.Logf("Expected no error, got %T:%v\n", err, err)
@@ -365,3 +373,98 @@ func TestFunctionCall(t *testing.T) {
 		})
 	})
 }
+
+func TestFunctionPopulateParameters(t *testing.T) {
+	keywords := func(pairs ...RubyObject) *Hash {
+		hash := &Hash{}
+		for i := 0; i < len(pairs); i += 2 {
+			hash.Set(pairs[i], pairs[i+1])
+		}
+		return hash
+	}
+	tests := []struct {
+		parameters []*FunctionParameter
+		args       []RubyObject
+		result     map[string]RubyObject
+		err        error
+	}{
+		{
+			[]*FunctionParameter{
+				{Name: "a"},
+				{Name: "b", Kind: ast.SplatParameter},
+				{Name: "c"},
+			},
+			[]RubyObject{NewInteger(1), NewInteger(2), NewInteger(3), NewInteger(4)},
+			map[string]RubyObject{"a": NewInteger(1), "b": NewArray(NewInteger(2), NewInteger(3)), "c": NewInteger(4)},
+			nil,
+		},
+		{
+			[]*FunctionParameter{
+				{Name: "a", Default: NewInteger(1)},
+				{Name: "b", Default: NewInteger(2)},
+				{Name: "c"},
+			},
+			[]RubyObject{NewInteger(5), NewInteger(6)},
+			map[string]RubyObject{"a": NewInteger(5), "b": NewInteger(2), "c": NewInteger(6)},
+			nil,
+		},
+		{
+			[]*FunctionParameter{
+				{Name: "a", Kind: ast.KeywordParameter},
+				{Name: "b", Default: NewInteger(2), Kind: ast.KeywordParameter},
+				{Name: "c", Kind: ast.DoubleSplatParameter},
+			},
+			[]RubyObject{keywords(&Symbol{Value: "a"}, NewInteger(1), &Symbol{Value: "d"}, NewInteger(4))},
+			map[string]RubyObject{"a": NewInteger(1), "b": NewInteger(2), "c": keywords(&Symbol{Value: "d"}, NewInteger(4))},
+			nil,
+		},
+		{
+			[]*FunctionParameter{
+				{Name: "a"},
+				{Name: "b", Default: NIL, Kind: ast.KeywordParameter},
+			},
+			[]RubyObject{keywords(&Symbol{Value: "b"}, NewInteger(1))},
+			map[string]RubyObject{"a": keywords(&Symbol{Value: "b"}, NewInteger(1)), "b": NIL},
+			nil,
+		},
+		{
+			[]*FunctionParameter{
+				{Name: "a"},
+				{Name: "b", Default: NewInteger(2)},
+			},
+			[]RubyObject{},
+			nil,
+			NewWrongNumberOfArgumentsRangeError(1, 2, false, 0),
+		},
+		{
+			[]*FunctionParameter{
+				{Name: "a", Kind: ast.KeywordParameter},
+				{Name: "b", Kind: ast.KeywordParameter},
+			},
+			[]RubyObject{keywords(&Symbol{Value: "b"}, NewInteger(1))},
+			nil,
+			NewArgumentError("missing keyword: :a"),
+		},
+		{
+			[]*FunctionParameter{
+				{Name: "a", Default: NIL, Kind: ast.KeywordParameter},
+			},
+			[]RubyObject{keywords(&Symbol{Value: "c"}, NewInteger(1), &Symbol{Value: "b"}, NewInteger(2))},
+			nil,
+			NewArgumentError("unknown keywords: :b, :c"),
+		},
+	}
+
+	for _, tt := range tests {
+		function := &Function{Parameters: tt.parameters}
+
+		result, err := function.populateParameters(tt.args)
+
+		checkError(t, err, tt.err)
+
+		if !reflect.DeepEqual(tt.result, result) && tt.err == nil {
+			t.Logf("Expected parameters to equal\n%v\n\tgot\n%v\n", tt.result, result)
+			t.Fail()
+		}
+	}
+}
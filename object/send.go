@@ -7,16 +7,7 @@ import (
 // Send sends message method with args to context and returns its result
 func Send(context CallContext, method string, args ...RubyObject) (RubyObject, error) {
 	receiver := context.Receiver()
-	class := receiver.Class()
-
-	// search for the method in the ancestry tree
-	for class != nil {
-		fn, ok := class.Methods().Get(method)
-		if !ok {
-			class = class.SuperClass()
-			continue
-		}
-
+	if fn, ok := findMethod(receiver.Class(), method); ok {
 		if fn.Visibility() == PRIVATE_METHOD && receiver.Type() != SELF {
 			return nil, errors.WithStack(NewPrivateNoMethodError(receiver, method))
 		}
@@ -32,8 +23,118 @@ func Send(context CallContext, method string, args ...RubyObject) (RubyObject, e
 	return methodMissing(context, methodMissingArgs...)
 }
 
+// RespondTo reports whether obj has a public method name
+func RespondTo(obj RubyObject, name string) bool {
+	fn, ok := findMethod(obj.Class(), name)
+	return ok && fn.Visibility() == PUBLIC_METHOD
+}
+
+// Super sends the message current.Name with args to context like Send does, but
+// starts the method lookup after the class or module current is defined in.
+func Super(context CallContext, current *Function, args ...RubyObject) (RubyObject, error) {
+	receiver := context.Receiver()
+	found := false
+	for _, methods := range ancestorMethodSets(receiver.Class()) {
+		fn, ok := methods.Get(current.Name)
+		if !ok {
+			continue
+		}
+		if isUndefined(fn) {
+			if found {
+				break
+			}
+			continue
+		}
+		if found {
+			return fn.Call(context, args...)
+		}
+		found = fn == RubyMethod(current)
+	}
+	return nil, errors.WithStack(NewNoSuperMethodError(receiver, current.Name))
+}
+
+// findMethod searches the method name in the ancestry tree of class. The
+// search stops at the first class defining name, or at a class undefining it.
+func findMethod(class RubyClass, name string) (RubyMethod, bool) {
+	for ; class != nil; class = class.SuperClass() {
+		fn, ok := class.Methods().Get(name)
+		if !ok {
+			continue
+		}
+		if isUndefined(fn) {
+			return nil, false
+		}
+		return fn, true
+	}
+	return nil, false
+}
+
+// ancestorMethodSets returns the method sets of class and all of its ancestors
+// in the order they are searched for methods. The modules of a mixin are
+// searched after the mixed class, the last module first.
+func ancestorMethodSets(class RubyClass) []MethodSet {
+	var sets []MethodSet
+	for ; class != nil; class = class.SuperClass() {
+		mixin, ok := class.(*mixin)
+		if !ok {
+			sets = append(sets, class.Methods())
+			continue
+		}
+		sets = append(sets, mixin.RubyClassObject.Methods())
+		for i := len(mixin.modules) - 1; i >= 0; i-- {
+			sets = append(sets, mixin.modules[i].Class().Methods())
+		}
+	}
+	return sets
+}
+
 // AddMethod adds a method to a given object. It returns the object with the modified method set
 func AddMethod(context RubyObject, methodName string, method *Function) RubyObject {
+	extended, result := extend(context)
+	extended.addMethod(methodName, method)
+	return result
+}
+
+// AliasMethod defines newName as a copy of the method oldName of context.
+// It returns the object with the modified method set, like AddMethod.
+func AliasMethod(context RubyObject, newName, oldName string) (RubyObject, error) {
+	extended, result := extend(context)
+	class := extended.methodClass()
+	fn, ok := findMethod(class, oldName)
+	if !ok {
+		return nil, errors.WithStack(NewUndefinedMethodNameError(extended, oldName))
+	}
+	extended.addMethod(newName, fn)
+	return result, nil
+}
+
+// UndefineMethod prevents context from responding to methodName, even if an
+// ancestor defines it. It returns the object with the modified method set.
+func UndefineMethod(context RubyObject, methodName string) (RubyObject, error) {
+	extended, result := extend(context)
+	class := extended.methodClass()
+	if _, ok := findMethod(class, methodName); !ok {
+		return nil, errors.WithStack(NewUndefinedMethodNameError(extended, methodName))
+	}
+	class.Methods().(SettableMethodSet).Undefine(methodName)
+	return result, nil
+}
+
+// RemoveMethod removes the method methodName defined directly in context.
+// Methods of the same name defined in ancestors are left untouched.
+func RemoveMethod(context RubyObject, methodName string) (RubyObject, error) {
+	extended, result := extend(context)
+	class := extended.methodClass()
+	if !class.Methods().(SettableMethodSet).Remove(methodName) {
+		return nil, errors.WithStack(NewMethodNotDefinedNameError(extended, methodName))
+	}
+	return result, nil
+}
+
+// extend returns the object methods defined within context are added to,
+// wrapping context into an extended object if it can't hold methods itself.
+// The second return value is the object to replace context with.
+func extend(context RubyObject) (extendableRubyObject, RubyObject) {
 	objectToExtend := context
 	self, contextIsSelf := context.(*Self)
 	if contextIsSelf {
@@ -47,24 +148,15 @@ func AddMethod(context RubyObject, methodName string, method *Function) RubyObje
 			Environment: NewEnvironment(),
 		}
 	}
-	extended.addMethod(methodName, method)
 	if contextIsSelf {
 		self.RubyObject = extended
-		return self
+		return extended, self
 	}
-	return extended
+	return extended, extended
 }
 
 func methodMissing(context CallContext, args ...RubyObject) (RubyObject, error) {
-	class := context.Receiver().Class()
-
-	// search for method_missing in the ancestry tree
-	for class != nil {
-		fn, ok := class.Methods().Get("method_missing")
-		if !ok {
-			class = class.SuperClass()
-			continue
-		}
+	if fn, ok := findMethod(context.Receiver().Class(), "method_missing"); ok {
 		return fn.Call(context, args...)
 	}
 	return nil, NewNoMethodError(context.Receiver(), args[0].(*Symbol).Value)
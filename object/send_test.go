@@ -402,3 +402,172 @@ func TestAddMethod(t *testing.T) {
 		}
 	})
 }
+
+func TestSuper(t *testing.T) {
+	returning := func(value string) RubyMethod {
+		return publicMethod(func(context CallContext, args ...RubyObject) (RubyObject, error) {
+			return &Symbol{Value: value}, nil
+		})
+	}
+	current := &Function{Name: "foo"}
+	newReceiver := func(superMethods, moduleMethods map[string]RubyMethod) RubyObject {
+		superClass := newMixin(
+			&class{
+				name:            "super class",
+				instanceMethods: NewMethodSet(superMethods),
+				superClass:      basicObjectClass,
+			},
+			newModule("M", moduleMethods, nil),
+		)
+		return &testRubyObject{
+			class: &class{
+				name:            "base class",
+				instanceMethods: NewMethodSet(map[string]RubyMethod{"foo": current, "bar": returning("bar")}),
+				superClass:      superClass,
+			},
+		}
+	}
+
+	withoutSuper := newReceiver(nil, nil)
+
+	tests := []struct {
+		receiver RubyObject
+		result   RubyObject
+		err      error
+	}{
+		{
+			newReceiver(map[string]RubyMethod{"foo": returning("super")}, map[string]RubyMethod{"foo": returning("module")}),
+			&Symbol{Value: "super"},
+			nil,
+		},
+		{
+			newReceiver(nil, map[string]RubyMethod{"foo": returning("module")}),
+			&Symbol{Value: "module"},
+			nil,
+		},
+		{
+			withoutSuper,
+			nil,
+			NewNoSuperMethodError(withoutSuper, "foo"),
+		},
+		{
+			newReceiver(map[string]RubyMethod{"foo": &tombstone{name: "foo"}}, map[string]RubyMethod{"foo": returning("module")}),
+			nil,
+			NewNoSuperMethodError(withoutSuper, "foo"),
+		},
+	}
+
+	for _, tt := range tests {
+		context := &callContext{receiver: tt.receiver}
+
+		result, err := Super(context, current)
+
+		checkError(t, errors.Cause(err), tt.err)
+
+		checkResult(t, result, tt.result)
+	}
+}
+
+func TestAliasMethod(t *testing.T) {
+	superClass := &class{
+		name: "Super",
+		instanceMethods: NewMethodSet(map[string]RubyMethod{
+			"foo": publicMethod(func(context CallContext, args ...RubyObject) (RubyObject, error) {
+				return &Symbol{Value: "foo"}, nil
+			}),
+			"bar": publicMethod(func(context CallContext, args ...RubyObject) (RubyObject, error) {
+				return &Symbol{Value: "bar"}, nil
+			}),
+		}),
+		superClass: basicObjectClass,
+	}
+	context := &class{
+		name: "Base",
+		instanceMethods: NewMethodSet(map[string]RubyMethod{
+			"bar": &tombstone{name: "bar"},
+		}),
+		superClass: superClass,
+	}
+
+	t.Run("inherited method", func(t *testing.T) {
+		_, err := AliasMethod(context, "qux", "foo")
+
+		checkError(t, err, nil)
+
+		result, err := Send(&callContext{receiver: &testRubyObject{class: context}}, "qux")
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, &Symbol{Value: "foo"})
+	})
+	t.Run("undefined method", func(t *testing.T) {
+		_, err := AliasMethod(context, "qux", "bar")
+
+		checkError(t, errors.Cause(err), NewUndefinedMethodNameError(context, "bar"))
+	})
+}
+
+func TestUndefineMethod(t *testing.T) {
+	superClass := &class{
+		name: "Super",
+		instanceMethods: NewMethodSet(map[string]RubyMethod{
+			"foo": publicMethod(func(context CallContext, args ...RubyObject) (RubyObject, error) {
+				return TRUE, nil
+			}),
+		}),
+		superClass: basicObjectClass,
+	}
+	context := &class{
+		name:            "Base",
+		instanceMethods: NewMethodSet(map[string]RubyMethod{}),
+		superClass:      superClass,
+	}
+	receiver := &testRubyObject{class: context}
+
+	_, err := UndefineMethod(context, "foo")
+
+	checkError(t, err, nil)
+
+	_, err = Send(&callContext{receiver: receiver}, "foo")
+
+	checkError(t, errors.Cause(err), NewNoMethodError(receiver, "foo"))
+
+	_, err = UndefineMethod(context, "foo")
+
+	checkError(t, errors.Cause(err), NewUndefinedMethodNameError(context, "foo"))
+}
+
+func TestRemoveMethod(t *testing.T) {
+	superClass := &class{
+		name: "Super",
+		instanceMethods: NewMethodSet(map[string]RubyMethod{
+			"foo": publicMethod(func(context CallContext, args ...RubyObject) (RubyObject, error) {
+				return &Symbol{Value: "super"}, nil
+			}),
+		}),
+		superClass: basicObjectClass,
+	}
+	context := &class{
+		name: "Base",
+		instanceMethods: NewMethodSet(map[string]RubyMethod{
+			"foo": publicMethod(func(context CallContext, args ...RubyObject) (RubyObject, error) {
+				return &Symbol{Value: "base"}, nil
+			}),
+		}),
+		superClass: superClass,
+	}
+
+	_, err := RemoveMethod(context, "foo")
+
+	checkError(t, err, nil)
+
+	result, err := Send(&callContext{receiver: &testRubyObject{class: context}}, "foo")
+
+	checkError(t, err, nil)
+
+	checkResult(t, result, &Symbol{Value: "super"})
+
+	_, err = RemoveMethod(context, "foo")
+
+	checkError(t, errors.Cause(err), NewMethodNotDefinedNameError(context, "foo"))
+}
@@ -3,6 +3,7 @@ package object
 import (
 	"fmt"
 	"hash/fnv"
+	"strings"
 )
 
 var stringClass RubyClassObject = newClass(
@@ -70,6 +71,14 @@ var stringMethods = map[string]RubyMethod{
 	"initialize": privateMethod(stringInitialize),
 	"to_s":       withArity(0, publicMethod(stringToS)),
 	"+":          withArity(1, publicMethod(stringAdd)),
+	"==":         withArity(1, publicMethod(stringEq)),
+	"=~":         withArity(1, publicMethod(stringMatchOperator)),
+	"match":      publicMethod(stringMatch),
+	"match?":     withArity(1, publicMethod(stringIsMatch)),
+	"scan":       publicMethod(stringScan),
+	"sub":        publicMethod(stringSub),
+	"gsub":       publicMethod(stringGsub),
+	"upcase":     withArity(0, publicMethod(stringUpcase)),
 }
 
 func stringInitialize(context CallContext, args ...RubyObject) (RubyObject, error) {
@@ -95,6 +104,11 @@ func stringToS(context CallContext, args ...RubyObject) (RubyObject, error) {
 	return &String{str.Value}, nil
 }
 
+func stringUpcase(context CallContext, args ...RubyObject) (RubyObject, error) {
+	str := context.Receiver().(*String)
+	return &String{strings.ToUpper(str.Value)}, nil
+}
+
 func stringAdd(context CallContext, args ...RubyObject) (RubyObject, error) {
 	s := context.Receiver().(*String)
 	add, ok := args[0].(*String)
@@ -103,3 +117,178 @@ func stringAdd(context CallContext, args ...RubyObject) (RubyObject, error) {
 	}
 	return &String{s.Value + add.Value}, nil
 }
+
+func stringMatchOperator(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if _, ok := args[0].(*String); ok {
+		return nil, NewTypeError("wrong argument type String (expected Regexp)")
+	}
+	return Send(NewCallContext(context.Env(), args[0]), "=~", context.Receiver())
+}
+
+func stringMatch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	re, err := regexpArgument(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return regexpMatch(NewCallContext(context.Env(), re), append([]RubyObject{context.Receiver()}, args[1:]...)...)
+}
+
+func stringIsMatch(context CallContext, args ...RubyObject) (RubyObject, error) {
+	re, err := regexpArgument(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return regexpIsMatch(NewCallContext(context.Env(), re), context.Receiver())
+}
+
+func stringScan(context CallContext, args ...RubyObject) (RubyObject, error) {
+	str := context.Receiver().(*String)
+	block, args, blockGiven := extractBlockFromArgs(args)
+	if len(args) != 1 {
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+	re, err := regexpArgument(args[0])
+	if err != nil {
+		return nil, err
+	}
+	results := []RubyObject{}
+	var last RubyObject = NIL
+	for _, loc := range re.Regexp.FindAllStringSubmatchIndex(str.Value, -1) {
+		match := &MatchData{Regexp: re, Subject: str.Value, Offsets: loc}
+		last = match
+		var result RubyObject = match.Group(0)
+		if match.size() > 1 {
+			captures, _ := matchDataCaptures(NewCallContext(context.Env(), match))
+			result = captures
+		}
+		if !blockGiven {
+			results = append(results, result)
+			continue
+		}
+		setLastMatch(context, match)
+		if _, err := block.Call(context, result); err != nil {
+			return nil, err
+		}
+	}
+	setLastMatch(context, last)
+	if blockGiven {
+		return str, nil
+	}
+	return NewArray(results...), nil
+}
+
+func stringSub(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return stringSubstitute(context, 1, args...)
+}
+
+func stringGsub(context CallContext, args ...RubyObject) (RubyObject, error) {
+	return stringSubstitute(context, -1, args...)
+}
+
+// stringSubstitute replaces up to n matches of the pattern within the
+// receiver, or all matches if n is negative. The replacement is either a
+// String with back references, a Hash mapping matched text to replacements,
+// or the value returned by the block.
+func stringSubstitute(context CallContext, n int, args ...RubyObject) (RubyObject, error) {
+	str := context.Receiver().(*String)
+	block, args, blockGiven := extractBlockFromArgs(args)
+	if len(args) != 2 && !(len(args) == 1 && blockGiven) {
+		return nil, NewWrongNumberOfArgumentsError(2, len(args))
+	}
+	re, err := regexpArgument(args[0])
+	if err != nil {
+		return nil, err
+	}
+	var out strings.Builder
+	var last RubyObject = NIL
+	pos := 0
+	for _, loc := range re.Regexp.FindAllStringSubmatchIndex(str.Value, n) {
+		match := &MatchData{Regexp: re, Subject: str.Value, Offsets: loc}
+		last = match
+		out.WriteString(str.Value[pos:loc[0]])
+		pos = loc[1]
+		if len(args) == 1 {
+			setLastMatch(context, match)
+			result, err := block.Call(context, match.Group(0))
+			if err != nil {
+				return nil, err
+			}
+			replacement, err := stringify(result)
+			if err != nil {
+				return nil, err
+			}
+			out.WriteString(replacement)
+			continue
+		}
+		switch replacement := args[1].(type) {
+		case *String:
+			out.WriteString(expandReplacement(replacement.Value, match))
+		case *Hash:
+			value, ok := replacement.Get(match.Group(0))
+			if !ok {
+				continue
+			}
+			s, err := stringify(value)
+			if err != nil {
+				return nil, err
+			}
+			out.WriteString(s)
+		default:
+			return nil, NewImplicitConversionTypeError(&String{}, args[1])
+		}
+	}
+	out.WriteString(str.Value[pos:])
+	setLastMatch(context, last)
+	return &String{Value: out.String()}, nil
+}
+
+// expandReplacement returns replacement with its back references \0 to \9,
+// \&, \k<name>, \` and \' replaced by the corresponding parts of match
+func expandReplacement(replacement string, match *MatchData) string {
+	var out strings.Builder
+	for i := 0; i < len(replacement); i++ {
+		c := replacement[i]
+		if c != '\\' || i+1 == len(replacement) {
+			out.WriteByte(c)
+			continue
+		}
+		i++
+		switch r := replacement[i]; {
+		case r >= '0' && r <= '9':
+			if group := int(r - '0'); group < match.size() {
+				out.WriteString(match.group(group))
+			}
+		case r == '&':
+			out.WriteString(match.group(0))
+		case r == '`':
+			out.WriteString(match.Subject[:match.Offsets[0]])
+		case r == '\'':
+			out.WriteString(match.Subject[match.Offsets[1]:])
+		case r == '\\':
+			out.WriteByte('\\')
+		case r == 'k' && strings.HasPrefix(replacement[i+1:], "<") && strings.Contains(replacement[i+1:], ">"):
+			end := strings.IndexByte(replacement[i+1:], '>')
+			name := replacement[i+2 : i+1+end]
+			if group := match.Regexp.Regexp.SubexpIndex(name); group >= 0 {
+				out.WriteString(match.group(group))
+			}
+			i += end + 1
+		default:
+			out.WriteByte('\\')
+			out.WriteByte(r)
+		}
+	}
+	return out.String()
+}
+
+func stringEq(context CallContext, args ...RubyObject) (RubyObject, error) {
+	str := context.Receiver().(*String)
+	other, ok := args[0].(*String)
+	if !ok {
+		return FALSE, nil
+	}
+	return nativeBoolToBoolean(str.Value == other.Value), nil
+}
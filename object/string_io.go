@@ -0,0 +1,173 @@
+package object
+
+import (
+	"fmt"
+	"strings"
+)
+
+var stringIOClass RubyClassObject = newClass(
+	"StringIO",
+	objectClass,
+	stringIOMethods,
+	stringIOClassMethods,
+	func(RubyClassObject, ...RubyObject) (RubyObject, error) {
+		return NewStringIO(""), nil
+	},
+)
+
+func init() {
+	classes.Set("StringIO", stringIOClass)
+}
+
+// NewStringIO returns a StringIO reading from content
+func NewStringIO(content string) *StringIO {
+	return &StringIO{Value: content}
+}
+
+// StringIO represents an IO-like object reading from a string. It is used
+// for the DATA section of a program.
+type StringIO struct {
+	Value string
+	pos   int // the byte offset of the next read
+}
+
+// Inspect returns the class name and the address of the object
+func (s *StringIO) Inspect() string { return fmt.Sprintf("#<StringIO:%p>", s) }
+
+// Type returns STRING_IO_OBJ
+func (s *StringIO) Type() Type { return STRING_IO_OBJ }
+
+// Class returns stringIOClass
+func (s *StringIO) Class() RubyClass { return stringIOClass }
+
+// readLine returns the next line including its line break and advances the
+// position behind it. It returns false if there is nothing left to read.
+func (s *StringIO) readLine() (string, bool) {
+	if s.pos >= len(s.Value) {
+		return "", false
+	}
+	rest := s.Value[s.pos:]
+	end := strings.IndexByte(rest, '\n') + 1
+	if end == 0 {
+		end = len(rest)
+	}
+	s.pos += end
+	return rest[:end], true
+}
+
+var stringIOClassMethods = map[string]RubyMethod{}
+
+var stringIOMethods = map[string]RubyMethod{
+	"initialize": privateMethod(stringIOInitialize),
+	"read":       publicMethod(stringIORead),
+	"gets":       withArity(0, publicMethod(stringIOGets)),
+	"each_line":  publicMethod(stringIOEachLine),
+	"readlines":  withArity(0, publicMethod(stringIOReadlines)),
+	"eof?":       withArity(0, publicMethod(stringIOEof)),
+	"rewind":     withArity(0, publicMethod(stringIORewind)),
+	"pos":        withArity(0, publicMethod(stringIOPos)),
+	"string":     withArity(0, publicMethod(stringIOString)),
+}
+
+func stringIOInitialize(context CallContext, args ...RubyObject) (RubyObject, error) {
+	self, _ := context.Receiver().(*Self)
+	switch len(args) {
+	case 0:
+		self.RubyObject = NewStringIO("")
+		return self, nil
+	case 1:
+		str, ok := args[0].(*String)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(str, args[0])
+		}
+		self.RubyObject = NewStringIO(str.Value)
+		return self, nil
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+}
+
+func stringIORead(context CallContext, args ...RubyObject) (RubyObject, error) {
+	io := unwrapSelf(context.Receiver()).(*StringIO)
+	rest := io.Value[io.pos:]
+	switch len(args) {
+	case 0:
+		io.pos = len(io.Value)
+		return &String{Value: rest}, nil
+	case 1:
+		length, ok := args[0].(*Integer)
+		if !ok {
+			return nil, NewImplicitConversionTypeError(length, args[0])
+		}
+		if length.Value < 0 {
+			return nil, NewArgumentError("negative length %d given", length.Value)
+		}
+		if rest == "" && length.Value > 0 {
+			return NIL, nil
+		}
+		if int64(len(rest)) > length.Value {
+			rest = rest[:length.Value]
+		}
+		io.pos += len(rest)
+		return &String{Value: rest}, nil
+	default:
+		return nil, NewWrongNumberOfArgumentsError(1, len(args))
+	}
+}
+
+func stringIOGets(context CallContext, args ...RubyObject) (RubyObject, error) {
+	io := unwrapSelf(context.Receiver()).(*StringIO)
+	line, ok := io.readLine()
+	if !ok {
+		return NIL, nil
+	}
+	return &String{Value: line}, nil
+}
+
+func stringIOEachLine(context CallContext, args ...RubyObject) (RubyObject, error) {
+	block, remainingArgs, ok := extractBlockFromArgs(args)
+	if !ok {
+		return nil, NewNoBlockGivenLocalJumpError()
+	}
+	if len(remainingArgs) != 0 {
+		return nil, NewWrongNumberOfArgumentsError(0, len(remainingArgs))
+	}
+	io := unwrapSelf(context.Receiver()).(*StringIO)
+	for line, ok := io.readLine(); ok; line, ok = io.readLine() {
+		_, err := block.Call(context, &String{Value: line})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return context.Receiver(), nil
+}
+
+func stringIOReadlines(context CallContext, args ...RubyObject) (RubyObject, error) {
+	io := unwrapSelf(context.Receiver()).(*StringIO)
+	lines := NewArray()
+	for line, ok := io.readLine(); ok; line, ok = io.readLine() {
+		lines.Elements = append(lines.Elements, &String{Value: line})
+	}
+	return lines, nil
+}
+
+func stringIOEof(context CallContext, args ...RubyObject) (RubyObject, error) {
+	io := unwrapSelf(context.Receiver()).(*StringIO)
+	return nativeBoolToBoolean(io.pos >= len(io.Value)), nil
+}
+
+func stringIORewind(context CallContext, args ...RubyObject) (RubyObject, error) {
+	io := unwrapSelf(context.Receiver()).(*StringIO)
+	io.pos = 0
+	return NewInteger(0), nil
+}
+
+func stringIOPos(context CallContext, args ...RubyObject) (RubyObject, error) {
+	io := unwrapSelf(context.Receiver()).(*StringIO)
+	return NewInteger(int64(io.pos)), nil
+}
+
+func stringIOString(context CallContext, args ...RubyObject) (RubyObject, error) {
+	io := unwrapSelf(context.Receiver()).(*StringIO)
+	return &String{Value: io.Value}, nil
+}
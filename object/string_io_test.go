@@ -0,0 +1,87 @@
+package object
+
+import (
+	"testing"
+)
+
+func TestStringIORead(t *testing.T) {
+	tests := []struct {
+		pos       int
+		arguments []RubyObject
+		result    RubyObject
+		newPos    int
+		err       error
+	}{
+		{0, []RubyObject{}, &String{Value: "foo\nbar"}, 7, nil},
+		{4, []RubyObject{}, &String{Value: "bar"}, 7, nil},
+		{7, []RubyObject{}, &String{Value: ""}, 7, nil},
+		{0, []RubyObject{NewInteger(2)}, &String{Value: "fo"}, 2, nil},
+		{5, []RubyObject{NewInteger(10)}, &String{Value: "ar"}, 7, nil},
+		{7, []RubyObject{NewInteger(1)}, NIL, 7, nil},
+		{7, []RubyObject{NewInteger(0)}, &String{Value: ""}, 7, nil},
+		{0, []RubyObject{NewInteger(-1)}, nil, 0, NewArgumentError("negative length -1 given")},
+		{0, []RubyObject{&String{}}, nil, 0, NewImplicitConversionTypeError(&Integer{}, &String{})},
+	}
+
+	for _, tt := range tests {
+		io := &StringIO{Value: "foo\nbar", pos: tt.pos}
+		context := &callContext{receiver: io}
+
+		result, err := stringIORead(context, tt.arguments...)
+
+		checkError(t, err, tt.err)
+
+		checkResult(t, result, tt.result)
+
+		if io.pos != tt.newPos {
+			t.Logf("Expected pos to equal %d, got %d", tt.newPos, io.pos)
+			t.Fail()
+		}
+	}
+}
+
+func TestStringIOGets(t *testing.T) {
+	io := NewStringIO("foo\nbar")
+	context := &callContext{receiver: io}
+
+	expected := []RubyObject{
+		&String{Value: "foo\n"},
+		&String{Value: "bar"},
+		NIL,
+	}
+
+	for _, exp := range expected {
+		result, err := stringIOGets(context)
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, exp)
+	}
+}
+
+func TestStringIOReadlines(t *testing.T) {
+	io := &StringIO{Value: "foo\nbar\nbaz\n", pos: 4}
+	context := &callContext{receiver: io}
+
+	result, err := stringIOReadlines(context)
+
+	checkError(t, err, nil)
+
+	checkResult(t, result, NewArray(&String{Value: "bar\n"}, &String{Value: "baz\n"}))
+
+	eof, err := stringIOEof(context)
+
+	checkError(t, err, nil)
+
+	checkResult(t, eof, TRUE)
+
+	_, err = stringIORewind(context)
+
+	checkError(t, err, nil)
+
+	pos, err := stringIOPos(context)
+
+	checkError(t, err, nil)
+
+	checkResult(t, pos, NewInteger(0))
+}
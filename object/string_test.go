@@ -89,3 +89,13 @@ func TestStringAdd(t *testing.T) {
 		checkResult(t, result, testCase.result)
 	}
 }
+
+func TestStringUpcase(t *testing.T) {
+	context := &callContext{receiver: &String{Value: "foo Bar"}}
+
+	result, err := stringUpcase(context)
+
+	checkError(t, err, nil)
+
+	checkResult(t, result, &String{Value: "FOO BAR"})
+}
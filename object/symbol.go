@@ -39,7 +39,9 @@ func (s *Symbol) hashKey() hashKey {
 var symbolClassMethods = map[string]RubyMethod{}
 
 var symbolMethods = map[string]RubyMethod{
-	"to_s": withArity(0, publicMethod(symbolToS)),
+	"to_s":    withArity(0, publicMethod(symbolToS)),
+	"==":      withArity(1, publicMethod(symbolEq)),
+	"to_proc": withArity(0, publicMethod(symbolToProc)),
 }
 
 func symbolToS(context CallContext, args ...RubyObject) (RubyObject, error) {
@@ -48,3 +50,30 @@ func symbolToS(context CallContext, args ...RubyObject) (RubyObject, error) {
 	}
 	return nil, nil
 }
+
+func symbolEq(context CallContext, args ...RubyObject) (RubyObject, error) {
+	sym := context.Receiver().(*Symbol)
+	other, ok := args[0].(*Symbol)
+	if !ok {
+		return FALSE, nil
+	}
+	return nativeBoolToBoolean(sym.Value == other.Value), nil
+}
+
+func symbolToProc(context CallContext, args ...RubyObject) (RubyObject, error) {
+	sym := context.Receiver().(*Symbol)
+	return &Proc{
+		ArgumentCountMandatory: true,
+		native: func(context CallContext, args ...RubyObject) (RubyObject, error) {
+			if len(args) == 0 {
+				return nil, NewArgumentError("no receiver given")
+			}
+			receiverContext := &callContext{
+				receiver: args[0],
+				env:      context.Env(),
+				eval:     context.Eval,
+			}
+			return Send(receiverContext, sym.Value, args[1:]...)
+		},
+	}, nil
+}
@@ -34,3 +34,38 @@ func TestSymbolToS(t *testing.T) {
 
 	checkResult(t, result, expected)
 }
+
+func TestSymbolToProc(t *testing.T) {
+	context := &callContext{
+		receiver: &Symbol{Value: "to_s"},
+		env:      NewEnvironment(),
+	}
+
+	result, err := symbolToProc(context)
+
+	checkError(t, err, nil)
+
+	proc, ok := result.(*Proc)
+	if !ok {
+		t.Logf("Expected result to be a Proc, got %T", result)
+		t.FailNow()
+	}
+
+	if !proc.ArgumentCountMandatory {
+		t.Logf("Expected proc to be a lambda")
+		t.Fail()
+	}
+
+	t.Run("call with receiver", func(t *testing.T) {
+		result, err := proc.Call(context, &Integer{Value: 3})
+
+		checkError(t, err, nil)
+
+		checkResult(t, result, &String{Value: "3"})
+	})
+	t.Run("call without receiver", func(t *testing.T) {
+		_, err := proc.Call(context)
+
+		checkError(t, err, NewArgumentError("no receiver given"))
+	})
+}
@@ -17,10 +17,13 @@ const (
 	_ int = iota
 	precLowest
 	precBlockDo     // do
-	precBlockBraces // { |x| }
-	precIfUnless    // modifier-if, modifier-unless
+	precIfUnless    // modifier-if, modifier-unless, modifier-while, modifier-until
+	precAndOr       // and, or
+	precNot         // not
+	precInPattern   // value in pattern
 	precAssignment  // x = 5
 	precTenary      // ?, :
+	precRange       // .., ...
 	precLogicalOr   // ||
 	precLogicalAnd  // &&
 	precEquals      // ==, !=, <=>
@@ -30,8 +33,10 @@ const (
 	precShift       // <<
 	precSum         // + or -
 	precProduct     // *, /, %
-	precPrefix      // -X or !X
+	precPrefix      // -X
+	precPower       // **, !X, ~X
 	precCallArg     // func x
+	precBlockBraces // { |x| }
 	precCall        // foo.myFunction(X)
 	precIndex       // array[index]
 	precScope       // A::B
@@ -41,50 +46,84 @@ const (
 )
 
 var precedences = map[token.Type]int{
-	token.IF:         precIfUnless,
-	token.UNLESS:     precIfUnless,
-	token.EQ:         precEquals,
-	token.NOTEQ:      precEquals,
-	token.SPACESHIP:  precEquals,
-	token.LSHIFT:     precShift,
-	token.QMARK:      precTenary,
-	token.COLON:      precTenary,
-	token.LT:         precLessGreater,
-	token.GT:         precLessGreater,
-	token.LTE:        precLessGreater,
-	token.GTE:        precLessGreater,
-	token.PLUS:       precSum,
-	token.MINUS:      precSum,
-	token.SLASH:      precProduct,
-	token.ASTERISK:   precProduct,
-	token.MODULO:     precProduct,
-	token.ASSIGN:     precAssignment,
-	token.ADDASSIGN:  precAssignment,
-	token.SUBASSIGN:  precAssignment,
-	token.MULASSIGN:  precAssignment,
-	token.DIVASSIGN:  precAssignment,
-	token.MODASSIGN:  precAssignment,
-	token.LPAREN:     precCall,
-	token.DOT:        precCall,
-	token.IDENT:      precCallArg,
-	token.CONST:      precCallArg,
-	token.GLOBAL:     precCallArg,
-	token.INT:        precCallArg,
-	token.STRING:     precCallArg,
-	token.SELF:       precCallArg,
-	token.LBRACKET:   precIndex,
-	token.LBRACE:     precBlockBraces,
-	token.DO:         precBlockDo,
-	token.SCOPE:      precScope,
-	token.SYMBEG:     precSymbol,
-	token.COMMA:      precAssignment,
-	token.THEN:       precHighest,
-	token.NEWLINE:    precHighest,
-	token.PIPE:       precOr,
-	token.AND:        precAnd,
-	token.LOGICALOR:  precLogicalOr,
-	token.LOGICALAND: precLogicalAnd,
-	token.CAPTURE:    precCapture,
+	token.IF:               precIfUnless,
+	token.UNLESS:           precIfUnless,
+	token.WHILE:            precIfUnless,
+	token.UNTIL:            precIfUnless,
+	token.RESCUE:           precIfUnless,
+	token.KEYWORD_AND:      precAndOr,
+	token.KEYWORD_OR:       precAndOr,
+	token.IN:               precInPattern,
+	token.EQ:               precEquals,
+	token.CASEEQ:           precEquals,
+	token.NOTEQ:            precEquals,
+	token.MATCH:            precEquals,
+	token.NMATCH:           precEquals,
+	token.SPACESHIP:        precEquals,
+	token.LSHIFT:           precShift,
+	token.RSHIFT:           precShift,
+	token.POW:              precPower,
+	token.CARET:            precOr,
+	token.QMARK:            precTenary,
+	token.COLON:            precTenary,
+	token.DOT2:             precRange,
+	token.DOT3:             precRange,
+	token.LT:               precLessGreater,
+	token.GT:               precLessGreater,
+	token.LTE:              precLessGreater,
+	token.GTE:              precLessGreater,
+	token.PLUS:             precSum,
+	token.MINUS:            precSum,
+	token.SLASH:            precProduct,
+	token.ASTERISK:         precProduct,
+	token.MODULO:           precProduct,
+	token.ASSIGN:           precAssignment,
+	token.ADDASSIGN:        precAssignment,
+	token.SUBASSIGN:        precAssignment,
+	token.MULASSIGN:        precAssignment,
+	token.DIVASSIGN:        precAssignment,
+	token.MODASSIGN:        precAssignment,
+	token.POWASSIGN:        precAssignment,
+	token.LSHIFTASSIGN:     precAssignment,
+	token.RSHIFTASSIGN:     precAssignment,
+	token.XORASSIGN:        precAssignment,
+	token.ORASSIGN:         precAssignment,
+	token.ANDASSIGN:        precAssignment,
+	token.LOGICALORASSIGN:  precAssignment,
+	token.LOGICALANDASSIGN: precAssignment,
+	token.LPAREN:           precCall,
+	token.DOT:              precCall,
+	token.ANDDOT:           precCall,
+	token.IDENT:            precCallArg,
+	token.CONST:            precCallArg,
+	token.GLOBAL:           precCallArg,
+	token.INT:              precCallArg,
+	token.FLOAT:            precCallArg,
+	token.STRING:           precCallArg,
+	token.REGEXBEG:         precCallArg,
+	token.WORDSBEG:         precCallArg,
+	token.SYMBOLSBEG:       precCallArg,
+	token.SELF:             precCallArg,
+	token.KEYWORD__FILE__:  precCallArg,
+	token.KEYWORD__LINE__:  precCallArg,
+	token.KEYWORD__DIR__:   precCallArg,
+	token.BANG:             precCallArg,
+	token.TILDE:            precCallArg,
+	token.LBRACKET:         precIndex,
+	token.LBRACE:           precBlockBraces,
+	token.DO:               precBlockDo,
+	token.SCOPE:            precScope,
+	token.SYMBEG:           precSymbol,
+	token.COMMA:            precAssignment,
+	token.THEN:             precHighest,
+	token.NEWLINE:          precHighest,
+	token.PIPE:             precOr,
+	token.AND:              precAnd,
+	token.LOGICALOR:        precLogicalOr,
+	token.LOGICALAND:       precLogicalAnd,
+	token.CAPTURE:          precCapture,
+	token.LABEL:            precCallArg,
+	token.LAMBDA:           precCallArg,
 }
 
 var tokensNotPossibleInCallArgs = []token.Type{
@@ -96,12 +135,29 @@ var tokensNotPossibleInCallArgs = []token.Type{
 	token.SPACESHIP,
 	token.LSHIFT,
 	token.EQ,
+	token.CASEEQ,
 	token.NOTEQ,
+	token.MATCH,
+	token.NMATCH,
+	token.LOGICALAND,
+	token.LOGICALOR,
+	token.KEYWORD_AND,
+	token.KEYWORD_OR,
+	token.IN,
+	token.HASHROCKET,
 	token.IF,
 	token.UNLESS,
+	token.WHILE,
+	token.UNTIL,
+	token.RESCUE,
+	token.THEN,
 	token.COLON,
 	token.RBRACKET,
+	token.RPAREN,
 	token.COMMA,
+	token.INTERPEND,
+	token.DOT2,
+	token.DOT3,
 }
 
 type (
@@ -118,6 +174,7 @@ var defaultExpressionTerminators = []token.Type{
 // AST describing the parsed program.
 type parser struct {
 	file   *gotoken.File
+	src    []byte
 	l      *lexer.Lexer
 	errors []error
 
@@ -137,6 +194,8 @@ type parser struct {
 
 func (p *parser) init(fset *gotoken.FileSet, filename string, src []byte, mode Mode) {
 	p.file = fset.AddFile(filename, -1, len(src))
+	p.file.SetLinesForContent(src)
+	p.src = src
 
 	p.l = lexer.New(string(src))
 	p.errors = []error{}
@@ -149,15 +208,30 @@ func (p *parser) init(fset *gotoken.FileSet, filename string, src []byte, mode M
 	p.registerPrefix(token.CONST, p.parseIdentifier)
 	p.registerPrefix(token.AT, p.parseInstanceVariable)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
+	p.registerPrefix(token.REGEXBEG, p.parseRegexLiteral)
+	p.registerPrefix(token.WORDSBEG, p.parseWordsLiteral)
+	p.registerPrefix(token.SYMBOLSBEG, p.parseWordsLiteral)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
+	p.registerPrefix(token.KEYWORD_NOT, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
+	p.registerPrefix(token.PLUS, p.parsePrefixExpression)
+	p.registerPrefix(token.TILDE, p.parsePrefixExpression)
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
 	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
 	p.registerPrefix(token.IF, p.parseIfExpression)
 	p.registerPrefix(token.UNLESS, p.parseIfExpression)
 	p.registerPrefix(token.WHILE, p.parseLoopExpression)
+	p.registerPrefix(token.UNTIL, p.parseLoopExpression)
+	p.registerPrefix(token.BREAK, p.parseJumpExpression)
+	p.registerPrefix(token.NEXT, p.parseJumpExpression)
+	p.registerPrefix(token.REDO, p.parseJumpExpression)
+	p.registerPrefix(token.RETRY, p.parseJumpExpression)
+	p.registerPrefix(token.ALIAS, p.parseAlias)
+	p.registerPrefix(token.UNDEF, p.parseUndef)
+	p.registerPrefix(token.SUPER, p.parseSuper)
 	p.registerPrefix(token.DEF, p.parseFunctionLiteral)
 	p.registerPrefix(token.SYMBEG, p.parseSymbolLiteral)
 	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
@@ -167,11 +241,22 @@ func (p *parser) init(fset *gotoken.FileSet, filename string, src []byte, mode M
 	p.registerPrefix(token.CLASS, p.parseClass)
 	p.registerPrefix(token.LBRACE, p.parseHash)
 	p.registerPrefix(token.DO, p.parseBlock)
+	p.registerPrefix(token.LAMBDA, p.parseLambdaLiteral)
 	p.registerPrefix(token.YIELD, p.parseYield)
 	p.registerPrefix(token.GLOBAL, p.parseGlobal)
 	p.registerPrefix(token.KEYWORD__FILE__, p.parseKeyword__FILE__)
+	p.registerPrefix(token.KEYWORD__LINE__, p.parseKeyword__LINE__)
+	p.registerPrefix(token.KEYWORD__DIR__, p.parseKeyword__DIR__)
+	p.registerPrefix(token.KEYWORD_BEGIN, p.parseNestedProgramHook)
+	p.registerPrefix(token.KEYWORD_END, p.parseNestedProgramHook)
 	p.registerPrefix(token.BEGIN, p.parseExceptionHandlingBlock)
-	p.registerPrefix(token.CAPTURE, p.parseBlockCapture)
+	p.registerPrefix(token.CASE, p.parseCaseExpression)
+	p.registerPrefix(token.CAPTURE, p.parseBlockPass)
+	p.registerPrefix(token.LABEL, p.parseKeywordArgument)
+	p.registerPrefix(token.ASTERISK, p.parseSplat)
+	p.registerPrefix(token.POW, p.parseSplat)
+	p.registerPrefix(token.DOT2, p.parseBeginlessRange)
+	p.registerPrefix(token.DOT3, p.parseBeginlessRange)
 
 	p.infixParseFns = make(map[token.Type]infixParseFn)
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
@@ -182,36 +267,70 @@ func (p *parser) init(fset *gotoken.FileSet, filename string, src []byte, mode M
 	p.registerInfix(token.AND, p.parseInfixExpression)
 	p.registerInfix(token.PIPE, p.parseInfixExpression)
 	p.registerInfix(token.EQ, p.parseInfixExpression)
+	p.registerInfix(token.CASEEQ, p.parseInfixExpression)
 	p.registerInfix(token.NOTEQ, p.parseInfixExpression)
+	p.registerInfix(token.MATCH, p.parseInfixExpression)
+	p.registerInfix(token.NMATCH, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
 	p.registerInfix(token.LTE, p.parseInfixExpression)
 	p.registerInfix(token.GTE, p.parseInfixExpression)
 	p.registerInfix(token.LOGICALOR, p.parseInfixExpression)
 	p.registerInfix(token.LOGICALAND, p.parseInfixExpression)
+	p.registerInfix(token.KEYWORD_AND, p.parseInfixExpression)
+	p.registerInfix(token.KEYWORD_OR, p.parseInfixExpression)
+	p.registerInfix(token.IN, p.parsePatternMatchExpression)
 	p.registerInfix(token.SPACESHIP, p.parseInfixExpression)
 	p.registerInfix(token.LSHIFT, p.parseInfixExpression)
+	p.registerInfix(token.RSHIFT, p.parseInfixExpression)
+	p.registerInfix(token.POW, p.parseInfixExpression)
+	p.registerInfix(token.CARET, p.parseInfixExpression)
+	p.registerInfix(token.DOT2, p.parseRange)
+	p.registerInfix(token.DOT3, p.parseRange)
 	p.registerInfix(token.ASSIGN, p.parseAssignment)
 	p.registerInfix(token.ADDASSIGN, p.parseAssignmentOperator)
 	p.registerInfix(token.SUBASSIGN, p.parseAssignmentOperator)
 	p.registerInfix(token.MULASSIGN, p.parseAssignmentOperator)
 	p.registerInfix(token.DIVASSIGN, p.parseAssignmentOperator)
 	p.registerInfix(token.MODASSIGN, p.parseAssignmentOperator)
+	p.registerInfix(token.POWASSIGN, p.parseAssignmentOperator)
+	p.registerInfix(token.LSHIFTASSIGN, p.parseAssignmentOperator)
+	p.registerInfix(token.RSHIFTASSIGN, p.parseAssignmentOperator)
+	p.registerInfix(token.XORASSIGN, p.parseAssignmentOperator)
+	p.registerInfix(token.ORASSIGN, p.parseAssignmentOperator)
+	p.registerInfix(token.ANDASSIGN, p.parseAssignmentOperator)
+	p.registerInfix(token.LOGICALORASSIGN, p.parseAssignment)
+	p.registerInfix(token.LOGICALANDASSIGN, p.parseAssignment)
 	p.registerInfix(token.IF, p.parseModifierConditionalExpression)
 	p.registerInfix(token.UNLESS, p.parseModifierConditionalExpression)
+	p.registerInfix(token.WHILE, p.parseModifierLoopExpression)
+	p.registerInfix(token.UNTIL, p.parseModifierLoopExpression)
+	p.registerInfix(token.RESCUE, p.parseModifierRescue)
 	p.registerInfix(token.QMARK, p.parseTenaryIfExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpressionWithParens)
 	p.registerInfix(token.IDENT, p.parseCallArgument)
 	p.registerInfix(token.CONST, p.parseCallArgument)
 	p.registerInfix(token.GLOBAL, p.parseCallArgument)
 	p.registerInfix(token.INT, p.parseCallArgument)
+	p.registerInfix(token.FLOAT, p.parseCallArgument)
 	p.registerInfix(token.STRING, p.parseCallArgument)
+	p.registerInfix(token.REGEXBEG, p.parseCallArgument)
+	p.registerInfix(token.WORDSBEG, p.parseCallArgument)
+	p.registerInfix(token.SYMBOLSBEG, p.parseCallArgument)
 	p.registerInfix(token.SYMBEG, p.parseCallArgument)
 	p.registerInfix(token.CAPTURE, p.parseCallArgument)
+	p.registerInfix(token.LABEL, p.parseCallArgument)
+	p.registerInfix(token.LAMBDA, p.parseCallArgument)
 	p.registerInfix(token.SELF, p.parseCallArgument)
+	p.registerInfix(token.KEYWORD__FILE__, p.parseCallArgument)
+	p.registerInfix(token.KEYWORD__LINE__, p.parseCallArgument)
+	p.registerInfix(token.KEYWORD__DIR__, p.parseCallArgument)
+	p.registerInfix(token.BANG, p.parseCallArgument)
+	p.registerInfix(token.TILDE, p.parseCallArgument)
 	p.registerInfix(token.LBRACE, p.parseCallBlock)
 	p.registerInfix(token.DO, p.parseCallBlock)
 	p.registerInfix(token.DOT, p.parseMethodCall)
+	p.registerInfix(token.ANDDOT, p.parseMethodCall)
 	p.registerInfix(token.COMMA, p.parseExpressions)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
 	p.registerInfix(token.SCOPE, p.parseScopedIdentifierExpression)
@@ -276,7 +395,6 @@ func (p *parser) nextToken() {
 	p.pos = gotoken.Pos(p.curToken.Pos)
 	p.lastLine += p.curToken.Literal
 	if p.curToken.Type == token.NEWLINE {
-		p.file.AddLine(int(p.pos))
 		p.lastLine = ""
 	}
 	if p.l.HasNext() {
@@ -333,7 +451,16 @@ func (p *parser) ParseProgram() (*ast.Program, error) {
 			p.nextToken()
 			continue
 		}
-		stmt := p.parseStatement()
+		if p.currentTokenIs(token.KEYWORD__END__) {
+			program.Data = p.parseDataSection()
+			break
+		}
+		var stmt ast.Statement
+		if p.currentTokenOneOf(token.KEYWORD_BEGIN, token.KEYWORD_END) {
+			stmt = p.parseProgramHook()
+		} else {
+			stmt = p.parseStatement()
+		}
 		if stmt != nil {
 			program.Statements = append(program.Statements, stmt)
 		}
@@ -364,7 +491,23 @@ func (p *parser) parseStatement() ast.Statement {
 	case token.NEWLINE:
 		return nil
 	case token.RETURN:
-		return p.parseReturnStatement()
+		stmt := p.parseReturnStatement()
+		if stmt == nil {
+			return nil
+		}
+		if p.peekTokenOneOf(token.IF, token.UNLESS) {
+			// the modifier applies to the whole statement, i.e. `return x if y`
+			p.nextToken()
+			modifier := &ast.ExpressionStatement{
+				Token:      stmt.Token,
+				Expression: p.parseModifierConditionalStatement(stmt),
+			}
+			if p.peekTokenOneOf(token.SEMICOLON, token.NEWLINE) {
+				p.nextToken()
+			}
+			return modifier
+		}
+		return stmt
 	case token.HASH:
 		return p.parseComment()
 	default:
@@ -377,15 +520,20 @@ func (p *parser) parseReturnStatement() *ast.ReturnStatement {
 		defer un(trace(p, "parseReturnStatement"))
 	}
 	stmt := &ast.ReturnStatement{Token: p.curToken}
+	returnTerminators := []token.Type{token.RBRACE, token.END, token.EOF, token.IF, token.UNLESS}
+
+	if p.peekTokenOneOf(returnTerminators...) {
+		return stmt
+	}
+
 	p.nextToken()
 
 	if p.currentTokenOneOf(token.NEWLINE, token.SEMICOLON) {
-		p.nextToken()
 		return stmt
 	}
 
 	valToken := p.curToken
-	stmt.ReturnValue = p.parseExpression(precLowest)
+	stmt.ReturnValue = p.parseExpression(precIfUnless)
 	if list, ok := stmt.ReturnValue.(ast.ExpressionList); ok {
 		stmt.ReturnValue = &ast.ArrayLiteral{Elements: list}
 	}
@@ -395,6 +543,10 @@ func (p *parser) parseReturnStatement() *ast.ReturnStatement {
 		return stmt
 	}
 
+	if p.peekTokenOneOf(returnTerminators...) {
+		return stmt
+	}
+
 	if !p.peekTokenIs(token.COMMA) {
 		p.peekError(token.COMMA)
 		return nil
@@ -420,6 +572,11 @@ func (p *parser) parseExpressionStatement() *ast.ExpressionStatement {
 	}
 	stmt := &ast.ExpressionStatement{Token: p.curToken}
 	stmt.Expression = p.parseExpression(precLowest)
+	if stmt.Expression != nil && p.peekTokenIs(token.HASHROCKET) {
+		// rightward pattern matching, i.e. `value => pattern`
+		p.nextToken()
+		stmt.Expression = p.parsePatternMatchExpression(stmt.Expression)
+	}
 	if p.peekTokenOneOf(token.SEMICOLON, token.NEWLINE) {
 		p.nextToken()
 	}
@@ -447,12 +604,29 @@ func (p *parser) parseExpression(precedence int) ast.Expression {
 		if infix == nil {
 			return leftExp
 		}
+		// a brace block can only follow a method call, otherwise it belongs
+		// to the enclosing call, i.e. `foo 1, 2 { |x| x }`
+		if p.peekTokenIs(token.LBRACE) && !acceptsBlock(leftExp) {
+			return leftExp
+		}
 		p.nextToken()
 		leftExp = infix(leftExp)
 	}
 	return leftExp
 }
 
+// acceptsBlock reports whether a block following exp would be passed to it
+func acceptsBlock(exp ast.Expression) bool {
+	switch exp := exp.(type) {
+	case *ast.Identifier:
+		return !exp.IsConstant()
+	case *ast.InfixExpression:
+		return acceptsBlock(exp.Right)
+	default:
+		return false
+	}
+}
+
 func (p *parser) parseComment() ast.Statement {
 	if p.trace {
 		defer un(trace(p, "parseComment"))
@@ -480,20 +654,68 @@ func (p *parser) parseExceptionHandlingBlock() ast.Expression {
 		defer un(trace(p, "parseExceptionHandlingBlock"))
 	}
 	block := &ast.ExceptionHandlingBlock{BeginToken: p.curToken}
-	if !p.accept(token.NEWLINE) {
+	if !p.acceptOneOf(token.NEWLINE, token.SEMICOLON) {
+		return nil
+	}
+	block.TryBody = p.parseBlockStatement(token.RESCUE, token.ELSE, token.ENSURE)
+	if !p.parseExceptionHandlingClauses(block) {
+		return nil
+	}
+	if !p.accept(token.END) {
 		return nil
 	}
-	block.TryBody = p.parseBlockStatement(token.END, token.RESCUE)
+	block.EndToken = p.curToken
+	return block
+}
+
+// parseExceptionHandlingClauses parses the rescue, else and ensure clauses
+// following the body of a begin block or a method definition into block
+func (p *parser) parseExceptionHandlingClauses(block *ast.ExceptionHandlingBlock) bool {
 	block.Rescues = []*ast.RescueBlock{}
 	for p.peekTokenIs(token.RESCUE) {
 		p.accept(token.RESCUE)
 		rescue := p.parseRescueBlock()
+		if rescue == nil {
+			return false
+		}
 		block.Rescues = append(block.Rescues, rescue)
 	}
-	if !p.accept(token.END) {
-		return nil
+	if p.peekTokenIs(token.ELSE) {
+		p.accept(token.ELSE)
+		if p.peekTokenIs(token.SEMICOLON) {
+			p.accept(token.SEMICOLON)
+		}
+		block.Else = p.parseBlockStatement(token.ENSURE)
 	}
-	block.EndToken = p.curToken
+	if p.peekTokenIs(token.ENSURE) {
+		p.accept(token.ENSURE)
+		if p.peekTokenIs(token.SEMICOLON) {
+			p.accept(token.SEMICOLON)
+		}
+		block.Ensure = p.parseBlockStatement()
+	}
+	return true
+}
+
+func (p *parser) parseModifierRescue(left ast.Expression) ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseModifierRescue"))
+	}
+	block := &ast.ExceptionHandlingBlock{BeginToken: p.curToken}
+	rescue := &ast.RescueBlock{Token: p.curToken}
+	p.nextToken()
+	fallback := p.parseExpression(precAndOr)
+	rescue.Body = &ast.BlockStatement{
+		Statements: []ast.Statement{
+			&ast.ExpressionStatement{Expression: fallback},
+		},
+	}
+	block.TryBody = &ast.BlockStatement{
+		Statements: []ast.Statement{
+			&ast.ExpressionStatement{Expression: left},
+		},
+	}
+	block.Rescues = []*ast.RescueBlock{rescue}
 	return block
 }
 
@@ -520,10 +742,10 @@ func (p *parser) parseRescueBlock() *ast.RescueBlock {
 		}
 		block.Exception = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 	}
-	if !p.accept(token.NEWLINE) {
+	if !p.acceptOneOf(token.NEWLINE, token.SEMICOLON, token.THEN) {
 		return nil
 	}
-	block.Body = p.parseBlockStatement(token.END)
+	block.Body = p.parseBlockStatement(token.RESCUE, token.ELSE, token.ENSURE)
 	return block
 }
 
@@ -555,6 +777,26 @@ func (p *parser) parseBlockCapture() ast.Expression {
 	return capture
 }
 
+// parseBlockPass parses a block passed as call argument. Any operand not
+// being a plain identifier is evaluated and converted to a proc at runtime.
+func (p *parser) parseBlockPass() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseBlockPass"))
+	}
+	capture := &ast.BlockCapture{Token: p.curToken}
+	p.nextToken()
+	value := p.parseExpression(precBlockBraces)
+	if value == nil {
+		return nil
+	}
+	if ident, ok := value.(*ast.Identifier); ok {
+		capture.Name = ident
+		return capture
+	}
+	capture.Value = value
+	return capture
+}
+
 func (p *parser) parseAssignmentOperator(left ast.Expression) ast.Expression {
 	if p.trace {
 		defer un(trace(p, "parseAssignmentOperator"))
@@ -572,7 +814,7 @@ func (p *parser) parseAssignmentOperator(left ast.Expression) ast.Expression {
 		Left:  left,
 	}
 	p.nextToken()
-	newInf.Right = p.parseExpression(precLowest)
+	newInf.Right = p.parseAssignmentValue()
 	assign.Right = newInf
 	return assign
 }
@@ -582,15 +824,20 @@ func (p *parser) parseAssignment(left ast.Expression) ast.Expression {
 		defer un(trace(p, "parseAssignment"))
 	}
 
-	switch left.(type) {
+	switch left := left.(type) {
 	case *ast.Identifier:
 	case *ast.Global:
 	case *ast.IndexExpression:
+	case *ast.ContextCallExpression:
+		if !left.IsAttribute() {
+			p.expectError(token.EOF)
+			return nil
+		}
 	case *ast.InstanceVariable:
 	case ast.ExpressionList:
-	case *ast.Keyword__FILE__:
+	case *ast.Keyword__FILE__, *ast.Keyword__LINE__:
 		epos := p.file.Position(p.pos)
-		msg := fmt.Errorf("%s: Can't assign to __FILE__", epos.String())
+		msg := fmt.Errorf("%s: Can't assign to %s", epos.String(), left.TokenLiteral())
 		p.errors = append(p.errors, msg)
 		return nil
 	default:
@@ -603,29 +850,27 @@ func (p *parser) parseAssignment(left ast.Expression) ast.Expression {
 		Left:  left,
 	}
 	p.nextToken()
-	expr := p.parseExpression(precLowest)
-	right, ok := expr.(*ast.ConditionalExpression)
-	if !ok {
-		assign.Right = expr
-		return assign
-	}
-	expStmt, ok := right.Consequence.Statements[0].(*ast.ExpressionStatement)
-	if !ok {
-		p.errors = append(p.errors, fmt.Errorf("malformed AST in assignment"))
-		return nil
+	assign.Right = p.parseAssignmentValue()
+	return assign
+}
+
+// parseAssignmentValue parses the right side of an assignment. It ends before
+// a modifier if, unless, while or until and before `and` or `or`, which all
+// apply to the whole assignment, but includes a modifier rescue, i.e.
+// `x = y rescue z` assigns z if y raises an error.
+func (p *parser) parseAssignmentValue() ast.Expression {
+	value := p.parseExpression(precAndOr)
+	// a do block still binds to the call on the right side, i.e.
+	// `x = m do ... end`
+	for value != nil && p.peekTokenIs(token.DO) && acceptsBlock(value) {
+		p.nextToken()
+		value = p.parseCallBlock(value)
 	}
-	assign.Right = expStmt.Expression
-	cond := &ast.ConditionalExpression{
-		Token:     right.Token,
-		Condition: right.Condition,
-		Consequence: &ast.BlockStatement{
-			Statements: []ast.Statement{
-				&ast.ExpressionStatement{Expression: assign},
-			},
-		},
+	if value != nil && p.peekTokenIs(token.RESCUE) {
+		p.nextToken()
+		value = p.parseModifierRescue(value)
 	}
-
-	return cond
+	return value
 }
 
 func (p *parser) parseInstanceVariable() ast.Expression {
@@ -684,7 +929,7 @@ func (p *parser) parseSelf() ast.Expression {
 	if p.peekTokenOneOf(token.IF, token.UNLESS) {
 		return self
 	}
-	if !p.peekTokenOneOf(token.NEWLINE, token.SEMICOLON, token.DOT, token.EOF) {
+	if !p.peekTokenOneOf(token.NEWLINE, token.SEMICOLON, token.DOT, token.ANDDOT, token.EOF, token.INTERPEND) {
 		p.peekError(token.NEWLINE, token.SEMICOLON, token.DOT, token.EOF)
 		return nil
 	}
@@ -702,6 +947,65 @@ func (p *parser) parseKeyword__FILE__() ast.Expression {
 	return file
 }
 
+func (p *parser) parseKeyword__LINE__() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseKeyword__LINE__"))
+	}
+	line := &ast.Keyword__LINE__{
+		Token: p.curToken,
+		Line:  p.file.Line(p.file.Pos(p.curToken.Pos)),
+	}
+	return line
+}
+
+func (p *parser) parseKeyword__DIR__() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseKeyword__DIR__"))
+	}
+	dir := &ast.Keyword__DIR__{
+		Token:    p.curToken,
+		Filename: p.file.Name(),
+	}
+	return dir
+}
+
+// parseProgramHook parses a BEGIN or END block at the top level of the program
+func (p *parser) parseProgramHook() ast.Statement {
+	if p.trace {
+		defer un(trace(p, "parseProgramHook"))
+	}
+	hook := &ast.ProgramHook{Token: p.curToken}
+	if !p.accept(token.LBRACE) {
+		return nil
+	}
+	hook.Body = p.parseBlockStatement(token.RBRACE)
+	if !p.accept(token.RBRACE) {
+		return nil
+	}
+	hook.Body.EndToken = p.curToken
+	return hook
+}
+
+// parseNestedProgramHook reports a BEGIN or END block which is not at the top
+// level of the program
+func (p *parser) parseNestedProgramHook() ast.Expression {
+	epos := p.file.Position(p.pos)
+	msg := fmt.Errorf("%s: %s is permitted only at toplevel", epos.String(), p.curToken.Literal)
+	p.errors = append(p.errors, msg)
+	return nil
+}
+
+// parseDataSection returns the input following the current `__END__` line
+func (p *parser) parseDataSection() *string {
+	data := string(p.src[p.curToken.Pos+len(p.curToken.Literal):])
+	if strings.HasPrefix(data, "\r\n") {
+		data = data[2:]
+	} else if strings.HasPrefix(data, "\n") {
+		data = data[1:]
+	}
+	return &data
+}
+
 func (p *parser) parseYield() ast.Expression {
 	if p.trace {
 		defer un(trace(p, "parseYield"))
@@ -718,6 +1022,44 @@ func (p *parser) parseYield() ast.Expression {
 	return yield
 }
 
+func (p *parser) parseSuper() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseSuper"))
+	}
+	super := &ast.SuperExpression{Token: p.curToken}
+
+	if p.peekTokenIs(token.LPAREN) {
+		p.accept(token.LPAREN)
+		p.nextToken()
+		super.Arguments = p.parseExpressionList(token.RPAREN)
+		if p.peekTokenOneOf(token.LBRACE, token.DO) {
+			p.acceptOneOf(token.LBRACE, token.DO)
+			super.Block = p.parseBlock().(*ast.BlockExpression)
+		}
+		return super
+	}
+
+	if p.peekTokenOneOf(token.LBRACE, token.DO) {
+		super.Implicit = true
+		p.acceptOneOf(token.LBRACE, token.DO)
+		super.Block = p.parseBlock().(*ast.BlockExpression)
+		return super
+	}
+
+	noArguments := []token.Type{token.SEMICOLON, token.NEWLINE, token.EOF, token.END, token.DOT, token.ANDDOT, token.RPAREN, token.RBRACE}
+	if p.peekTokenOneOf(append(tokensNotPossibleInCallArgs, noArguments...)...) || p.peekToken.Type.IsOperator() {
+		super.Implicit = true
+		return super
+	}
+
+	p.nextToken()
+	super.Arguments = p.parseCallArguments(token.LBRACE, token.DO)
+	if p.currentTokenOneOf(token.LBRACE, token.DO) {
+		super.Block = p.parseBlock().(*ast.BlockExpression)
+	}
+	return super
+}
+
 var integerLiteralReplacer = strings.NewReplacer("_", "")
 
 func (p *parser) parseIntegerLiteral() ast.Expression {
@@ -725,7 +1067,12 @@ func (p *parser) parseIntegerLiteral() ast.Expression {
 		defer un(trace(p, "parseIntegerLiteral"))
 	}
 	lit := &ast.IntegerLiteral{Token: p.curToken}
-	value, err := strconv.ParseInt(integerLiteralReplacer.Replace(p.curToken.Literal), 0, 64)
+	literal := integerLiteralReplacer.Replace(p.curToken.Literal)
+	// strconv knows all prefixes but the one for decimal numbers
+	if strings.HasPrefix(literal, "0d") || strings.HasPrefix(literal, "0D") {
+		literal = literal[2:]
+	}
+	value, err := strconv.ParseInt(literal, 0, 64)
 	if err != nil {
 		msg := fmt.Errorf("could not parse %q as integer", p.curToken.Literal)
 		p.errors = append(p.errors, msg)
@@ -735,11 +1082,97 @@ func (p *parser) parseIntegerLiteral() ast.Expression {
 	return lit
 }
 
+func (p *parser) parseRegexLiteral() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseRegexLiteral"))
+	}
+	regex := &ast.RegexLiteral{Token: p.curToken}
+	if !p.accept(token.STRING) {
+		return nil
+	}
+	regex.Pattern = p.parseStringLiteral()
+	if !p.accept(token.REGEXEND) {
+		return nil
+	}
+	regex.Flags = strings.TrimPrefix(p.curToken.Literal, "/")
+	return regex
+}
+
+// parseWordsLiteral parses a %w or %i literal into an array of strings or
+// symbols.
+func (p *parser) parseWordsLiteral() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseWordsLiteral"))
+	}
+	array := &ast.ArrayLiteral{Token: p.curToken}
+	for p.peekTokenIs(token.STRING) {
+		p.nextToken()
+		word := p.parseStringLiteral()
+		if word == nil {
+			return nil
+		}
+		if array.Token.Type == token.SYMBOLSBEG {
+			word = &ast.SymbolLiteral{Token: p.curToken, Value: word}
+		}
+		array.Elements = append(array.Elements, word)
+	}
+	if !p.accept(token.WORDSEND) {
+		return nil
+	}
+	array.Rbracket = p.curToken
+	return array
+}
+
+func (p *parser) parseFloatLiteral() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseFloatLiteral"))
+	}
+	lit := &ast.FloatLiteral{Token: p.curToken}
+	value, err := strconv.ParseFloat(integerLiteralReplacer.Replace(p.curToken.Literal), 64)
+	if err != nil {
+		msg := fmt.Errorf("could not parse %q as float", p.curToken.Literal)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+	lit.Value = value
+	return lit
+}
+
 func (p *parser) parseStringLiteral() ast.Expression {
 	if p.trace {
 		defer un(trace(p, "parseStringLiteral"))
 	}
-	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+	str := &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+	if !p.peekTokenIs(token.INTERPBEG) {
+		return str
+	}
+	interpolated := &ast.InterpolatedString{Token: p.curToken}
+	if str.Value != "" {
+		interpolated.Parts = append(interpolated.Parts, str)
+	}
+	for p.peekTokenIs(token.INTERPBEG) {
+		p.accept(token.INTERPBEG)
+		interpolation := &ast.Interpolation{Token: p.curToken}
+		interpolation.Body = p.parseBlockStatement(token.INTERPEND)
+		if !p.accept(token.INTERPEND) {
+			return nil
+		}
+		interpolation.EndToken = p.curToken
+		interpolation.Body.EndToken = p.curToken
+		if len(interpolation.Body.Statements) != 0 {
+			interpolated.Parts = append(interpolated.Parts, interpolation)
+		}
+		if !p.accept(token.STRING) {
+			return nil
+		}
+		if p.curToken.Literal != "" {
+			interpolated.Parts = append(
+				interpolated.Parts,
+				&ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal},
+			)
+		}
+	}
+	return interpolated
 }
 
 func (p *parser) parseSymbolLiteral() ast.Expression {
@@ -809,6 +1242,11 @@ func (p *parser) parseHash() ast.Expression {
 }
 
 func (p *parser) parseKeyValue() (ast.Expression, ast.Expression, bool) {
+	if p.currentTokenIs(token.LABEL) {
+		key := p.parseLabel()
+		p.nextToken()
+		return key, p.parseExpression(precAssignment), true
+	}
 	key := p.parseExpression(precAssignment)
 	if !p.consume(token.HASHROCKET) {
 		return nil, nil, false
@@ -817,13 +1255,49 @@ func (p *parser) parseKeyValue() (ast.Expression, ast.Expression, bool) {
 	return key, val, true
 }
 
+// parseLabel returns the symbol named by the current token.LABEL
+func (p *parser) parseLabel() ast.Expression {
+	return &ast.SymbolLiteral{
+		Token: p.curToken,
+		Value: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal},
+	}
+}
+
+// parseKeywordArgument parses a keyword argument given without braces, i.e.
+// `foo(key: value)`, into a hash holding a single pair. Consecutive keyword
+// arguments are merged when the arguments are evaluated.
+func (p *parser) parseKeywordArgument() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseKeywordArgument"))
+	}
+	hash := &ast.HashLiteral{Token: p.curToken, Map: make(map[ast.Expression]ast.Expression)}
+	key := p.parseLabel()
+	p.nextToken()
+	hash.Map[key] = p.parseExpression(precAssignment)
+	hash.Rbrace = p.curToken
+	return hash
+}
+
+func (p *parser) parseSplat() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseSplat"))
+	}
+	splat := &ast.Splat{Token: p.curToken}
+	p.nextToken()
+	splat.Value = p.parseExpression(precPrefix)
+	return splat
+}
+
 func (p *parser) parseBlock() ast.Expression {
 	if p.trace {
 		defer un(trace(p, "parseBlock"))
 	}
 	block := &ast.BlockExpression{Token: p.curToken}
-	if p.peekTokenIs(token.PIPE) {
-		block.Parameters = p.parseParameters(token.PIPE, token.PIPE)
+	switch {
+	case p.peekTokenIs(token.LOGICALOR):
+		p.accept(token.LOGICALOR)
+	case p.peekTokenIs(token.PIPE):
+		p.parseBlockParameters(block)
 	}
 
 	if p.peekTokenOneOf(token.NEWLINE, token.SEMICOLON) {
@@ -841,6 +1315,99 @@ func (p *parser) parseBlock() ast.Expression {
 	return block
 }
 
+// parseBlockParameters parses the parameters between the pipes of a block,
+// including the block-local variables following a semicolon, i.e.
+// `|a, (b, c); tmp|`.
+func (p *parser) parseBlockParameters(block *ast.BlockExpression) {
+	if p.trace {
+		defer un(trace(p, "parseBlockParameters"))
+	}
+	p.accept(token.PIPE)
+	for !p.peekTokenOneOf(token.PIPE, token.SEMICOLON) {
+		param := p.parseBlockParameter(token.PIPE)
+		if param == nil {
+			return
+		}
+		block.Parameters = append(block.Parameters, param)
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.accept(token.COMMA)
+	}
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.accept(token.SEMICOLON)
+		for p.accept(token.IDENT) {
+			block.Locals = append(block.Locals, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+			if !p.peekTokenIs(token.COMMA) {
+				break
+			}
+			p.accept(token.COMMA)
+		}
+	}
+	p.accept(token.PIPE)
+}
+
+// parseBlockParameter parses a single block parameter, which may destructure
+// its argument into nested parameters, i.e. `(a, (b, *c))`.
+func (p *parser) parseBlockParameter(endToken token.Type) *ast.FunctionParameter {
+	if p.trace {
+		defer un(trace(p, "parseBlockParameter"))
+	}
+	if !p.peekTokenIs(token.LPAREN) {
+		// default values must not swallow the closing pipe
+		return p.parseParameter(endToken, precOr)
+	}
+	p.accept(token.LPAREN)
+	param := &ast.FunctionParameter{Kind: ast.DestructuringParameter, Lparen: p.curToken}
+	for {
+		nested := p.parseBlockParameter(token.RPAREN)
+		if nested == nil {
+			return nil
+		}
+		param.Parameters = append(param.Parameters, nested)
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.accept(token.COMMA)
+	}
+	if !p.accept(token.RPAREN) {
+		return nil
+	}
+	param.Rparen = p.curToken
+	return param
+}
+
+func (p *parser) parseLambdaLiteral() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseLambdaLiteral"))
+	}
+	lambda := &ast.LambdaLiteral{Token: p.curToken}
+	var params []*ast.FunctionParameter
+	if p.peekTokenIs(token.LPAREN) {
+		params = p.parseParameters(token.LPAREN, token.RPAREN)
+	}
+	// parameters without parens, i.e. `-> x, y { }`
+	for !p.peekTokenOneOf(token.LBRACE, token.DO) {
+		param := p.parseParameter(token.LBRACE, precPrefix)
+		if param == nil {
+			return nil
+		}
+		params = append(params, param)
+		if p.peekTokenIs(token.COMMA) {
+			p.accept(token.COMMA)
+		}
+	}
+	p.acceptOneOf(token.LBRACE, token.DO)
+	block, ok := p.parseBlock().(*ast.BlockExpression)
+	if !ok {
+		p.errors = append(p.errors, fmt.Errorf("could not parse lambda literal: malformed block"))
+		return nil
+	}
+	block.Parameters = params
+	lambda.Block = block
+	return lambda
+}
+
 func (p *parser) parsePrefixExpression() ast.Expression {
 	if p.trace {
 		defer un(trace(p, "parsePrefixExpression"))
@@ -849,8 +1416,18 @@ func (p *parser) parsePrefixExpression() ast.Expression {
 		Token:    p.curToken,
 		Operator: p.curToken.Literal,
 	}
+	precedence := precPrefix
+	switch expression.Token.Type {
+	case token.MINUS:
+	case token.KEYWORD_NOT:
+		// `not x == y` is `not (x == y)`
+		precedence = precNot
+	default:
+		// `!x ** 2` is `(!x) ** 2`, whereas `-x ** 2` is `-(x ** 2)`
+		precedence = precPower
+	}
 	p.nextToken()
-	expression.Right = p.parseExpression(precPrefix)
+	expression.Right = p.parseExpression(precedence)
 	return expression
 }
 
@@ -864,17 +1441,67 @@ func (p *parser) parseInfixExpression(left ast.Expression) ast.Expression {
 		Left:     left,
 	}
 	precedence := p.curPrecedence()
+	if expression.Token.Type == token.POW {
+		// `**` is right-associative
+		precedence--
+	}
 	p.nextToken()
 	expression.Right = p.parseExpression(precedence)
 	return expression
 }
 
-func (p *parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	if p.trace {
-		defer un(trace(p, "parseIndexExpression"))
-	}
-	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
-
+// rangeEndTerminators contains the tokens which can follow an endless range
+var rangeEndTerminators = []token.Type{
+	token.NEWLINE,
+	token.SEMICOLON,
+	token.EOF,
+	token.RPAREN,
+	token.RBRACKET,
+	token.RBRACE,
+	token.COMMA,
+	token.THEN,
+	token.DO,
+	token.INTERPEND,
+}
+
+func (p *parser) parseRange(left ast.Expression) ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseRange"))
+	}
+	rng := &ast.RangeLiteral{
+		Token:     p.curToken,
+		Left:      left,
+		Exclusive: p.currentTokenIs(token.DOT3),
+	}
+	if p.peekTokenOneOf(rangeEndTerminators...) {
+		return rng
+	}
+	precedence := p.curPrecedence()
+	p.nextToken()
+	rng.Right = p.parseExpression(precedence)
+	return rng
+}
+
+func (p *parser) parseBeginlessRange() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseBeginlessRange"))
+	}
+	rng := &ast.RangeLiteral{
+		Token:     p.curToken,
+		Exclusive: p.currentTokenIs(token.DOT3),
+	}
+	precedence := p.curPrecedence()
+	p.nextToken()
+	rng.Right = p.parseExpression(precedence)
+	return rng
+}
+
+func (p *parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseIndexExpression"))
+	}
+	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+
 	p.nextToken()
 	exp.Index = p.parseExpression(precLowest)
 	if elist, ok := exp.Index.(ast.ExpressionList); ok {
@@ -907,11 +1534,12 @@ func (p *parser) parseIfExpression() ast.Expression {
 	expression := &ast.ConditionalExpression{Token: p.curToken}
 	p.nextToken()
 	expression.Condition = p.parseExpression(precLowest)
-	if p.peekTokenIs(token.THEN) {
+	then := p.peekTokenIs(token.THEN)
+	if then {
 		p.accept(token.THEN)
 	}
 
-	if !p.peekTokenOneOf(token.NEWLINE, token.SEMICOLON) {
+	if !then && !p.peekTokenOneOf(token.NEWLINE, token.SEMICOLON) {
 		msg := fmt.Sprintf(
 			"could not parse if expression: unexpected token %s: '%s'",
 			p.peekToken.Type,
@@ -927,12 +1555,36 @@ func (p *parser) parseIfExpression() ast.Expression {
 		p.errors = append(p.errors, err)
 		return nil
 	}
-	p.acceptOneOf(token.NEWLINE, token.SEMICOLON)
-	consequence := p.parseBlockStatement(token.ELSE)
+	if p.peekTokenOneOf(token.NEWLINE, token.SEMICOLON) {
+		p.nextToken()
+	}
+	consequence := p.parseBlockStatement(token.ELSE, token.ELSIF)
 	expression.Consequence = consequence
+	if p.peekTokenIs(token.ELSIF) {
+		if expression.IsNegated() {
+			p.peekError(token.ELSE, token.END)
+			return nil
+		}
+		p.accept(token.ELSIF)
+		elsif, ok := p.parseIfExpression().(*ast.ConditionalExpression)
+		if !ok {
+			return nil
+		}
+		expression.Alternative = &ast.BlockStatement{
+			Token:    elsif.Token,
+			EndToken: elsif.EndToken,
+			Statements: []ast.Statement{
+				&ast.ExpressionStatement{Token: elsif.Token, Expression: elsif},
+			},
+		}
+		expression.EndToken = elsif.EndToken
+		return expression
+	}
 	if p.peekTokenIs(token.ELSE) {
 		p.accept(token.ELSE)
-		p.accept(token.NEWLINE)
+		if p.peekTokenOneOf(token.NEWLINE, token.SEMICOLON) {
+			p.nextToken()
+		}
 		expression.Alternative = p.parseBlockStatement()
 	}
 	p.accept(token.END)
@@ -940,6 +1592,487 @@ func (p *parser) parseIfExpression() ast.Expression {
 	return expression
 }
 
+func (p *parser) parseCaseExpression() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseCaseExpression"))
+	}
+	expression := &ast.CaseExpression{Token: p.curToken}
+	if !p.peekTokenOneOf(token.NEWLINE, token.SEMICOLON) {
+		p.nextToken()
+		expression.Subject = p.parseExpression(precInPattern)
+	}
+	for p.peekTokenOneOf(token.NEWLINE, token.SEMICOLON) {
+		p.nextToken()
+	}
+	if expression.Subject != nil && p.peekTokenIs(token.IN) {
+		return p.parseCaseMatchExpression(expression.Token, expression.Subject)
+	}
+	if !p.peekTokenIs(token.WHEN) {
+		p.peekError(token.WHEN)
+		return nil
+	}
+	for p.peekTokenIs(token.WHEN) {
+		p.accept(token.WHEN)
+		when := &ast.WhenClause{Token: p.curToken}
+		p.nextToken()
+		when.Conditions = append(when.Conditions, p.parseExpression(precAssignment))
+		for p.peekTokenIs(token.COMMA) {
+			p.consume(token.COMMA)
+			for p.currentTokenIs(token.NEWLINE) {
+				p.nextToken()
+			}
+			when.Conditions = append(when.Conditions, p.parseExpression(precAssignment))
+		}
+		if !p.acceptOneOf(token.THEN, token.NEWLINE, token.SEMICOLON) {
+			return nil
+		}
+		when.Consequence = p.parseBlockStatement(token.WHEN, token.ELSE)
+		expression.Whens = append(expression.Whens, when)
+	}
+	if p.peekTokenIs(token.ELSE) {
+		p.accept(token.ELSE)
+		expression.Else = p.parseBlockStatement()
+	}
+	if !p.accept(token.END) {
+		return nil
+	}
+	expression.EndToken = p.curToken
+	return expression
+}
+
+func (p *parser) parseCaseMatchExpression(caseToken token.Token, subject ast.Expression) ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseCaseMatchExpression"))
+	}
+	expression := &ast.CaseMatchExpression{Token: caseToken, Subject: subject}
+	for p.peekTokenIs(token.IN) {
+		p.accept(token.IN)
+		in := &ast.InClause{Token: p.curToken}
+		p.nextToken()
+		in.Pattern = p.parseTopPattern()
+		if in.Pattern == nil {
+			return nil
+		}
+		if p.peekTokenOneOf(token.IF, token.UNLESS) {
+			p.nextToken()
+			in.GuardToken = p.curToken
+			p.nextToken()
+			in.Guard = p.parseExpression(precLowest)
+		}
+		if !p.acceptOneOf(token.THEN, token.NEWLINE, token.SEMICOLON) {
+			return nil
+		}
+		in.Consequence = p.parseBlockStatement(token.IN, token.ELSE)
+		expression.Ins = append(expression.Ins, in)
+	}
+	if p.peekTokenIs(token.ELSE) {
+		p.accept(token.ELSE)
+		expression.Else = p.parseBlockStatement()
+	}
+	if !p.accept(token.END) {
+		return nil
+	}
+	expression.EndToken = p.curToken
+	return expression
+}
+
+// parsePatternMatchExpression parses a standalone pattern match, i.e.
+// `value in pattern` or `value => pattern`. The current token is `in` or `=>`.
+func (p *parser) parsePatternMatchExpression(value ast.Expression) ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parsePatternMatchExpression"))
+	}
+	expression := &ast.PatternMatchExpression{Token: p.curToken, Value: value}
+	p.nextToken()
+	expression.Pattern = p.parseTopPattern()
+	if expression.Pattern == nil {
+		return nil
+	}
+	return expression
+}
+
+// patternTerminators contains the tokens which can follow a complete pattern
+var patternTerminators = []token.Type{
+	token.COMMA,
+	token.NEWLINE,
+	token.SEMICOLON,
+	token.EOF,
+	token.THEN,
+	token.IF,
+	token.UNLESS,
+	token.KEYWORD_AND,
+	token.KEYWORD_OR,
+	token.PIPE,
+	token.HASHROCKET,
+	token.RPAREN,
+	token.RBRACKET,
+	token.RBRACE,
+}
+
+// parseTopPattern parses the pattern of an in clause or of a standalone
+// pattern match. At this level array and hash patterns can be given without
+// brackets, i.e. `in first, *rest` or `in name:, age:`.
+func (p *parser) parseTopPattern() ast.Pattern {
+	if p.trace {
+		defer un(trace(p, "parseTopPattern"))
+	}
+	if p.currentTokenOneOf(token.LABEL, token.POW) {
+		return p.parseHashPatternElements(&ast.HashPattern{Token: p.curToken}, token.ILLEGAL)
+	}
+	start := p.curToken
+	first := p.parseArrayPatternElement()
+	if first == nil {
+		return nil
+	}
+	if _, ok := first.(*ast.SplatPattern); !ok && !p.peekTokenIs(token.COMMA) {
+		return first
+	}
+	elements := []ast.Pattern{first}
+	for p.peekTokenIs(token.COMMA) {
+		p.accept(token.COMMA)
+		p.nextToken()
+		element := p.parseArrayPatternElement()
+		if element == nil {
+			return nil
+		}
+		elements = append(elements, element)
+	}
+	return p.newArrayPattern(start, token.Token{}, nil, elements)
+}
+
+// parsePattern parses a pattern with its alternatives and captures, i.e.
+// `Integer | Float => number`
+func (p *parser) parsePattern() ast.Pattern {
+	if p.trace {
+		defer un(trace(p, "parsePattern"))
+	}
+	pattern := p.parsePrimaryPattern()
+	if pattern == nil {
+		return nil
+	}
+	if p.peekTokenIs(token.PIPE) {
+		alternative := &ast.AlternativePattern{Alternatives: []ast.Pattern{pattern}}
+		for p.peekTokenIs(token.PIPE) {
+			p.accept(token.PIPE)
+			p.nextToken()
+			next := p.parsePrimaryPattern()
+			if next == nil {
+				return nil
+			}
+			alternative.Alternatives = append(alternative.Alternatives, next)
+		}
+		if !p.checkAlternativeBindings(alternative) {
+			return nil
+		}
+		pattern = alternative
+	}
+	for p.peekTokenIs(token.HASHROCKET) {
+		p.accept(token.HASHROCKET)
+		capture := &ast.CapturePattern{Token: p.curToken, Pattern: pattern}
+		if !p.accept(token.IDENT) {
+			return nil
+		}
+		capture.Name = p.parseIdentifier().(*ast.Identifier)
+		pattern = capture
+	}
+	return pattern
+}
+
+// checkAlternativeBindings reports an error if any alternative binds a
+// variable, as it is undefined which alternative would set it. Variables
+// starting with an underscore are allowed.
+func (p *parser) checkAlternativeBindings(pattern *ast.AlternativePattern) bool {
+	var name string
+	bind := func(ident *ast.Identifier) {
+		if ident != nil && name == "" && !strings.HasPrefix(ident.Value, "_") {
+			name = ident.Value
+		}
+	}
+	ast.Inspect(pattern, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.VariablePattern:
+			bind(node.Name)
+		case *ast.CapturePattern:
+			bind(node.Name)
+		case *ast.SplatPattern:
+			bind(node.Name)
+		case *ast.HashPattern:
+			for _, pair := range node.Pairs {
+				if pair.Value == nil {
+					bind(pair.Key)
+				}
+			}
+		case *ast.PinPattern:
+			return false
+		}
+		return name == ""
+	})
+	if name == "" {
+		return true
+	}
+	epos := p.file.Position(p.pos)
+	msg := fmt.Errorf("%s: illegal variable in alternative pattern (%s)", epos.String(), name)
+	p.errors = append(p.errors, msg)
+	return false
+}
+
+func (p *parser) parsePrimaryPattern() ast.Pattern {
+	if p.trace {
+		defer un(trace(p, "parsePrimaryPattern"))
+	}
+	switch p.curToken.Type {
+	case token.LBRACKET:
+		start := p.curToken
+		elements, ok := p.parseArrayPatternElements(token.RBRACKET)
+		if !ok {
+			return nil
+		}
+		return p.newArrayPattern(start, p.curToken, nil, elements)
+	case token.LBRACE:
+		return p.parseHashPattern(&ast.HashPattern{Token: p.curToken}, token.RBRACE)
+	case token.CARET:
+		pin := &ast.PinPattern{Token: p.curToken}
+		p.nextToken()
+		pin.Value = p.parseExpression(precHighest)
+		if pin.Value == nil {
+			return nil
+		}
+		return pin
+	case token.IDENT:
+		return &ast.VariablePattern{Name: p.parseIdentifier().(*ast.Identifier)}
+	case token.CONST:
+		return p.parseConstantPattern()
+	case token.DOT2, token.DOT3:
+		return p.parseRangePattern(nil)
+	}
+	value := p.parseExpression(precOr)
+	if value == nil {
+		return nil
+	}
+	if p.peekTokenOneOf(token.DOT2, token.DOT3) {
+		p.nextToken()
+		return p.parseRangePattern(value)
+	}
+	return &ast.ValuePattern{Value: value}
+}
+
+// parseRangePattern parses a range starting with left, which is nil for a
+// beginless range. The current token is the `..` or `...`.
+func (p *parser) parseRangePattern(left ast.Expression) ast.Pattern {
+	if p.trace {
+		defer un(trace(p, "parseRangePattern"))
+	}
+	rng := &ast.RangeLiteral{
+		Token:     p.curToken,
+		Left:      left,
+		Exclusive: p.curToken.Type == token.DOT3,
+	}
+	if left == nil || !p.peekTokenOneOf(patternTerminators...) {
+		p.nextToken()
+		rng.Right = p.parseExpression(precOr)
+		if rng.Right == nil {
+			return nil
+		}
+	}
+	return &ast.ValuePattern{Value: rng}
+}
+
+// parseConstantPattern parses a constant, which is either matched as value
+// or constrains the class of an array or hash pattern, i.e. `Point(x:, y:)`
+// or `Point[x, y]`.
+func (p *parser) parseConstantPattern() ast.Pattern {
+	if p.trace {
+		defer un(trace(p, "parseConstantPattern"))
+	}
+	start := p.curToken
+	constant := p.parseExpression(precIndex)
+	if constant == nil {
+		return nil
+	}
+	var end token.Type
+	switch {
+	case p.peekTokenIs(token.LPAREN):
+		end = token.RPAREN
+	case p.peekTokenIs(token.LBRACKET):
+		end = token.RBRACKET
+	case p.peekTokenOneOf(token.DOT2, token.DOT3):
+		p.nextToken()
+		return p.parseRangePattern(constant)
+	default:
+		return &ast.ValuePattern{Value: constant}
+	}
+	p.nextToken()
+	if p.peekTokenOneOf(token.LABEL, token.POW) {
+		return p.parseHashPattern(&ast.HashPattern{Token: start, Constant: constant}, end)
+	}
+	elements, ok := p.parseArrayPatternElements(end)
+	if !ok {
+		return nil
+	}
+	return p.newArrayPattern(start, p.curToken, constant, elements)
+}
+
+// parseArrayPatternElements parses the patterns of an array pattern up to the
+// token end. The current token is the opening bracket.
+func (p *parser) parseArrayPatternElements(end token.Type) ([]ast.Pattern, bool) {
+	if p.trace {
+		defer un(trace(p, "parseArrayPatternElements"))
+	}
+	elements := []ast.Pattern{}
+	p.skipNewlines()
+	if p.peekTokenIs(end) {
+		p.accept(end)
+		return elements, true
+	}
+	for {
+		p.nextToken()
+		element := p.parseArrayPatternElement()
+		if element == nil {
+			return nil, false
+		}
+		elements = append(elements, element)
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.accept(token.COMMA)
+		p.skipNewlines()
+	}
+	p.skipNewlines()
+	if !p.accept(end) {
+		return nil, false
+	}
+	return elements, true
+}
+
+// parseArrayPatternElement parses a pattern or a splat within an array
+// pattern
+func (p *parser) parseArrayPatternElement() ast.Pattern {
+	if !p.currentTokenIs(token.ASTERISK) {
+		return p.parsePattern()
+	}
+	splat := &ast.SplatPattern{Token: p.curToken}
+	if p.peekTokenIs(token.IDENT) {
+		p.accept(token.IDENT)
+		splat.Name = p.parseIdentifier().(*ast.Identifier)
+	}
+	return splat
+}
+
+// newArrayPattern returns an array pattern for elements, or a find pattern
+// if elements start and end with a splat
+func (p *parser) newArrayPattern(start, end token.Token, constant ast.Expression, elements []ast.Pattern) ast.Pattern {
+	splats := []int{}
+	for i, element := range elements {
+		if _, ok := element.(*ast.SplatPattern); ok {
+			splats = append(splats, i)
+		}
+	}
+	switch {
+	case len(splats) == 0:
+		return &ast.ArrayPattern{Token: start, EndToken: end, Constant: constant, Pre: elements}
+	case len(splats) == 1:
+		i := splats[0]
+		return &ast.ArrayPattern{
+			Token:    start,
+			EndToken: end,
+			Constant: constant,
+			Pre:      elements[:i],
+			Rest:     elements[i].(*ast.SplatPattern),
+			Post:     elements[i+1:],
+		}
+	case len(splats) == 2 && splats[0] == 0 && splats[1] == len(elements)-1 && len(elements) > 2:
+		return &ast.FindPattern{
+			Token:    start,
+			EndToken: end,
+			Constant: constant,
+			Pre:      elements[0].(*ast.SplatPattern),
+			Patterns: elements[1 : len(elements)-1],
+			Post:     elements[len(elements)-1].(*ast.SplatPattern),
+		}
+	}
+	epos := p.file.Position(p.pos)
+	msg := fmt.Errorf("%s: multiple splats in array pattern", epos.String())
+	p.errors = append(p.errors, msg)
+	return nil
+}
+
+// parseHashPattern parses the hash pattern up to the token end. The current
+// token is the opening brace or parenthesis.
+func (p *parser) parseHashPattern(pattern *ast.HashPattern, end token.Type) ast.Pattern {
+	if p.trace {
+		defer un(trace(p, "parseHashPattern"))
+	}
+	p.skipNewlines()
+	if p.peekTokenIs(end) {
+		p.accept(end)
+		pattern.EndToken = p.curToken
+		return pattern
+	}
+	p.nextToken()
+	return p.parseHashPatternElements(pattern, end)
+}
+
+// parseHashPatternElements parses the pairs and the rest of a hash pattern up
+// to the token end, or without a closing token if end is token.ILLEGAL. The
+// current token is the first element.
+func (p *parser) parseHashPatternElements(pattern *ast.HashPattern, end token.Type) ast.Pattern {
+	if p.trace {
+		defer un(trace(p, "parseHashPatternElements"))
+	}
+	for {
+		switch {
+		case p.currentTokenIs(token.LABEL):
+			pair := &ast.HashPatternPair{
+				Key: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal},
+			}
+			if !p.peekTokenOneOf(patternTerminators...) {
+				p.nextToken()
+				pair.Value = p.parsePattern()
+				if pair.Value == nil {
+					return nil
+				}
+			}
+			pattern.Pairs = append(pattern.Pairs, pair)
+		case p.currentTokenIs(token.POW):
+			rest := &ast.SplatPattern{Token: p.curToken}
+			if !p.acceptOneOf(token.IDENT, token.NIL) {
+				return nil
+			}
+			if p.currentTokenIs(token.NIL) {
+				pattern.NoRest = true
+				break
+			}
+			rest.Name = p.parseIdentifier().(*ast.Identifier)
+			pattern.Rest = rest
+		default:
+			p.expectError(token.LABEL, token.POW)
+			return nil
+		}
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.accept(token.COMMA)
+		p.skipNewlines()
+		p.nextToken()
+	}
+	if end == token.ILLEGAL {
+		return pattern
+	}
+	p.skipNewlines()
+	if !p.accept(end) {
+		return nil
+	}
+	pattern.EndToken = p.curToken
+	return pattern
+}
+
+// skipNewlines moves behind any newlines following the current token
+func (p *parser) skipNewlines() {
+	for p.peekTokenIs(token.NEWLINE) {
+		p.nextToken()
+	}
+}
+
 func (p *parser) parseTenaryIfExpression(condition ast.Expression) ast.Expression {
 	if p.trace {
 		defer un(trace(p, "parseTenaryIfExpression"))
@@ -969,14 +2102,16 @@ func (p *parser) parseModifierConditionalExpression(left ast.Expression) ast.Exp
 	if p.trace {
 		defer un(trace(p, "parseModifierConditionalExpression"))
 	}
+	return p.parseModifierConditionalStatement(&ast.ExpressionStatement{Expression: left})
+}
+
+func (p *parser) parseModifierConditionalStatement(stmt ast.Statement) ast.Expression {
 	expression := &ast.ConditionalExpression{Token: p.curToken}
 	p.nextToken()
 	expression.Condition = p.parseExpression(precLowest)
 
 	expression.Consequence = &ast.BlockStatement{
-		Statements: []ast.Statement{
-			&ast.ExpressionStatement{Expression: left},
-		},
+		Statements: []ast.Statement{stmt},
 	}
 	return expression
 }
@@ -992,10 +2127,116 @@ func (p *parser) parseLoopExpression() ast.Expression {
 		p.accept(token.DO)
 	}
 	loop.Block = p.parseBlockStatement(token.END)
+	if !p.accept(token.END) {
+		return nil
+	}
+	loop.EndToken = p.curToken
+	return loop
+}
+
+func (p *parser) parseModifierLoopExpression(left ast.Expression) ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseModifierLoopExpression"))
+	}
+	loop := &ast.LoopExpression{Token: p.curToken}
 	p.nextToken()
+	loop.Condition = p.parseExpression(precIfUnless)
+	if begin, ok := left.(*ast.ExceptionHandlingBlock); ok {
+		loop.DoWhile = !begin.IsModifier()
+	}
+	loop.Block = &ast.BlockStatement{
+		Statements: []ast.Statement{
+			&ast.ExpressionStatement{Expression: left},
+		},
+	}
 	return loop
 }
 
+func (p *parser) parseJumpExpression() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseJumpExpression"))
+	}
+	jump := &ast.JumpExpression{Token: p.curToken}
+	if p.currentTokenOneOf(token.REDO, token.RETRY) {
+		return jump
+	}
+	if p.peekTokenOneOf(token.NEWLINE, token.SEMICOLON, token.EOF, token.END, token.RBRACE, token.IF, token.UNLESS, token.WHILE, token.UNTIL) {
+		return jump
+	}
+	p.nextToken()
+	jump.Value = p.parseExpression(precIfUnless)
+	if list, ok := jump.Value.(ast.ExpressionList); ok {
+		jump.Value = &ast.ArrayLiteral{Elements: list}
+	}
+	return jump
+}
+
+func (p *parser) parseAlias() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseAlias"))
+	}
+	alias := &ast.AliasExpression{Token: p.curToken}
+	if p.peekTokenIs(token.GLOBAL) {
+		p.nextToken()
+		alias.New = &ast.Global{Token: p.curToken, Value: p.curToken.Literal}
+		if !p.accept(token.GLOBAL) {
+			return nil
+		}
+		alias.Old = &ast.Global{Token: p.curToken, Value: p.curToken.Literal}
+		return alias
+	}
+	newName := p.parseMethodReference()
+	if newName == nil {
+		return nil
+	}
+	oldName := p.parseMethodReference()
+	if oldName == nil {
+		return nil
+	}
+	alias.New, alias.Old = newName, oldName
+	return alias
+}
+
+func (p *parser) parseUndef() ast.Expression {
+	if p.trace {
+		defer un(trace(p, "parseUndef"))
+	}
+	undef := &ast.UndefExpression{Token: p.curToken}
+	for {
+		name := p.parseMethodReference()
+		if name == nil {
+			return nil
+		}
+		undef.Methods = append(undef.Methods, name)
+		if !p.peekTokenIs(token.COMMA) {
+			return undef
+		}
+		p.nextToken()
+	}
+}
+
+// parseMethodReference parses the peek token as the name of an existing
+// method, as used by alias and undef. The name is either given as in a method
+// definition or as a symbol.
+func (p *parser) parseMethodReference() *ast.Identifier {
+	if p.trace {
+		defer un(trace(p, "parseMethodReference"))
+	}
+	if p.peekTokenIs(token.SYMBEG) {
+		p.nextToken()
+		if !p.acceptOneOf(token.IDENT, token.CONST) {
+			return nil
+		}
+		return p.parseMethodName()
+	}
+	if !p.peekTokenOneOf(token.IDENT, token.CONST, token.LBRACKET) && !p.peekToken.Type.IsOperator() {
+		p.peekError(token.IDENT, token.CONST, token.SYMBEG)
+		return nil
+	}
+	p.nextToken()
+	return p.parseMethodName()
+}
+
 func (p *parser) parseModule() ast.Expression {
 	if p.trace {
 		defer un(trace(p, "parseModule"))
@@ -1053,28 +2294,24 @@ func (p *parser) parseFunctionLiteral() ast.Expression {
 	}
 	lit := &ast.FunctionLiteral{Token: p.curToken}
 
-	if !p.peekTokenOneOf(token.IDENT, token.SELF, token.CONST) && !p.peekToken.Type.IsOperator() {
+	if !p.peekTokenOneOf(token.IDENT, token.SELF, token.CONST, token.LBRACKET) && !p.peekToken.Type.IsOperator() {
 		p.peekError(token.IDENT, token.CONST)
 		return nil
 	}
+	p.nextToken()
 
-	if p.peekTokenOneOf(token.IDENT, token.SELF, token.CONST) {
-		p.acceptOneOf(token.IDENT, token.SELF, token.CONST)
-		if p.peekTokenIs(token.DOT) {
-			lit.Receiver = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-			p.accept(token.DOT)
-			if !p.peekTokenOneOf(token.IDENT, token.SELF, token.CONST) && !p.peekToken.Type.IsOperator() {
-				p.peekError(token.IDENT, token.CONST)
-				return nil
-			}
-			p.nextToken()
-			lit.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-		} else {
-			lit.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if p.currentTokenOneOf(token.IDENT, token.SELF, token.CONST) && p.peekTokenIs(token.DOT) {
+		lit.Receiver = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		p.accept(token.DOT)
+		if !p.peekTokenOneOf(token.IDENT, token.SELF, token.CONST, token.LBRACKET) && !p.peekToken.Type.IsOperator() {
+			p.peekError(token.IDENT, token.CONST)
+			return nil
 		}
-	} else {
 		p.nextToken()
-		lit.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+	lit.Name = p.parseMethodName()
+	if lit.Name == nil {
+		return nil
 	}
 
 	lit.Parameters = p.parseParameters(token.LPAREN, token.RPAREN)
@@ -1093,13 +2330,12 @@ func (p *parser) parseFunctionLiteral() ast.Expression {
 	if !p.acceptOneOf(token.NEWLINE, token.SEMICOLON) {
 		return nil
 	}
-	lit.Body = p.parseBlockStatement(token.END, token.RESCUE)
-	lit.Rescues = []*ast.RescueBlock{}
-	for p.peekTokenIs(token.RESCUE) {
-		p.accept(token.RESCUE)
-		rescue := p.parseRescueBlock()
-		lit.Rescues = append(lit.Rescues, rescue)
+	lit.Body = p.parseBlockStatement(token.RESCUE, token.ELSE, token.ENSURE)
+	clauses := &ast.ExceptionHandlingBlock{}
+	if !p.parseExceptionHandlingClauses(clauses) {
+		return nil
 	}
+	lit.Rescues, lit.Else, lit.Ensure = clauses.Rescues, clauses.Else, clauses.Ensure
 	if !p.accept(token.END) {
 		return nil
 	}
@@ -1129,6 +2365,89 @@ func (p *parser) parseFunctionLiteral() ast.Expression {
 	return lit
 }
 
+// peekTokenIsSign reports whether the `+` or `-` peek token is the sign of an
+// argument, like in `x.y -1`, rather than a binary operator like in `x.y - 1`
+// or `x.y-1`.
+func (p *parser) peekTokenIsSign() bool {
+	pos := p.peekToken.Pos
+	if pos == 0 || pos+1 >= len(p.src) {
+		return false
+	}
+	return isSpace(p.src[pos-1]) && !isSpace(p.src[pos+1])
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t'
+}
+
+// parseMethodName parses the name of a method definition. Besides plain
+// identifiers this can be a setter like `name=`, an operator, one of the unary
+// operators `-@` and `+@`, or one of the index operators `[]` and `[]=`.
+func (p *parser) parseMethodName() *ast.Identifier {
+	if p.trace {
+		defer un(trace(p, "parseMethodName"))
+	}
+	name := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	switch {
+	case p.currentTokenIs(token.LBRACKET):
+		if !p.accept(token.RBRACKET) {
+			return nil
+		}
+		name.Value = "[]"
+		if p.peekTokenIs(token.ASSIGN) && p.peekToken.Pos == name.End() {
+			p.nextToken()
+			name.Value = "[]="
+		}
+	case p.currentTokenOneOf(token.IDENT, token.CONST):
+		if p.peekTokenIs(token.ASSIGN) && p.peekToken.Pos == name.End() {
+			p.nextToken()
+			name.Value += "="
+		}
+	case p.currentTokenOneOf(token.PLUS, token.MINUS, token.BANG, token.TILDE):
+		if p.peekTokenIs(token.AT) && p.peekToken.Pos == name.End() {
+			p.nextToken()
+			// `!@` and `~@` define the same methods as `!` and `~`
+			if name.Token.Type == token.PLUS || name.Token.Type == token.MINUS {
+				name.Value += "@"
+			}
+		}
+	}
+	return name
+}
+
+func (p *parser) parseParameter(endToken token.Type, defaultPrecedence int) *ast.FunctionParameter {
+	if p.trace {
+		defer un(trace(p, "parseParameter"))
+	}
+	param := &ast.FunctionParameter{}
+	switch {
+	case p.peekTokenIs(token.ASTERISK):
+		p.accept(token.ASTERISK)
+		param.Kind = ast.SplatParameter
+	case p.peekTokenIs(token.POW):
+		p.accept(token.POW)
+		param.Kind = ast.DoubleSplatParameter
+	case p.peekTokenIs(token.LABEL):
+		p.accept(token.LABEL)
+		param.Kind = ast.KeywordParameter
+		param.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		if !p.peekTokenOneOf(token.COMMA, token.NEWLINE, token.SEMICOLON, endToken) {
+			p.nextToken()
+			param.Default = p.parseExpression(defaultPrecedence)
+		}
+		return param
+	}
+	if !p.accept(token.IDENT) {
+		return nil
+	}
+	param.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if param.Kind == ast.PositionalParameter && p.peekTokenIs(token.ASSIGN) {
+		p.consume(token.ASSIGN)
+		param.Default = p.parseExpression(defaultPrecedence)
+	}
+	return param
+}
+
 func (p *parser) parseParameters(startToken, endToken token.Type) []*ast.FunctionParameter {
 	if p.trace {
 		defer un(trace(p, "parseParameters"))
@@ -1155,36 +2474,25 @@ func (p *parser) parseParameters(startToken, endToken token.Type) []*ast.Functio
 		return identifiers
 	}
 
-	if p.peekTokenIs(token.ASTERISK) {
-		p.accept(token.ASTERISK)
-	}
 	if p.peekTokenOneOf(token.CAPTURE, token.AND) {
 		p.acceptOneOf(token.CAPTURE, token.AND)
 		return identifiers
 	}
-	p.accept(token.IDENT)
-
-	ident := &ast.FunctionParameter{Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
-	if p.peekTokenIs(token.ASSIGN) {
-		p.consume(token.ASSIGN)
-		ident.Default = p.parseExpression(precAssignment)
+	ident := p.parseParameter(endToken, precAssignment)
+	if ident == nil {
+		return nil
 	}
 	identifiers = append(identifiers, ident)
 
 	for p.peekTokenIs(token.COMMA) {
 		p.accept(token.COMMA)
-		if p.peekTokenIs(token.ASTERISK) {
-			p.accept(token.ASTERISK)
-		}
 		if p.peekTokenOneOf(token.CAPTURE, token.AND) {
 			p.acceptOneOf(token.CAPTURE, token.AND)
 			return identifiers
 		}
-		p.accept(token.IDENT)
-		ident := &ast.FunctionParameter{Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
-		if p.peekTokenIs(token.ASSIGN) {
-			p.consume(token.ASSIGN)
-			ident.Default = p.parseExpression(precPrefix)
+		ident := p.parseParameter(endToken, precPrefix)
+		if ident == nil {
+			return nil
 		}
 		identifiers = append(identifiers, ident)
 	}
@@ -1237,6 +2545,22 @@ func (p *parser) parseMethodCall(context ast.Expression) ast.Expression {
 
 	p.nextToken()
 
+	// `proc.(args)` is a shorthand for `proc.call(args)`
+	if p.currentTokenIs(token.LPAREN) {
+		contextCallExpression.Function = &ast.Identifier{Token: p.curToken, Value: "call"}
+		p.nextToken()
+		contextCallExpression.Arguments = p.parseExpressionList(token.RPAREN)
+		if !p.currentTokenIs(token.RPAREN) {
+			p.peekError(token.RPAREN)
+			return nil
+		}
+		if p.peekTokenOneOf(token.LBRACE, token.DO) {
+			p.acceptOneOf(token.LBRACE, token.DO)
+			contextCallExpression.Block = p.parseBlock().(*ast.BlockExpression)
+		}
+		return contextCallExpression
+	}
+
 	if !p.currentTokenOneOf(token.IDENT, token.CLASS) && !p.curToken.Type.IsOperator() {
 		p.expectError(token.IDENT, token.CLASS)
 		return nil
@@ -1245,7 +2569,7 @@ func (p *parser) parseMethodCall(context ast.Expression) ast.Expression {
 	function := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 	contextCallExpression.Function = function
 
-	if p.peekTokenOneOf(token.SEMICOLON, token.NEWLINE, token.EOF, token.DOT, token.SCOPE) {
+	if p.peekTokenOneOf(token.SEMICOLON, token.NEWLINE, token.EOF, token.DOT, token.ANDDOT, token.SCOPE) {
 		contextCallExpression.Arguments = []ast.Expression{}
 		return contextCallExpression
 	}
@@ -1254,6 +2578,10 @@ func (p *parser) parseMethodCall(context ast.Expression) ast.Expression {
 		p.accept(token.LPAREN)
 		p.nextToken()
 		contextCallExpression.Arguments = p.parseExpressionList(token.RPAREN)
+		if !p.currentTokenIs(token.RPAREN) {
+			p.peekError(token.RPAREN)
+			return nil
+		}
 		if p.peekTokenOneOf(token.LBRACE, token.DO) {
 			p.acceptOneOf(token.LBRACE, token.DO)
 			contextCallExpression.Block = p.parseBlock().(*ast.BlockExpression)
@@ -1261,7 +2589,16 @@ func (p *parser) parseMethodCall(context ast.Expression) ast.Expression {
 		return contextCallExpression
 	}
 
-	if p.peekTokenOneOf(append(tokensNotPossibleInCallArgs, token.RBRACE)...) {
+	if p.peekTokenOneOf(append(tokensNotPossibleInCallArgs, token.RBRACE)...) || p.peekToken.Type.IsAssignOperator() {
+		return contextCallExpression
+	}
+
+	if p.peekTokenOneOf(token.PLUS, token.MINUS) && !p.peekTokenIsSign() {
+		return contextCallExpression
+	}
+
+	// a bracket right after the method name indexes the result, i.e. `foo.bar[1]`
+	if p.peekTokenIs(token.LBRACKET) && p.peekToken.Pos == p.curToken.Pos+len(p.curToken.Literal) {
 		return contextCallExpression
 	}
 
@@ -1298,7 +2635,7 @@ func (p *parser) parseContextCallExpression(context ast.Expression) ast.Expressi
 	ident := function.(*ast.Identifier)
 	contextCallExpression.Function = ident
 
-	if p.peekTokenOneOf(token.SEMICOLON, token.NEWLINE, token.DOT, token.SCOPE) {
+	if p.peekTokenOneOf(token.SEMICOLON, token.NEWLINE, token.DOT, token.ANDDOT, token.SCOPE) {
 		contextCallExpression.Arguments = []ast.Expression{}
 		return contextCallExpression
 	}
@@ -1307,6 +2644,10 @@ func (p *parser) parseContextCallExpression(context ast.Expression) ast.Expressi
 		p.accept(token.LPAREN)
 		p.nextToken()
 		contextCallExpression.Arguments = p.parseExpressionList(token.RPAREN)
+		if !p.currentTokenIs(token.RPAREN) {
+			p.peekError(token.RPAREN)
+			return nil
+		}
 		if p.peekTokenOneOf(token.LBRACE, token.DO) {
 			p.acceptOneOf(token.LBRACE, token.DO)
 			contextCallExpression.Block = p.parseBlock().(*ast.BlockExpression)
@@ -1314,7 +2655,7 @@ func (p *parser) parseContextCallExpression(context ast.Expression) ast.Expressi
 		return contextCallExpression
 	}
 
-	if p.peekTokenOneOf(append(tokensNotPossibleInCallArgs, token.RBRACE)...) {
+	if p.peekTokenOneOf(append(tokensNotPossibleInCallArgs, token.RBRACE)...) || p.peekToken.Type.IsAssignOperator() {
 		return contextCallExpression
 	}
 
@@ -1428,7 +2769,7 @@ func (p *parser) parseExpressionList(end ...token.Type) []ast.Expression {
 		return list
 	}
 
-	next := p.parseExpression(precIfUnless)
+	next := p.parseExpression(precAndOr)
 	if elist, ok := next.(ast.ExpressionList); ok {
 		if p.peekTokenOneOf(end...) {
 			p.acceptOneOf(end...)
@@ -123,6 +123,34 @@ func TestBlockCapture(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:  "block pass of symbol",
+			input: "map(&:upcase)",
+			result: &ast.ContextCallExpression{
+				Function: &ast.Identifier{Value: "map"},
+				Arguments: []ast.Expression{
+					&ast.BlockCapture{
+						Value: &ast.SymbolLiteral{Value: &ast.Identifier{Value: "upcase"}},
+					},
+				},
+			},
+		},
+		{
+			desc:  "block pass of method call",
+			input: "map x, &foo.bar",
+			result: &ast.ContextCallExpression{
+				Function: &ast.Identifier{Value: "map"},
+				Arguments: []ast.Expression{
+					&ast.Identifier{Value: "x"},
+					&ast.BlockCapture{
+						Value: &ast.ContextCallExpression{
+							Context:  &ast.Identifier{Value: "foo"},
+							Function: &ast.Identifier{Value: "bar"},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -462,6 +490,264 @@ func TestWhileExpression(t *testing.T) {
 	}
 }
 
+func TestLoopExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		output   string
+		negated  bool
+		modifier bool
+		doWhile  bool
+	}{
+		{"while x < y do\nx += 1\nend", "while (x < y) do x = (x + 1) end", false, false, false},
+		{"until x > y\nx += 1\nend", "until (x > y) do x = (x + 1) end", true, false, false},
+		{"x += 1 while x < 10", "x = (x + 1) while (x < 10)", false, true, false},
+		{"x = foo until done", "x = foo until done", true, true, false},
+		{"foo 3 while x", "foo(3) while x", false, true, false},
+		{"begin\nx += 1\nend while x < 10", "begin\nx = (x + 1)\nend while (x < 10)", false, true, true},
+		{"begin\nx += 1\nend until x > 10", "begin\nx = (x + 1)\nend until (x > 10)", true, true, true},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		loop, ok := stmt.Expression.(*ast.LoopExpression)
+		if !ok {
+			t.Fatalf("stmt.Expression is not *ast.LoopExpression. got=%T", stmt.Expression)
+		}
+		if loop.IsNegated() != tt.negated {
+			t.Errorf("loop.IsNegated() not %t. got=%t", tt.negated, loop.IsNegated())
+		}
+		if loop.IsModifier() != tt.modifier {
+			t.Errorf("loop.IsModifier() not %t. got=%t", tt.modifier, loop.IsModifier())
+		}
+		if loop.DoWhile != tt.doWhile {
+			t.Errorf("loop.DoWhile not %t. got=%t", tt.doWhile, loop.DoWhile)
+		}
+		if loop.String() != tt.output {
+			t.Errorf("loop.String() not %q. got=%q", tt.output, loop.String())
+		}
+	}
+}
+
+func TestJumpExpressions(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+		value  bool
+	}{
+		{"break", "break", false},
+		{"next\n", "next", false},
+		{"redo", "redo", false},
+		{"break 3", "break 3", true},
+		{"next x + 1", "next (x + 1)", true},
+		{"break 1, 2", "break [1, 2]", true},
+		{"break if x", "if x break end", false},
+		{"next foo 3 unless x", "unless x next foo(3) end", true},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		var jump *ast.JumpExpression
+		ast.Inspect(program, func(n ast.Node) bool {
+			if j, ok := n.(*ast.JumpExpression); ok && jump == nil {
+				jump = j
+			}
+			return true
+		})
+		if jump == nil {
+			t.Fatalf("Expected a *ast.JumpExpression within %q", tt.input)
+		}
+		if (jump.Value != nil) != tt.value {
+			t.Errorf("jump.Value != nil not %t. got=%T", tt.value, jump.Value)
+		}
+		if program.String() != tt.output {
+			t.Errorf("program.String() not %q. got=%q", tt.output, program.String())
+		}
+	}
+}
+
+func TestAliasExpression(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"alias foo bar", "alias foo bar"},
+		{"alias :foo :bar", "alias foo bar"},
+		{"alias foo? bar!", "alias foo? bar!"},
+		{"alias name= set_name=", "alias name= set_name="},
+		{"alias :name= :set_name=", "alias name= set_name="},
+		{"alias plus +", "alias plus +"},
+		{"alias [] fetch", "alias [] fetch"},
+		{"alias $new $old", "alias $new $old"},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		if _, ok := stmt.Expression.(*ast.AliasExpression); !ok {
+			t.Fatalf("stmt.Expression is not *ast.AliasExpression. got=%T", stmt.Expression)
+		}
+		if program.String() != tt.output {
+			t.Errorf("program.String() not %q. got=%q", tt.output, program.String())
+		}
+	}
+
+	for _, input := range []string{"alias $new old", "alias new $old", "alias foo"} {
+		_, err := parseSource(input)
+		if err == nil {
+			t.Errorf("Expected parser error for %q", input)
+		}
+	}
+}
+
+func TestUndefExpression(t *testing.T) {
+	tests := []struct {
+		input   string
+		methods []string
+	}{
+		{"undef foo", []string{"foo"}},
+		{"undef foo, :bar, baz=", []string{"foo", "bar", "baz="}},
+		{"undef ==", []string{"=="}},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		undef, ok := stmt.Expression.(*ast.UndefExpression)
+		if !ok {
+			t.Fatalf("stmt.Expression is not *ast.UndefExpression. got=%T", stmt.Expression)
+		}
+		var methods []string
+		for _, method := range undef.Methods {
+			methods = append(methods, method.Value)
+		}
+		if !reflect.DeepEqual(methods, tt.methods) {
+			t.Errorf("undef.Methods not %v. got=%v", tt.methods, methods)
+		}
+	}
+}
+
+func TestSuperExpression(t *testing.T) {
+	tests := []struct {
+		input     string
+		output    string
+		implicit  bool
+		arguments int
+	}{
+		{"super", "super", true, 0},
+		{"super()", "super()", false, 0},
+		{"super(1, x)", "super(1, x)", false, 2},
+		{"super 1, x", "super(1, x)", false, 2},
+		{"super do |x|\nx\nend", "super do|x|\nx\nend", true, 0},
+		{"super(3) { |x| x }", "super(3) {|x|\nx\n}", false, 1},
+		{"super + 1", "(super + 1)", true, 0},
+		{"super.foo", "super.foo()", true, 0},
+		{"super if x", "if x super end", true, 0},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		var super *ast.SuperExpression
+		ast.Inspect(program, func(n ast.Node) bool {
+			if s, ok := n.(*ast.SuperExpression); ok && super == nil {
+				super = s
+			}
+			return true
+		})
+		if super == nil {
+			t.Fatalf("Expected a *ast.SuperExpression within %q", tt.input)
+		}
+		if super.Implicit != tt.implicit {
+			t.Errorf("super.Implicit not %t. got=%t", tt.implicit, super.Implicit)
+		}
+		if len(super.Arguments) != tt.arguments {
+			t.Errorf("Expected %d arguments, got %d", tt.arguments, len(super.Arguments))
+		}
+		if program.String() != tt.output {
+			t.Errorf("program.String() not %q. got=%q", tt.output, program.String())
+		}
+	}
+}
+
+func TestFunctionParameterKinds(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+		kinds  []ast.ParameterKind
+	}{
+		{
+			"def foo(a, b = 1, *c, d); end",
+			"def foo(a, b = 1, *c, d)  end",
+			[]ast.ParameterKind{ast.PositionalParameter, ast.PositionalParameter, ast.SplatParameter, ast.PositionalParameter},
+		},
+		{
+			"def foo(a:, b: 2, **c); end",
+			"def foo(a:, b: 2, **c)  end",
+			[]ast.ParameterKind{ast.KeywordParameter, ast.KeywordParameter, ast.DoubleSplatParameter},
+		},
+		{
+			"def foo a, key:, &blk\nend",
+			"def foo(a, key:, &blk)  end",
+			[]ast.ParameterKind{ast.PositionalParameter, ast.KeywordParameter},
+		},
+		{
+			"def foo(*rest, key: 1, &blk); end",
+			"def foo(*rest, key: 1, &blk)  end",
+			[]ast.ParameterKind{ast.SplatParameter, ast.KeywordParameter},
+		},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		function := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+		var kinds []ast.ParameterKind
+		for _, param := range function.Parameters {
+			kinds = append(kinds, param.Kind)
+		}
+		if !reflect.DeepEqual(kinds, tt.kinds) {
+			t.Errorf("Expected parameter kinds %v, got %v", tt.kinds, kinds)
+		}
+		if program.String() != tt.output {
+			t.Errorf("program.String() not %q. got=%q", tt.output, program.String())
+		}
+	}
+}
+
+func TestSplatAndKeywordArguments(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"foo(*a)", "foo(*a)"},
+		{"foo(1, *a, **h, &blk)", "foo(1, *a, **h, &blk)"},
+		{"foo(key: 1)", `foo(":key" => "1")`},
+		{"foo x, key: 1", `foo(x, ":key" => "1")`},
+		{"x = [*a, 1]", "x = [*a, 1]"},
+		{"x = {key: 1}", `x = {":key" => "1"}`},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		if program.String() != tt.output {
+			t.Errorf("program.String() not %q. got=%q", tt.output, program.String())
+		}
+	}
+}
+
 func TestGlobalAssignment(t *testing.T) {
 	input := "$foo = 3"
 
@@ -743,6 +1029,78 @@ end
 	}
 }
 
+func TestExceptionHandlingClauses(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{
+			"begin\n2\nrescue\n3\nelse\n4\nensure\n5\nend",
+			"begin\n2\nrescue\n3\nelse\n4\nensure\n5\nend",
+		},
+		{
+			"begin; 2; ensure; 5; end",
+			"begin\n2\nensure\n5\nend",
+		},
+		{
+			"begin\nfoo\nrescue ArgumentError, TypeError then 3\nrescue => e\nretry\nend",
+			"begin\nfoo\nrescue ArgumentError, TypeError\n3\nrescue => e\nretry\nend",
+		},
+		{
+			"foo rescue 3",
+			"foo rescue 3",
+		},
+		{
+			"x = foo 1 rescue nil",
+			"foo(1) rescue nil",
+		},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		var begin *ast.ExceptionHandlingBlock
+		ast.Inspect(program, func(n ast.Node) bool {
+			if b, ok := n.(*ast.ExceptionHandlingBlock); ok && begin == nil {
+				begin = b
+			}
+			return true
+		})
+		if begin == nil {
+			t.Fatalf("Expected a *ast.ExceptionHandlingBlock within %q", tt.input)
+		}
+		if begin.String() != tt.output {
+			t.Errorf("begin.String() not %q. got=%q", tt.output, begin.String())
+		}
+	}
+}
+
+func TestFunctionLiteralExceptionHandling(t *testing.T) {
+	input := "def foo\n1\nrescue => e\n2\nelse\n3\nensure\n4\nend"
+
+	program, err := parseSource(input)
+	checkParserErrors(t, err)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	function, ok := stmt.Expression.(*ast.FunctionLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not *ast.FunctionLiteral. got=%T", stmt.Expression)
+	}
+	if len(function.Rescues) != 1 {
+		t.Fatalf("Expected 1 rescue block, got %d", len(function.Rescues))
+	}
+	if function.Rescues[0].Exception.Value != "e" {
+		t.Errorf("Expected rescue to bind e, got %s", function.Rescues[0].Exception)
+	}
+	if function.Else == nil || function.Else.String() != "3" {
+		t.Errorf("Expected else body 3, got %v", function.Else)
+	}
+	if function.Ensure == nil || function.Ensure.String() != "4" {
+		t.Errorf("Expected ensure body 4, got %v", function.Ensure)
+	}
+}
+
 func TestReturnStatements(t *testing.T) {
 	tests := []struct {
 		input         string
@@ -782,71 +1140,274 @@ func TestReturnStatements(t *testing.T) {
 	}
 }
 
-func TestParseComment(t *testing.T) {
-	t.Run("line comment newline", func(t *testing.T) {
-		tests := []struct {
-			input        string
-			commentValue string
-		}{
-			{
-				input:        "# a comment\n",
-				commentValue: " a comment",
-			},
-			{
-				input:        "# a comment",
-				commentValue: " a comment",
-			},
-			{
-				input:        "# a comment;",
-				commentValue: " a comment;",
-			},
-		}
+func TestReturnStatementTerminators(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"x.each do return 1 end", "x.each()\ndoreturn 1\nend"},
+		{"x.each do return end", "x.each()\ndoreturn \nend"},
+		{"return 1 if x", "if x return 1 end"},
+		{"return unless x", "unless x return  end"},
+		{"return 1 if true; 2", "if true return 1 end\n2"},
+	}
 
-		for _, tt := range tests {
-			program, err := parseSource(tt.input)
-			checkParserErrors(t, err)
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
 
-			if len(program.Statements) != 1 {
-				t.Fatalf(
-					"program has not enough statements. got=%d",
-					len(program.Statements),
-				)
-			}
+		if program.String() != tt.output {
+			t.Errorf("program.String() not %q. got=%q", tt.output, program.String())
+		}
+	}
+}
 
-			comment, ok := program.Statements[0].(*ast.Comment)
-			if !ok {
-				t.Logf("Expected program.Statements[0] to be %T, got %T\n", comment, program.Statements[0])
-				t.FailNow()
-			}
+func TestLambdaLiteral(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+		params int
+	}{
+		{"->(x, y) { x }", "->(x, y) {x\n}", 2},
+		{"-> x { x }", "->(x) {x\n}", 1},
+		{"-> { 1 }", "->() {1\n}", 0},
+		{"->(a, *b) do\nb\nend", "->(a, *b) dob\nend", 2},
+		{"foo ->(x) { x }", "foo(->(x) {x\n})", 1},
+	}
 
-			if comment.Value != tt.commentValue {
-				t.Logf("Expected comment value to equal %q, got %q\n", tt.commentValue, comment.Value)
-				t.Fail()
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		var lambda *ast.LambdaLiteral
+		ast.Inspect(program, func(n ast.Node) bool {
+			if l, ok := n.(*ast.LambdaLiteral); ok {
+				lambda = l
 			}
+			return true
+		})
+		if lambda == nil {
+			t.Fatalf("Expected a *ast.LambdaLiteral within %q", tt.input)
 		}
-	})
-	t.Run("inline comment", func(t *testing.T) {
-		tests := []struct {
-			input        string
-			commentValue string
-		}{
-			{
-				input:        "foo # a comment\n",
-				commentValue: " a comment",
-			},
-			{
-				input:        "foo # a comment",
-				commentValue: " a comment",
-			},
-			{
-				input:        "foo # a comment;",
-				commentValue: " a comment;",
-			},
+		if len(lambda.Block.Parameters) != tt.params {
+			t.Errorf("Expected %d parameters, got %d", tt.params, len(lambda.Block.Parameters))
 		}
+		if program.String() != tt.output {
+			t.Errorf("program.String() not %q. got=%q", tt.output, program.String())
+		}
+	}
+}
 
-		for _, tt := range tests {
-			program, err := parseSource(tt.input)
-			checkParserErrors(t, err)
+func TestCallShorthands(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"add.(1, 2)", "add.call(1, 2)"},
+		{"add.()", "add.call()"},
+		{"foo.bar[1]", "(foo.bar()[1])"},
+		{"foo.bar [1]", "foo.bar([1])"},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		if program.String() != tt.output {
+			t.Errorf("program.String() not %q. got=%q", tt.output, program.String())
+		}
+	}
+
+	t.Run("unclosed call shorthand", func(t *testing.T) {
+		_, err := parseSource("add.(1")
+		if err == nil {
+			t.Fatalf("Expected parser error for %q", "add.(1")
+		}
+		if !strings.Contains(err.Error(), "expecting )") {
+			t.Errorf("Expected error to contain %q, got %q", "expecting )", err.Error())
+		}
+	})
+}
+
+func TestSafeNavigation(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"user&.address&.city", "user&.address()&.city()"},
+		{"user&.name(1)", "user&.name(1)"},
+		{"self&.name", "self&.name()"},
+		{"x&.each { |a| a }", "x&.each()\n{|a|\na\n}"},
+		{"user&.name = 1", "(user&.name()) = 1"},
+		{"user.name = 1", "(user.name()) = 1"},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		if program.String() != tt.output {
+			t.Errorf("program.String() not %q. got=%q", tt.output, program.String())
+		}
+	}
+
+	_, err := parseSource("user.name(1) = 2")
+	if err == nil {
+		t.Errorf("Expected an error assigning to a call with arguments")
+	}
+}
+
+func TestPercentLiterals(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"%w(a b  c)", "[a, b, c]"},
+		{"%w[]", "[]"},
+		{"%i<a b>", "[:a, :b]"},
+		{"%W(a#{x} b)", `["a#{x}", b]`},
+		{"%q(a (b))", "a (b)"},
+		{"%s(sym)", ":sym"},
+		{"puts %w(a b)", "puts([a, b])"},
+		{"x % 2", "(x % 2)"},
+		{"x %2", "(x % 2)"},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		if program.String() != tt.output {
+			t.Errorf("program.String() not %q. got=%q", tt.output, program.String())
+		}
+	}
+
+	_, err := parseSource("%w(a b")
+	if err == nil {
+		t.Errorf("Expected an error for an unterminated percent literal")
+	}
+}
+
+func TestMethodCallOperatorArguments(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"x.y - 1", "(x.y() - 1)"},
+		{"x.y-1", "(x.y() - 1)"},
+		{"x.y -1", "x.y((-1))"},
+		{"x.y + 1", "(x.y() + 1)"},
+		{"x.y +1", "x.y((+1))"},
+		{"x.y && z", "(x.y() && z)"},
+		{"x.y || z", "(x.y() || z)"},
+		{"puts !x", "puts((!x))"},
+		{"puts ~5", "puts((~5))"},
+		{"+x", "(+x)"},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		if program.String() != tt.output {
+			t.Errorf("program.String() not %q. got=%q", tt.output, program.String())
+		}
+	}
+}
+
+func TestCompoundAssignment(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"x ||= 1", "x ||= 1"},
+		{"@x &&= y + 1", "@x &&= (y + 1)"},
+		{"$x ||= 1", "$x ||= 1"},
+		{"h[k] ||= []", "(h[k]) ||= []"},
+		{"obj.x ||= 1", "(obj.x()) ||= 1"},
+		{"obj.x += 1", "(obj.x()) = (obj.x() + 1)"},
+		{"x **= 2", "x = (x ** 2)"},
+		{"x <<= 2", "x = (x << 2)"},
+		{"x |= 2", "x = (x | 2)"},
+		{"x &= 2", "x = (x & 2)"},
+		{"x ||= 1 if y", "if y x ||= 1 end"},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		if program.String() != tt.output {
+			t.Errorf("program.String() not %q. got=%q", tt.output, program.String())
+		}
+	}
+}
+
+func TestParseComment(t *testing.T) {
+	t.Run("line comment newline", func(t *testing.T) {
+		tests := []struct {
+			input        string
+			commentValue string
+		}{
+			{
+				input:        "# a comment\n",
+				commentValue: " a comment",
+			},
+			{
+				input:        "# a comment",
+				commentValue: " a comment",
+			},
+			{
+				input:        "# a comment;",
+				commentValue: " a comment;",
+			},
+		}
+
+		for _, tt := range tests {
+			program, err := parseSource(tt.input)
+			checkParserErrors(t, err)
+
+			if len(program.Statements) != 1 {
+				t.Fatalf(
+					"program has not enough statements. got=%d",
+					len(program.Statements),
+				)
+			}
+
+			comment, ok := program.Statements[0].(*ast.Comment)
+			if !ok {
+				t.Logf("Expected program.Statements[0] to be %T, got %T\n", comment, program.Statements[0])
+				t.FailNow()
+			}
+
+			if comment.Value != tt.commentValue {
+				t.Logf("Expected comment value to equal %q, got %q\n", tt.commentValue, comment.Value)
+				t.Fail()
+			}
+		}
+	})
+	t.Run("inline comment", func(t *testing.T) {
+		tests := []struct {
+			input        string
+			commentValue string
+		}{
+			{
+				input:        "foo # a comment\n",
+				commentValue: " a comment",
+			},
+			{
+				input:        "foo # a comment",
+				commentValue: " a comment",
+			},
+			{
+				input:        "foo # a comment;",
+				commentValue: " a comment;",
+			},
+		}
+
+		for _, tt := range tests {
+			program, err := parseSource(tt.input)
+			checkParserErrors(t, err)
 
 			if len(program.Statements) != 2 {
 				t.Fatalf(
@@ -1083,6 +1644,128 @@ func TestKeyword__FILE__(t *testing.T) {
 	})
 }
 
+func TestKeyword__LINE__(t *testing.T) {
+	tests := []struct {
+		input string
+		line  int
+	}{
+		{"__LINE__", 1},
+		{"x = 1\n\n__LINE__", 3},
+		{"x = <<EOS\na\nb\nEOS\n__LINE__", 5},
+		{"puts 'a\nb', __LINE__", 2},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		var line *ast.Keyword__LINE__
+		ast.Inspect(program, func(n ast.Node) bool {
+			if l, ok := n.(*ast.Keyword__LINE__); ok {
+				line = l
+			}
+			return true
+		})
+		if line == nil {
+			t.Fatalf("Expected a *ast.Keyword__LINE__ within %q", tt.input)
+		}
+		if line.Line != tt.line {
+			t.Errorf("line.Line not %d for %q. got=%d", tt.line, tt.input, line.Line)
+		}
+	}
+
+	_, err := parseSource("__LINE__ = 42;")
+	if err == nil || err.errors[0].Error() != "1:9: Can't assign to __LINE__" {
+		t.Errorf("Expected assignment error, got %v", err)
+	}
+}
+
+func TestKeyword__DIR__(t *testing.T) {
+	program, err := ParseFile(gotoken.NewFileSet(), "dir/a_filename.rb", "__dir__", 0)
+	checkParserErrors(t, err)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	dir, ok := stmt.Expression.(*ast.Keyword__DIR__)
+	if !ok {
+		t.Fatalf("expression not *ast.Keyword__DIR__. got=%T", stmt.Expression)
+	}
+	if dir.Filename != "dir/a_filename.rb" {
+		t.Errorf("dir.Filename not %q. got=%q", "dir/a_filename.rb", dir.Filename)
+	}
+}
+
+func TestProgramHooks(t *testing.T) {
+	program, err := parseSource("x\nBEGIN { a; b }\nEND {\nc\n}\ny")
+	checkParserErrors(t, err)
+
+	if len(program.Statements) != 4 {
+		t.Fatalf("program.Statements does not contain 4 statements. got=%d", len(program.Statements))
+	}
+	tests := []struct {
+		isBegin    bool
+		statements int
+	}{
+		{true, 2},
+		{false, 1},
+	}
+	for i, tt := range tests {
+		hook, ok := program.Statements[i+1].(*ast.ProgramHook)
+		if !ok {
+			t.Fatalf("program.Statements[%d] is not *ast.ProgramHook. got=%T", i+1, program.Statements[i+1])
+		}
+		if hook.IsBegin() != tt.isBegin {
+			t.Errorf("hook.IsBegin() not %t. got=%t", tt.isBegin, hook.IsBegin())
+		}
+		if len(hook.Body.Statements) != tt.statements {
+			t.Errorf("hook.Body.Statements does not contain %d statements. got=%d", tt.statements, len(hook.Body.Statements))
+		}
+	}
+
+	for _, input := range []string{"def foo\nBEGIN { x }\nend", "if x\nEND { y }\nend", "BEGIN x"} {
+		_, err := parseSource(input)
+		if err == nil {
+			t.Errorf("Expected parser error for %q", input)
+		}
+	}
+}
+
+func TestDataSection(t *testing.T) {
+	tests := []struct {
+		input      string
+		statements int
+		data       interface{}
+	}{
+		{"x\n__END__\nfoo\nbar\n", 1, "foo\nbar\n"},
+		{"x\r\n__END__\r\nfoo", 1, "foo"},
+		{"__END__", 0, ""},
+		{"x\n__END__ \n", 2, nil},
+		{"x = __END__\n", 1, nil},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		if len(program.Statements) != tt.statements {
+			t.Errorf("program.Statements does not contain %d statements for %q. got=%d", tt.statements, tt.input, len(program.Statements))
+		}
+		data, ok := tt.data.(string)
+		if !ok {
+			if program.Data != nil {
+				t.Errorf("Expected no data for %q, got %q", tt.input, *program.Data)
+			}
+			continue
+		}
+		if program.Data == nil {
+			t.Errorf("Expected data %q for %q, got nil", data, tt.input)
+			continue
+		}
+		if *program.Data != data {
+			t.Errorf("program.Data not %q. got=%q", data, *program.Data)
+		}
+	}
+}
+
 func TestYieldExpression(t *testing.T) {
 	tests := []struct {
 		input         string
@@ -1107,73 +1790,361 @@ func TestYieldExpression(t *testing.T) {
 		program, err := parseSource(tt.input)
 		checkParserErrors(t, err)
 
-		if len(program.Statements) != 1 {
-			t.Fatalf(
-				"program has not enough statements. got=%d",
-				len(program.Statements),
-			)
-		}
-		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
-		if !ok {
-			t.Fatalf(
-				"program.Statements[0] is not ast.ExpressionStatement. got=%T",
-				program.Statements[0],
-			)
-		}
-
-		yield, ok := stmt.Expression.(*ast.YieldExpression)
+		if len(program.Statements) != 1 {
+			t.Fatalf(
+				"program has not enough statements. got=%d",
+				len(program.Statements),
+			)
+		}
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf(
+				"program.Statements[0] is not ast.ExpressionStatement. got=%T",
+				program.Statements[0],
+			)
+		}
+
+		yield, ok := stmt.Expression.(*ast.YieldExpression)
+		if !ok {
+			t.Fatalf("expression not *ast.YieldExpression. got=%T", stmt.Expression)
+		}
+
+		if len(yield.Arguments) != len(tt.expectedArgs) {
+			t.Logf("Expected %d arguments, got %d", len(tt.expectedArgs), len(yield.Arguments))
+			t.Fail()
+		}
+
+		actualArgs := make([]string, len(yield.Arguments))
+		for i, arg := range yield.Arguments {
+			actualArgs[i] = arg.String()
+		}
+
+		if !reflect.DeepEqual(tt.expectedArgs, actualArgs) {
+			t.Logf("Expected arguments to equal\n%v\n\tgot\n%v\n", tt.expectedArgs, actualArgs)
+			t.Fail()
+		}
+	}
+}
+
+func TestIntegerLiteralExpression(t *testing.T) {
+	input := "5;"
+
+	program, err := parseSource(input)
+	checkParserErrors(t, err)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf(
+			"program has not enough statements. got=%d",
+			len(program.Statements),
+		)
+	}
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf(
+			"program.Statements[0] is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+
+	literal, ok := stmt.Expression.(*ast.IntegerLiteral)
+	if !ok {
+		t.Fatalf("expression not *ast.IntegerLiteral. got=%T", stmt.Expression)
+	}
+	if literal.Value != 5 {
+		t.Errorf("expression.Value not %d. got=%d", 5, literal.Value)
+	}
+	if literal.TokenLiteral() != "5" {
+		t.Errorf(
+			"expression.TokenLiteral not %s. got=%s", "5",
+			literal.TokenLiteral(),
+		)
+	}
+}
+
+func TestIntegerLiteralNotations(t *testing.T) {
+	tests := []struct {
+		input string
+		value int64
+	}{
+		{"1_000_000", 1000000},
+		{"0x1F", 31},
+		{"0XfF", 255},
+		{"0o17", 15},
+		{"0O17", 15},
+		{"017", 15},
+		{"0_17", 15},
+		{"0b1010", 10},
+		{"0B1_0", 2},
+		{"0d170", 170},
+		{"0D1_7", 17},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		literal, ok := stmt.Expression.(*ast.IntegerLiteral)
+		if !ok {
+			t.Fatalf("expression not *ast.IntegerLiteral. got=%T", stmt.Expression)
+		}
+		if literal.Value != tt.value {
+			t.Errorf("expression.Value not %d. got=%d", tt.value, literal.Value)
+		}
+		if literal.TokenLiteral() != tt.input {
+			t.Errorf("expression.TokenLiteral not %s. got=%s", tt.input, literal.TokenLiteral())
+		}
+	}
+}
+
+func TestFloatLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input string
+		value float64
+	}{
+		{"1.5", 1.5},
+		{"1_000.25", 1000.25},
+		{"1e-3", 0.001},
+		{"2.5E2", 250},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		literal, ok := stmt.Expression.(*ast.FloatLiteral)
+		if !ok {
+			t.Fatalf("expression not *ast.FloatLiteral. got=%T", stmt.Expression)
+		}
+		if literal.Value != tt.value {
+			t.Errorf("expression.Value not %f. got=%f", tt.value, literal.Value)
+		}
+		if literal.TokenLiteral() != tt.input {
+			t.Errorf("expression.TokenLiteral not %s. got=%s", tt.input, literal.TokenLiteral())
+		}
+	}
+}
+
+func TestRangeLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input     string
+		output    string
+		exclusive bool
+	}{
+		{"1..5", "(1..5)", false},
+		{"1...5", "(1...5)", true},
+		{"x..y + 1", "(x..(y + 1))", false},
+		{"1..", "(1..)", false},
+		{"..5", "(..5)", false},
+		{"...5", "(...5)", true},
+		{"a || b..c", "((a || b)..c)", false},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		rng, ok := stmt.Expression.(*ast.RangeLiteral)
+		if !ok {
+			t.Fatalf("expression not *ast.RangeLiteral. got=%T", stmt.Expression)
+		}
+		if rng.Exclusive != tt.exclusive {
+			t.Errorf("expression.Exclusive not %t. got=%t", tt.exclusive, rng.Exclusive)
+		}
+		if rng.String() != tt.output {
+			t.Errorf("expression.String() not %q. got=%q", tt.output, rng.String())
+		}
+	}
+}
+
+func TestRegexLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+		flags  string
+	}{
+		{"/ab+c/", "/ab+c/", ""},
+		{"/a#{b}c/mix", "/a#{b}c/mix", "mix"},
+		{`/\d+\/\w/i`, `/\d+/\w/i`, "i"},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		regex, ok := stmt.Expression.(*ast.RegexLiteral)
+		if !ok {
+			t.Fatalf("expression not *ast.RegexLiteral. got=%T", stmt.Expression)
+		}
+		if regex.Flags != tt.flags {
+			t.Errorf("expression.Flags not %q. got=%q", tt.flags, regex.Flags)
+		}
+		if regex.String() != tt.output {
+			t.Errorf("expression.String() not %q. got=%q", tt.output, regex.String())
+		}
+	}
+}
+
+func TestParsingMatchExpressions(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"x =~ /a/", "(x =~ /a/)"},
+		{"x !~ /a/", "(x !~ /a/)"},
+		{"a = x =~ /a/ && y", "a = ((x =~ /a/) && y)"},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		if program.String() != tt.output {
+			t.Errorf("program.String() not %q. got=%q", tt.output, program.String())
+		}
+	}
+}
+
+func TestCaseExpression(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+		whens  int
+	}{
+		{
+			"case x\nwhen 1 then :a\nwhen 2, 3\n:b\nelse\n:c\nend",
+			"case x when 1 then :a when 2, 3 then :b else :c end",
+			2,
+		},
+		{
+			"case\nwhen x > 1; :a\nend",
+			"case when (x > 1) then :a end",
+			1,
+		},
+		{
+			"case foo.bar\nwhen String,\n  Symbol then x.y\nend",
+			"case foo.bar() when String, Symbol then x.y() end",
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		exp, ok := stmt.Expression.(*ast.CaseExpression)
+		if !ok {
+			t.Fatalf("expression not *ast.CaseExpression. got=%T", stmt.Expression)
+		}
+		if len(exp.Whens) != tt.whens {
+			t.Errorf("expression.Whens does not contain %d clauses. got=%d", tt.whens, len(exp.Whens))
+		}
+		if exp.String() != tt.output {
+			t.Errorf("expression.String() not %q. got=%q", tt.output, exp.String())
+		}
+	}
+}
+
+func TestCaseMatchExpression(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+		ins    int
+	}{
+		{
+			"case x\nin 1 | 2 then :a\nin Integer => n if n > 2\n:b\nelse\n:c\nend",
+			"case x in 1 | 2 then :a in Integer => n if (n > 2) then :b else :c end",
+			2,
+		},
+		{
+			"case x\nin [1, *rest, ^y]\n:a\nin [*, :x, *post]; :b\nin a, b then :c\nend",
+			"case x in [1, *rest, ^y] then :a in [*, :x, *post] then :b in [a, b] then :c end",
+			3,
+		},
+		{
+			"case x\nin {name: String => name, **rest} then :a\nin a:, b: 1, **nil then :b\nin {} then :c\nend",
+			"case x in {name: String => name, **rest} then :a in {a:, b: 1, **nil} then :b in {} then :c end",
+			3,
+		},
+		{
+			"case x\nin Point(x:, y: 0) unless x then :a\nin Point[a, b] then :b\nin Foo::Bar then :c\nend",
+			"case x in Point(x:, y: 0) unless x then :a in Point[a, b] then :b in Foo::Bar then :c end",
+			3,
+		},
+		{
+			"case x\nin 1.. then :a\nin ..0 then :b\nin ^(y + 1) then :c\nend",
+			"case x in (1..) then :a in (..0) then :b in ^(y + 1) then :c end",
+			3,
+		},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		exp, ok := stmt.Expression.(*ast.CaseMatchExpression)
+		if !ok {
+			t.Fatalf("expression not *ast.CaseMatchExpression. got=%T", stmt.Expression)
+		}
+		if len(exp.Ins) != tt.ins {
+			t.Errorf("expression.Ins does not contain %d clauses. got=%d", tt.ins, len(exp.Ins))
+		}
+		if exp.String() != tt.output {
+			t.Errorf("expression.String() not %q. got=%q", tt.output, exp.String())
+		}
+	}
+}
+
+func TestPatternMatchExpression(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+		isTest bool
+	}{
+		{"x in Integer", "x in Integer", true},
+		{"x in [a, *]", "x in [a, *]", true},
+		{"config => {db: {user:}}", "config => {db: {user:}}", false},
+		{"x => Integer | Float => n", "x => Integer | Float => n", false},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		exp, ok := stmt.Expression.(*ast.PatternMatchExpression)
 		if !ok {
-			t.Fatalf("expression not *ast.YieldExpression. got=%T", stmt.Expression)
-		}
-
-		if len(yield.Arguments) != len(tt.expectedArgs) {
-			t.Logf("Expected %d arguments, got %d", len(tt.expectedArgs), len(yield.Arguments))
-			t.Fail()
+			t.Fatalf("expression not *ast.PatternMatchExpression. got=%T", stmt.Expression)
 		}
-
-		actualArgs := make([]string, len(yield.Arguments))
-		for i, arg := range yield.Arguments {
-			actualArgs[i] = arg.String()
+		if exp.IsTest() != tt.isTest {
+			t.Errorf("expression.IsTest() not %t", tt.isTest)
 		}
-
-		if !reflect.DeepEqual(tt.expectedArgs, actualArgs) {
-			t.Logf("Expected arguments to equal\n%v\n\tgot\n%v\n", tt.expectedArgs, actualArgs)
-			t.Fail()
+		if exp.String() != tt.output {
+			t.Errorf("expression.String() not %q. got=%q", tt.output, exp.String())
 		}
 	}
-}
-
-func TestIntegerLiteralExpression(t *testing.T) {
-	input := "5;"
-
-	program, err := parseSource(input)
-	checkParserErrors(t, err)
 
-	if len(program.Statements) != 1 {
-		t.Fatalf(
-			"program has not enough statements. got=%d",
-			len(program.Statements),
-		)
-	}
-	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
-	if !ok {
-		t.Fatalf(
-			"program.Statements[0] is not ast.ExpressionStatement. got=%T",
-			program.Statements[0])
+	errorTests := []struct {
+		input   string
+		message string
+	}{
+		{"x in [*a, *b]", "multiple splats in array pattern"},
+		{"x in [a] | [b]", "illegal variable in alternative pattern (a)"},
 	}
 
-	literal, ok := stmt.Expression.(*ast.IntegerLiteral)
-	if !ok {
-		t.Fatalf("expression not *ast.IntegerLiteral. got=%T", stmt.Expression)
-	}
-	if literal.Value != 5 {
-		t.Errorf("expression.Value not %d. got=%d", 5, literal.Value)
-	}
-	if literal.TokenLiteral() != "5" {
-		t.Errorf(
-			"expression.TokenLiteral not %s. got=%s", "5",
-			literal.TokenLiteral(),
-		)
+	for _, tt := range errorTests {
+		_, err := parseSource(tt.input)
+		if err == nil {
+			t.Errorf("Expected parser error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("Expected error to contain %q, got %q", tt.message, err.Error())
+		}
 	}
 }
 
@@ -1454,6 +2425,34 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"-a * b",
 			"((-a) * b)",
 		},
+		{
+			"a ** b ** c",
+			"(a ** (b ** c))",
+		},
+		{
+			"-a ** b",
+			"(-(a ** b))",
+		},
+		{
+			"~a ** b",
+			"((~a) ** b)",
+		},
+		{
+			"a * b ** c",
+			"(a * (b ** c))",
+		},
+		{
+			"a | b ^ c & d",
+			"((a | b) ^ (c & d))",
+		},
+		{
+			"a << b + c >> d",
+			"((a << (b + c)) >> d)",
+		},
+		{
+			"a & b == c",
+			"((a & b) == c)",
+		},
 		{
 			"!-a",
 			"(!(-a))",
@@ -1586,6 +2585,38 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
 		},
+		{
+			"x = a or b",
+			"(x = a or b)",
+		},
+		{
+			"x = a || b",
+			"x = (a || b)",
+		},
+		{
+			"a and b or c and d",
+			"(((a and b) or c) and d)",
+		},
+		{
+			"not a == b and c",
+			"((not (a == b)) and c)",
+		},
+		{
+			"not a = b",
+			"(not a = b)",
+		},
+		{
+			"foo a and b",
+			"(foo(a) and b)",
+		},
+		{
+			"x = m do\n1\nend",
+			"x = (m()\ndo1\nend)",
+		},
+		{
+			"l = lambda do |x|\nx\nend",
+			"l = (lambda()\ndo|x|\nx\nend)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -1793,7 +2824,7 @@ func TestConditionalExpression(t *testing.T) {
 			y
 			end
 			x
-			end`, "x", "<", "y", "if(x == 3) y endx"},
+			end`, "x", "<", "y", "if (x == 3) y endx"},
 			{`if x < y
 			x = Object x
 			end`, "x", "<", "y", "x = (Object(x))"},
@@ -1814,7 +2845,7 @@ func TestConditionalExpression(t *testing.T) {
 			y
 			end
 			x
-			end`, "x", "<", "y", "if(x == 3) y endx"},
+			end`, "x", "<", "y", "if (x == 3) y endx"},
 			{`unless x < y
 			x = Object x
 			end`, "x", "<", "y", "x = (Object(x))"},
@@ -2012,6 +3043,72 @@ func TestConditionalExpression(t *testing.T) {
 	})
 }
 
+func TestConditionalExpressionWithElsif(t *testing.T) {
+	tests := []struct {
+		input      string
+		output     string
+		conditions []string
+	}{
+		{
+			"if x < 1\n:a\nelsif x < 2\n:b\nend",
+			"if (x < 1) :a elsif (x < 2) :b end",
+			[]string{"(x < 1)", "(x < 2)"},
+		},
+		{
+			"if x < 1 then :a\nelsif x < 2 then :b\nelsif x < 3; :c\nelse\n:d\nend",
+			"if (x < 1) :a elsif (x < 2) :b elsif (x < 3) :c else :d end",
+			[]string{"(x < 1)", "(x < 2)", "(x < 3)"},
+		},
+		{
+			"if a\nif b\n:x\nelsif c\n:y\nend\nelsif d\n:z\nend",
+			"if a if b :x elsif c :y end elsif d :z end",
+			[]string{"a", "b", "c", "d"},
+		},
+		{
+			"if a then 1 elsif b then 2 else 3 end",
+			"if a 1 elsif b 2 else 3 end",
+			[]string{"a", "b"},
+		},
+		{
+			"if a; 1; elsif b; 2; else; 3; end",
+			"if a 1 elsif b 2 else 3 end",
+			[]string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		exp, ok := stmt.Expression.(*ast.ConditionalExpression)
+		if !ok {
+			t.Fatalf("stmt.Expression is not ast.ConditionalExpression. got=%T", stmt.Expression)
+		}
+		if exp.String() != tt.output {
+			t.Errorf("expression.String() not %q. got=%q", tt.output, exp.String())
+		}
+
+		var conditions []string
+		ast.Inspect(exp, func(n ast.Node) bool {
+			if cond, ok := n.(*ast.ConditionalExpression); ok {
+				conditions = append(conditions, cond.Condition.String())
+			}
+			return true
+		})
+		if !reflect.DeepEqual(conditions, tt.conditions) {
+			t.Errorf("walked conditions not %v. got=%v", tt.conditions, conditions)
+		}
+	}
+
+	t.Run("elsif within unless", func(t *testing.T) {
+		_, err := parseSource("unless x\n:a\nelsif y\n:b\nend")
+		if err == nil {
+			t.Errorf("Expected parse error, got nil")
+		}
+	})
+}
+
 func TestConditionalExpressionWithAlternative(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -2294,6 +3391,109 @@ func TestFunctionLiteralParsing(t *testing.T) {
 			[]funcParam{},
 			"(x + y)",
 		},
+		{
+			"index operator as function name",
+			`def [](i)
+          x + y
+          end`,
+			"",
+			"[]",
+			[]funcParam{
+				{name: "i", defaultValue: nil},
+			},
+			"(x + y)",
+		},
+		{
+			"index assignment operator as function name",
+			`def []=(i, v)
+          x + y
+          end`,
+			"",
+			"[]=",
+			[]funcParam{
+				{name: "i", defaultValue: nil},
+				{name: "v", defaultValue: nil},
+			},
+			"(x + y)",
+		},
+		{
+			"setter as function name",
+			`def name=(v)
+          x + y
+          end`,
+			"",
+			"name=",
+			[]funcParam{
+				{name: "v", defaultValue: nil},
+			},
+			"(x + y)",
+		},
+		{
+			"setter on self context",
+			`def self.name=(v)
+          x + y
+          end`,
+			"self",
+			"name=",
+			[]funcParam{
+				{name: "v", defaultValue: nil},
+			},
+			"(x + y)",
+		},
+		{
+			"unary minus as function name",
+			`def -@
+          x + y
+          end`,
+			"",
+			"-@",
+			[]funcParam{},
+			"(x + y)",
+		},
+		{
+			"unary plus as function name",
+			`def +@
+          x + y
+          end`,
+			"",
+			"+@",
+			[]funcParam{},
+			"(x + y)",
+		},
+		{
+			"bang as function name",
+			`def !
+          x + y
+          end`,
+			"",
+			"!",
+			[]funcParam{},
+			"(x + y)",
+		},
+		{
+			"modulo as function name",
+			`def %(other)
+          x + y
+          end`,
+			"",
+			"%",
+			[]funcParam{
+				{name: "other", defaultValue: nil},
+			},
+			"(x + y)",
+		},
+		{
+			"divide as function name",
+			`def /(other)
+          x + y
+          end`,
+			"",
+			"/",
+			[]funcParam{
+				{name: "other", defaultValue: nil},
+			},
+			"(x + y)",
+		},
 		{
 			"function on local variable context",
 			`def a.qux
@@ -2814,6 +4014,72 @@ func TestBlockParameterParsing(t *testing.T) {
 	}
 }
 
+func TestBlockDestructuringAndLocalParameters(t *testing.T) {
+	tests := []struct {
+		input  string
+		params string
+		locals []string
+	}{
+		{"method { |a, (b, c)| }", "a, (b, c)", []string{}},
+		{"method { |(a, (b, *c)), d| }", "(a, (b, *c)), d", []string{}},
+		{"method do |(a, b)|; end", "(a, b)", []string{}},
+		{"method { |a; x, y| }", "a", []string{"x", "y"}},
+		{"method { |; x| }", "", []string{"x"}},
+		{"method do |(a, b), c; x|; end", "(a, b), c", []string{"x"}},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		call, ok := stmt.Expression.(*ast.ContextCallExpression)
+		if !ok || call.Block == nil {
+			t.Fatalf("Expected a call with block, got %T", stmt.Expression)
+		}
+
+		params := []string{}
+		for _, param := range call.Block.Parameters {
+			params = append(params, param.String())
+		}
+		if strings.Join(params, ", ") != tt.params {
+			t.Errorf("Expected params %q, got %q", tt.params, strings.Join(params, ", "))
+		}
+
+		locals := []string{}
+		for _, local := range call.Block.Locals {
+			locals = append(locals, local.Value)
+		}
+		if !reflect.DeepEqual(tt.locals, locals) {
+			t.Errorf("Expected locals %v, got %v", tt.locals, locals)
+		}
+	}
+}
+
+func TestBraceBlockPrecedence(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"puts foo { 1 }", "puts(foo()\n{1\n})"},
+		{"puts(foo { 1 }.bar)", "puts(foo()\n{1\n}.bar())"},
+		{"puts(x.map { |a| a }.first)", "puts(x.map()\n{|a|\na\n}.first())"},
+		{"x = [foo { 1 }]", "x = [foo()\n{1\n}]"},
+		{"-foo { 1 }", "(-foo()\n{1\n})"},
+		{"foo 1, 2 { 3 }", "foo(1, 2)\n{3\n}"},
+		{"foo 1, bar { 3 }", "foo(1, bar()\n{3\n})"},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		if program.String() != tt.output {
+			t.Errorf("program.String() not %q. got=%q", tt.output, program.String())
+		}
+	}
+}
+
 func TestCallExpressionParsing(t *testing.T) {
 	testCases := []struct {
 		desc        string
@@ -3837,6 +5103,42 @@ func TestStringLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestInterpolatedStringLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+		parts  int
+	}{
+		{`"foo #{bar} baz"`, `"foo #{bar} baz"`, 3},
+		{`"#{bar}"`, `"#{bar}"`, 1},
+		{`"#{}"`, `""`, 0},
+		{`"a#{x + 1}b#{y.foo}c"`, `"a#{(x + 1)}b#{y.foo()}c"`, 5},
+		{`"#{"inner #{x}"}"`, `"#{"inner #{x}"}"`, 1},
+		{`"#{a = 1; a + 1}"`, `"#{a = 1; (a + 1)}"`, 1},
+		{"\"x#{\n  a = 1\n  a + 1\n}y\"", `"x#{a = 1; (a + 1)}y"`, 3},
+		{`"a #@foo b #$bar"`, `"a #{@foo} b #{$bar}"`, 4},
+	}
+
+	for _, tt := range tests {
+		program, err := parseSource(tt.input)
+		checkParserErrors(t, err)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		literal, ok := stmt.Expression.(*ast.InterpolatedString)
+		if !ok {
+			t.Fatalf("exp not *ast.InterpolatedString. got=%T", stmt.Expression)
+		}
+
+		if len(literal.Parts) != tt.parts {
+			t.Errorf("len(literal.Parts) not %d. got=%d", tt.parts, len(literal.Parts))
+		}
+
+		if literal.String() != tt.output {
+			t.Errorf("literal.String() not %q. got=%q", tt.output, literal.String())
+		}
+	}
+}
+
 func TestSymbolExpression(t *testing.T) {
 	tests := []struct {
 		input string
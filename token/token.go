@@ -19,42 +19,62 @@ const (
 	CONST
 	GLOBAL
 	INT
+	FLOAT
 	STRING
+	LABEL // name:
 	literal_end
 
 	// Operators
 	operator_beg
 	operator_assign_beg
-	ASSIGN    // =
-	ADDASSIGN // +=
-	SUBASSIGN // -=
-	MULASSIGN // *=
-	DIVASSIGN // /=
-	MODASSIGN // %=
+	ASSIGN           // =
+	ADDASSIGN        // +=
+	SUBASSIGN        // -=
+	MULASSIGN        // *=
+	DIVASSIGN        // /=
+	MODASSIGN        // %=
+	POWASSIGN        // **=
+	LSHIFTASSIGN     // <<=
+	RSHIFTASSIGN     // >>=
+	ORASSIGN         // |=
+	ANDASSIGN        // &=
+	XORASSIGN        // ^=
+	LOGICALORASSIGN  // ||=
+	LOGICALANDASSIGN // &&=
 	operator_assign_end
 
 	PLUS       // +
 	MINUS      // -
 	BANG       // !
+	TILDE      // ~
 	ASTERISK   // *
+	POW        // **
 	SLASH      // /
 	MODULO     // %
 	AND        // &
 	LOGICALAND // &&
 	PIPE       // |
 	LOGICALOR  // ||
+	CARET      // ^
 
 	LT        // <
 	LTE       // <=
 	GT        // >
 	GTE       // >=
 	EQ        // ==
+	CASEEQ    // ===
 	NOTEQ     // !=
+	MATCH     // =~
+	NMATCH    // !~
 	SPACESHIP // <=>
 	LSHIFT    // <<
+	RSHIFT    // >>
+	DOT2      // ..
+	DOT3      // ...
 	operator_end
 
 	HASHROCKET // =>
+	LAMBDA     // ->
 
 	// Delimiters
 
@@ -65,6 +85,7 @@ const (
 
 	CAPTURE  // &
 	DOT      // .
+	ANDDOT   // &.
 	COLON    // :
 	LPAREN   // (
 	RPAREN   // )
@@ -79,15 +100,29 @@ const (
 	QMARK  // ?
 	SYMBEG // :
 
+	INTERPBEG // #{
+	INTERPEND // }
+	REGEXBEG  // /
+	REGEXEND  // /
+
+	WORDSBEG   // %w(
+	SYMBOLSBEG // %i(
+	WORDSEND   // )
+
 	// Keywords
 	keyword_beg
 	DEF
 	SELF
+	SUPER
 	END
 	IF
 	THEN
 	ELSE
+	ELSIF
 	UNLESS
+	CASE
+	WHEN
+	IN
 	TRUE
 	FALSE
 	RETURN
@@ -98,8 +133,24 @@ const (
 	YIELD
 	BEGIN
 	RESCUE
+	ENSURE
+	RETRY
 	WHILE
+	UNTIL
+	BREAK
+	NEXT
+	REDO
 	KEYWORD__FILE__
+	KEYWORD__LINE__
+	KEYWORD__DIR__
+	KEYWORD__END__
+	KEYWORD_BEGIN
+	KEYWORD_END
+	ALIAS
+	UNDEF
+	KEYWORD_AND
+	KEYWORD_OR
+	KEYWORD_NOT
 	keyword_end
 )
 
@@ -111,34 +162,53 @@ var tokens = [...]string{
 	CONST:  "CONST",
 	GLOBAL: "GLOBAL",
 	INT:    "INT",
+	FLOAT:  "FLOAT",
 	STRING: "STRING",
-
-	ASSIGN:    "=",
-	ADDASSIGN: "+=",
-	SUBASSIGN: "-=",
-	MULASSIGN: "*=",
-	DIVASSIGN: "/=",
-	MODASSIGN: "%=",
+	LABEL:  "LABEL",
+
+	ASSIGN:           "=",
+	ADDASSIGN:        "+=",
+	SUBASSIGN:        "-=",
+	MULASSIGN:        "*=",
+	DIVASSIGN:        "/=",
+	MODASSIGN:        "%=",
+	POWASSIGN:        "**=",
+	LSHIFTASSIGN:     "<<=",
+	RSHIFTASSIGN:     ">>=",
+	ORASSIGN:         "|=",
+	ANDASSIGN:        "&=",
+	XORASSIGN:        "^=",
+	LOGICALORASSIGN:  "||=",
+	LOGICALANDASSIGN: "&&=",
 
 	PLUS:       "+",
 	MINUS:      "-",
 	BANG:       "!",
+	TILDE:      "~",
 	ASTERISK:   "*",
+	POW:        "**",
 	SLASH:      "/",
 	MODULO:     "%",
 	AND:        "&",
 	CAPTURE:    "&",
 	LOGICALAND: "&&",
 	LOGICALOR:  "||",
+	CARET:      "^",
 
 	LT:        "<",
 	LTE:       "<=",
 	GT:        ">",
 	GTE:       ">=",
 	EQ:        "==",
+	CASEEQ:    "===",
 	NOTEQ:     "!=",
+	MATCH:     "=~",
+	NMATCH:    "!~",
 	SPACESHIP: "<=>",
 	LSHIFT:    "<<",
+	RSHIFT:    ">>",
+	DOT2:      "..",
+	DOT3:      "...",
 
 	NEWLINE:   "NEWLINE",
 	COMMA:     ",",
@@ -146,6 +216,7 @@ var tokens = [...]string{
 	HASH:      "#",
 
 	DOT:      ".",
+	ANDDOT:   "&.",
 	COLON:    ":",
 	LPAREN:   "(",
 	RPAREN:   ")",
@@ -157,18 +228,33 @@ var tokens = [...]string{
 
 	SCOPE:      "::",
 	HASHROCKET: "=>",
+	LAMBDA:     "->",
 	AT:         "@",
 
 	QMARK:  "?",
 	SYMBEG: ":",
 
+	INTERPBEG: "#{",
+	INTERPEND: "}",
+	REGEXBEG:  "REGEXBEG",
+	REGEXEND:  "REGEXEND",
+
+	WORDSBEG:   "WORDSBEG",
+	SYMBOLSBEG: "SYMBOLSBEG",
+	WORDSEND:   "WORDSEND",
+
 	DEF:             "def",
 	SELF:            "self",
+	SUPER:           "super",
 	END:             "end",
 	UNLESS:          "unless",
+	CASE:            "case",
+	WHEN:            "when",
+	IN:              "in",
 	IF:              "if",
 	THEN:            "then",
 	ELSE:            "else",
+	ELSIF:           "elsif",
 	TRUE:            "true",
 	FALSE:           "false",
 	RETURN:          "return",
@@ -179,8 +265,24 @@ var tokens = [...]string{
 	YIELD:           "yield",
 	BEGIN:           "begin",
 	RESCUE:          "rescue",
+	ENSURE:          "ensure",
+	RETRY:           "retry",
 	WHILE:           "while",
+	UNTIL:           "until",
+	BREAK:           "break",
+	NEXT:            "next",
+	REDO:            "redo",
 	KEYWORD__FILE__: "__FILE__",
+	KEYWORD__LINE__: "__LINE__",
+	KEYWORD__DIR__:  "__dir__",
+	KEYWORD__END__:  "__END__",
+	KEYWORD_BEGIN:   "BEGIN",
+	KEYWORD_END:     "END",
+	ALIAS:           "alias",
+	UNDEF:           "undef",
+	KEYWORD_AND:     "and",
+	KEYWORD_OR:      "or",
+	KEYWORD_NOT:     "not",
 }
 
 // String returns the string corresponding to the token tok.